@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 直接從推薦結果收藏的餐廳（只有快照座標，沒有 location 欄位）
+var savedFromResults = []gin.H{
+	{"place_id": "fav-ramen", "name": "一蘭拉麵", "rating": 4.5, "lat": 25.0380, "lng": 121.5700, "address": "收藏路 1 號"},
+	{"place_id": "fav-chicken", "name": "韓式炸雞", "rating": 4.2, "lat": 25.0300, "lng": 121.5750, "address": "收藏路 2 號"},
+	{"place_id": "fav-hotpot", "name": "老四川麻辣鍋", "rating": 4.0, "lat": 25.0400, "lng": 121.5800, "address": "收藏路 3 號"},
+}
+
+type recommendedPlaces struct {
+	Restaurants []struct {
+		PlaceID  string `json:"place_id"`
+		Distance string `json:"distance"`
+	} `json:"restaurants"`
+}
+
+// 建立匿名使用者並收藏 savedFromResults
+func newFavoritesClient(t *testing.T, env map[string]string) *specClient {
+	t.Helper()
+	client := newSpecClient(t, newTestApp(t, env).router, loadSpec(t))
+
+	var device struct {
+		Token string `json:"token"`
+	}
+	client.decode(client.do("POST", "/api/auth/device", nil, http.StatusCreated), &device)
+	client.token = device.Token
+	for _, restaurant := range savedFromResults {
+		client.do("POST", "/api/favorites", gin.H{"restaurant": restaurant}, http.StatusCreated)
+	}
+	return client
+}
+
+func countFavorites(result recommendedPlaces) int {
+	n := 0
+	for _, r := range result.Restaurants {
+		if strings.HasPrefix(r.PlaceID, "fav-") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRecommendFromFavorites(t *testing.T) {
+	client := newFavoritesClient(t, nil)
+
+	var result recommendedPlaces
+	client.decode(client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&source=favorites", nil, http.StatusOK), &result)
+	if len(result.Restaurants) != len(savedFromResults) || countFavorites(result) != len(savedFromResults) {
+		t.Fatalf("restaurants = %+v, want all %d favourites", result.Restaurants, len(savedFromResults))
+	}
+	for _, r := range result.Restaurants {
+		if r.Distance == "" {
+			t.Errorf("%s has no distance", r.PlaceID)
+		}
+	}
+
+	// 超出收藏的距離範圍時沒有候選
+	client.decode(client.do("GET", "/api/restaurants?lat=24.1477&lng=120.6736&source=favorites", nil, http.StatusOK), &result)
+	if len(result.Restaurants) != 0 {
+		t.Errorf("far away: restaurants = %+v, want none", result.Restaurants)
+	}
+}
+
+// 混合來源依 FAVORITE_MIXED_RATIO 從收藏挑選，其餘由附近餐廳補足
+func TestRecommendMixedRatio(t *testing.T) {
+	tests := []struct {
+		ratio         string
+		wantFavorites int
+	}{
+		{"0", 0},
+		{"0.34", 1},
+		{"0.67", 2},
+		{"1", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run("ratio "+tt.ratio, func(t *testing.T) {
+			client := newFavoritesClient(t, map[string]string{"FAVORITE_MIXED_RATIO": tt.ratio})
+
+			var result recommendedPlaces
+			client.decode(client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&source=mixed", nil, http.StatusOK), &result)
+			if len(result.Restaurants) != 3 {
+				t.Fatalf("len(restaurants) = %d, want 3", len(result.Restaurants))
+			}
+			if got := countFavorites(result); got != tt.wantFavorites {
+				t.Errorf("favourites = %d, want %d (%s)", got, tt.wantFavorites, fmt.Sprint(result.Restaurants))
+			}
+		})
+	}
+}
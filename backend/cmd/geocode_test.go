@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"what2eat-backend/internal/model"
+)
+
+// 假的 Google Geocoding API：依地址返回固定結果、查無結果或錯誤，其餘請求交給 fakePlaces
+type fakeGeocoding struct {
+	requests atomic.Int32
+}
+
+func (f *fakeGeocoding) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/geocode/json") {
+		fakePlaces(w, r)
+		return
+	}
+
+	f.requests.Add(1)
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Query().Get("address") {
+	case "市府路45號":
+		w.Write([]byte(`{"status":"OK","results":[{"formatted_address":"110台北市信義區市府路45號","geometry":{"location":{"lat":25.0339,"lng":121.5645}}}]}`))
+	case "服務故障":
+		w.Write([]byte(`{"status":"REQUEST_DENIED","error_message":"The provided API key is invalid.","results":[]}`))
+	case "額度用完":
+		w.Write([]byte(`{"status":"OVER_QUERY_LIMIT","error_message":"You have exceeded your daily request quota.","results":[]}`))
+	default:
+		w.Write([]byte(`{"status":"ZERO_RESULTS","results":[]}`))
+	}
+}
+
+// near 參數在本地地名資料查無時改用 Google Geocoding，查無結果與外部服務錯誤各有對應的狀態碼
+func TestRecommendNearGeocodeFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		near         string
+		dailyLimit   string
+		wantStatus   int
+		wantCode     string
+		wantRequests int32 // 兩次相同請求後呼叫 Geocoding API 的次數
+	}{
+		{"解析成功並快取", "市府路45號", "10", http.StatusOK, "", 1},
+		{"本地地名資料不呼叫外部服務", "台北101", "10", http.StatusOK, "", 0},
+		{"查無結果並快取", "查無此地", "10", http.StatusNotFound, model.ErrorCodeLocationNotFound, 1},
+		{"外部服務錯誤不快取", "服務故障", "10", http.StatusBadGateway, model.ErrorCodeUpstreamUnavailable, 2},
+		{"Google 額度用完", "額度用完", "10", http.StatusTooManyRequests, model.ErrorCodeUpstreamQuota, 2},
+		{"每日額度用完", "市府路45號", "0", http.StatusTooManyRequests, model.ErrorCodeQuotaExceeded, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := &fakeGeocoding{}
+			application := newTestAppWithUpstream(t, map[string]string{
+				"GEOCODE_PROVIDER":        "google",
+				"GEOCODE_DAILY_API_LIMIT": tt.dailyLimit,
+			}, upstream)
+			client := newSpecClient(t, application.router, loadSpec(t))
+			query := "near=" + url.QueryEscape(tt.near)
+
+			var v1 struct {
+				Error string `json:"error"`
+				Near  string `json:"near"`
+			}
+			client.decode(client.do("GET", "/api/restaurants?"+query, nil, tt.wantStatus), &v1)
+			if tt.wantCode != "" && (v1.Error == "" || v1.Near != tt.near) {
+				t.Errorf("/api: error = %q, near = %q", v1.Error, v1.Near)
+			}
+
+			var v2 model.APIResponse
+			client.decode(client.do("GET", "/api/v2/restaurants?"+query, nil, tt.wantStatus), &v2)
+			if tt.wantCode == "" {
+				if len(v2.Errors) != 0 {
+					t.Errorf("/api/v2: errors = %+v", v2.Errors)
+				}
+			} else if len(v2.Errors) != 1 || v2.Errors[0].Code != tt.wantCode || v2.Errors[0].Message == "" {
+				t.Errorf("/api/v2: errors = %+v, want %s", v2.Errors, tt.wantCode)
+			}
+
+			if got := upstream.requests.Load(); got != tt.wantRequests {
+				t.Errorf("geocoding requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"what2eat-backend/internal/grpcapi/what2eatv1"
+	"what2eat-backend/internal/repository"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// 讓 bufconn 的連線帶有指定的客戶端位址，模擬不同 IP 的客戶端
+type remoteAddrListener struct {
+	*bufconn.Listener
+	addr net.Addr
+}
+
+func (l remoteAddrListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return remoteAddrConn{Conn: conn, addr: l.addr}, nil
+}
+
+type remoteAddrConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c remoteAddrConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// 以記憶體內的連線呼叫應用程式的 gRPC 服務，ip 為服務器看到的客戶端位址
+func dialGRPC(t *testing.T, application *app, ip string) what2eatv1.What2EatClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	go application.grpcServer.Serve(remoteAddrListener{
+		Listener: listener,
+		addr:     &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000},
+	})
+	t.Cleanup(application.grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return what2eatv1.NewWhat2EatClient(conn)
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("status code = %v, want %v (err: %v)", got, want, err)
+	}
+}
+
+func TestGRPCRecommendAndReroll(t *testing.T) {
+	application := newTestApp(t, nil)
+	client := dialGRPC(t, application, "198.51.100.1")
+	ctx := context.Background()
+
+	query := &what2eatv1.RecommendRequest{Lat: 25.0330, Lng: 121.5654, Strategy: "weighted", Budget: proto.Int32(2), TravelMode: "walking", Language: "en"}
+	first, err := client.Recommend(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Restaurants) != 3 {
+		t.Fatalf("got %d restaurants, want 3", len(first.Restaurants))
+	}
+	for _, restaurant := range first.Restaurants {
+		if restaurant.PlaceId == "" || len(restaurant.Reasons) == 0 || restaurant.Travel == nil {
+			t.Errorf("incomplete restaurant: %v", restaurant)
+		}
+	}
+	if first.Usage.GetUsed() != 1 {
+		t.Errorf("usage.used = %d, want 1", first.Usage.GetUsed())
+	}
+
+	excluded := make([]string, len(first.Restaurants))
+	for i, restaurant := range first.Restaurants {
+		excluded[i] = restaurant.PlaceId
+	}
+	second, err := client.Reroll(ctx, &what2eatv1.RerollRequest{Query: query, ExcludePlaceIds: excluded})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Restaurants) == 0 {
+		t.Fatal("reroll returned no restaurants")
+	}
+	for _, restaurant := range second.Restaurants {
+		if slices.Contains(excluded, restaurant.PlaceId) {
+			t.Errorf("reroll returned excluded restaurant %s", restaurant.PlaceId)
+		}
+	}
+
+	// REST 與 gRPC 共用同一個 CounterService
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants?lat=25.0330&lng=121.5654", nil)
+	req.RemoteAddr = "198.51.100.1:40000"
+	application.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("REST status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	usage, err := client.GetUsage(ctx, &what2eatv1.GetUsageRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Usage.GetUsed() != 3 || usage.LimitExceeded {
+		t.Errorf("usage = %v, want 3 used and limit not exceeded", usage)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/usage", nil)
+	req.RemoteAddr = "198.51.100.1:40000"
+	application.router.ServeHTTP(rec, req)
+	var body struct {
+		Data struct {
+			Usage struct {
+				Used int `json:"used"`
+			} `json:"usage"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Data.Usage.Used != int(usage.Usage.GetUsed()) {
+		t.Errorf("REST usage = %d, gRPC usage = %d", body.Data.Usage.Used, usage.Usage.GetUsed())
+	}
+}
+
+func TestGRPCInvalidArguments(t *testing.T) {
+	application := newTestApp(t, nil)
+	client := dialGRPC(t, application, "198.51.100.2")
+	ctx := context.Background()
+
+	_, err := client.Recommend(ctx, &what2eatv1.RecommendRequest{Lat: 25.0330, Lng: 121.5654, Budget: proto.Int32(9)})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.Recommend(ctx, &what2eatv1.RecommendRequest{Lat: 25.0330, Lng: 121.5654, Strategy: "best"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.Recommend(ctx, &what2eatv1.RecommendRequest{Lat: 125, Lng: 121.5654})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.Recommend(ctx, &what2eatv1.RecommendRequest{Lat: math.NaN(), Lng: 121.5654})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.Reroll(ctx, &what2eatv1.RerollRequest{Query: &what2eatv1.RecommendRequest{Lat: 25.0330, Lng: math.Inf(1)}, ExcludePlaceIds: []string{"place-1"}})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.Reroll(ctx, &what2eatv1.RerollRequest{ExcludePlaceIds: []string{"place-1"}})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestGRPCGetCategories(t *testing.T) {
+	application := newTestApp(t, nil)
+	client := dialGRPC(t, application, "198.51.100.3")
+
+	resp, err := client.GetCategories(context.Background(), &what2eatv1.GetCategoriesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(resp.Categories, repository.RestaurantTypes) {
+		t.Errorf("categories = %v, want %v", resp.Categories, repository.RestaurantTypes)
+	}
+}
+
+func TestGRPCDailyLimit(t *testing.T) {
+	application := newTestApp(t, map[string]string{"DAILY_API_LIMIT": "1"})
+	client := dialGRPC(t, application, "198.51.100.4")
+	ctx := context.Background()
+
+	query := &what2eatv1.RecommendRequest{Lat: 25.0330, Lng: 121.5654}
+	if _, err := client.Recommend(ctx, query); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Recommend(ctx, query)
+	assertCode(t, err, codes.ResourceExhausted)
+
+	usage, err := client.GetUsage(ctx, &what2eatv1.GetUsageRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !usage.LimitExceeded || usage.Usage.GetRemaining() != 0 {
+		t.Errorf("usage = %v, want limit exceeded", usage)
+	}
+}
+
+// 同一個 IP 的 REST 與 gRPC 請求共用流量限制，其他客戶端不受影響
+func TestGRPCRateLimitPerClient(t *testing.T) {
+	application := newTestApp(t, nil)
+	limited := dialGRPC(t, application, "198.51.100.5")
+	other := dialGRPC(t, application, "198.51.100.6")
+	ctx := context.Background()
+
+	for application.limiters.AllowIP("198.51.100.5") {
+	}
+
+	_, err := limited.GetCategories(ctx, &what2eatv1.GetCategoriesRequest{})
+	assertCode(t, err, codes.ResourceExhausted)
+
+	if _, err := other.GetCategories(ctx, &what2eatv1.GetCategoriesRequest{}); err != nil {
+		t.Fatalf("other client was rate limited: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// 假的 LINE Messaging API，記錄收到的回覆
+type fakeLineAPI struct {
+	mu      sync.Mutex
+	replies []lineReply
+}
+
+type lineReply struct {
+	Authorization string
+	ReplyToken    string           `json:"replyToken"`
+	Messages      []map[string]any `json:"messages"`
+}
+
+func (f *fakeLineAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v2/bot/message/reply" {
+		http.NotFound(w, r)
+		return
+	}
+	var reply lineReply
+	if err := json.NewDecoder(r.Body).Decode(&reply); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply.Authorization = r.Header.Get("Authorization")
+
+	f.mu.Lock()
+	f.replies = append(f.replies, reply)
+	f.mu.Unlock()
+	w.Write([]byte(`{}`))
+}
+
+func (f *fakeLineAPI) take(t *testing.T) lineReply {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.replies) != 1 {
+		t.Fatalf("got %d replies, want 1", len(f.replies))
+	}
+	reply := f.replies[0]
+	f.replies = nil
+	return reply
+}
+
+func newLineTestApp(t *testing.T) (*app, *fakeLineAPI) {
+	t.Helper()
+	lineAPI := &fakeLineAPI{}
+	server := httptest.NewServer(lineAPI)
+	t.Cleanup(server.Close)
+
+	application := newTestApp(t, map[string]string{
+		"LINE_CHANNEL_SECRET":       "line-secret",
+		"LINE_CHANNEL_ACCESS_TOKEN": "line-token",
+		"LINE_API_BASE_URL":         server.URL,
+		"DAILY_API_LIMIT":           "600",
+	})
+	return application, lineAPI
+}
+
+func lineSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func postLineWebhook(t *testing.T, application *app, signature string, events ...map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"destination": "bot", "events": events})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature == "" {
+		signature = lineSignature("line-secret", body)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/line", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Line-Signature", signature)
+	application.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func lineMessageEvent(replyToken string, message map[string]any) map[string]any {
+	return map[string]any{
+		"type":       "message",
+		"replyToken": replyToken,
+		"source":     map[string]any{"type": "user", "userId": "U123"},
+		"message":    message,
+	}
+}
+
+// 取出 carousel 中每家餐廳的 place_id（來自 Google Maps 按鈕的網址）
+func carouselPlaceIDs(t *testing.T, message map[string]any) []string {
+	t.Helper()
+	if message["type"] != "flex" {
+		t.Fatalf("message type = %v, want flex", message["type"])
+	}
+	var carousel struct {
+		Contents []struct {
+			Footer struct {
+				Contents []struct {
+					Action struct {
+						URI string `json:"uri"`
+					} `json:"action"`
+				} `json:"contents"`
+			} `json:"footer"`
+		} `json:"contents"`
+	}
+	raw, _ := json.Marshal(message["contents"])
+	if err := json.Unmarshal(raw, &carousel); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, 0, len(carousel.Contents))
+	for _, bubble := range carousel.Contents {
+		parsed, err := url.Parse(bubble.Footer.Contents[0].Action.URI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, parsed.Query().Get("query_place_id"))
+	}
+	return ids
+}
+
+func TestLineWebhookRejectsInvalidSignature(t *testing.T) {
+	application, lineAPI := newLineTestApp(t)
+	event := lineMessageEvent("token-1", map[string]any{"id": "1", "type": "text", "text": "日式"})
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "其他金鑰簽署", signature: lineSignature("other-secret", []byte(`{}`))},
+		{name: "非 Base64", signature: "not base64!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postLineWebhook(t, application, tt.signature, event); rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", rec.Code)
+			}
+		})
+	}
+
+	lineAPI.mu.Lock()
+	defer lineAPI.mu.Unlock()
+	if len(lineAPI.replies) != 0 {
+		t.Errorf("sent %d replies for unsigned webhooks", len(lineAPI.replies))
+	}
+}
+
+func TestLineWebhookRecommendsAndRerolls(t *testing.T) {
+	application, lineAPI := newLineTestApp(t)
+
+	// 尚未傳送位置時要求位置資訊
+	if rec := postLineWebhook(t, application, "", lineMessageEvent("token-0", map[string]any{"id": "0", "type": "text", "text": "再一次"})); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	reply := lineAPI.take(t)
+	if reply.ReplyToken != "token-0" || reply.Messages[0]["type"] != "text" || reply.Messages[0]["quickReply"] == nil {
+		t.Fatalf("reply = %+v, want location request", reply)
+	}
+
+	location := map[string]any{"id": "1", "type": "location", "latitude": 25.0330, "longitude": 121.5654}
+	if rec := postLineWebhook(t, application, "", lineMessageEvent("token-1", location)); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	reply = lineAPI.take(t)
+	if reply.ReplyToken != "token-1" || reply.Authorization != "Bearer line-token" {
+		t.Errorf("reply token %q, authorization %q", reply.ReplyToken, reply.Authorization)
+	}
+	if len(reply.Messages) != 2 {
+		t.Fatalf("got %d messages, want carousel and quick reply", len(reply.Messages))
+	}
+	first := carouselPlaceIDs(t, reply.Messages[0])
+	if len(first) != 5 {
+		t.Fatalf("carousel has %d restaurants, want 5", len(first))
+	}
+
+	// 「再一次」排除上一批推薦（假資料共 8 家，只剩 3 家）
+	if rec := postLineWebhook(t, application, "", lineMessageEvent("token-2", map[string]any{"id": "2", "type": "text", "text": "再一次"})); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	second := carouselPlaceIDs(t, lineAPI.take(t).Messages[0])
+	if len(second) != 3 {
+		t.Errorf("reroll returned %d restaurants, want the 3 not shown before", len(second))
+	}
+	for _, id := range second {
+		if slices.Contains(first, id) {
+			t.Errorf("reroll repeated restaurant %s", id)
+		}
+	}
+}
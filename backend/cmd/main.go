@@ -2,17 +2,24 @@ package main
 
 import (
 	"fmt"
+	"net"
+	"strings"
 	"time"
 	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/grpcapi"
 	"what2eat-backend/internal/handler"
 	"what2eat-backend/internal/infrastructure"
 	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/ogcard"
+	"what2eat-backend/internal/openapi"
 	"what2eat-backend/internal/repository"
 	"what2eat-backend/internal/service"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
+	"googlemaps.github.io/maps"
 )
 
 func main() {
@@ -31,27 +38,215 @@ func main() {
 		return
 	}
 
+	application, err := newApp(cfg, mapsClient)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return
+	}
+	application.scheduler.Start()
+
+	// 啟動 gRPC 服務器（未設定端口時不啟動）
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			fmt.Printf("gRPC 服務器啟動失敗: %v\n", err)
+			return
+		}
+		go func() {
+			fmt.Printf("gRPC 服務器啟動在端口 %s\n", cfg.GRPCPort)
+			if err := application.grpcServer.Serve(listener); err != nil {
+				fmt.Printf("gRPC 服務器停止: %v\n", err)
+			}
+		}()
+	}
+
+	// 啟動服務器
+	fmt.Printf("服務器啟動在端口 %s\n", cfg.Port)
+	if err := application.router.Run(":" + cfg.Port); err != nil {
+		fmt.Printf("服務器啟動失敗: %v\n", err)
+		return
+	}
+}
+
+// 已初始化的服務與路由
+type app struct {
+	router     *gin.Engine
+	grpcServer *grpc.Server              // 與 REST API 共用同一組服務，由呼叫端決定監聽的位址
+	scheduler  *service.SchedulerService // 由呼叫端決定何時啟動
+	limiters   *middleware.RateLimiters  // REST 與 gRPC 共用的速率限制器
+}
+
+// 依 API 文件檢查查詢參數的中間件，/api 與 /api/v2 的錯誤回應格式不同
+type requestValidators struct {
+	api gin.HandlerFunc
+	v2  gin.HandlerFunc
+}
+
+// newApp 初始化所有服務、Handler 與路由，不啟動排程與服務器，測試可直接使用
+func newApp(cfg *config.Config, mapsClient *maps.Client) (*app, error) {
 	// 初始化 Repository
 	restaurantRepo := repository.NewRestaurantRepository(mapsClient)
 
 	// 初始化計數器服務
 	counterService := service.NewCounterService(cfg.DailyAPILimit)
 
+	// 初始化收藏服務
+	favoriteStore, err := repository.OpenStore(cfg.Favorites.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化收藏儲存: %w", err)
+	}
+	favoriteService := service.NewFavoriteService(repository.NewFavoriteRepository(favoriteStore))
+
+	// 初始化用餐紀錄服務
+	historyStore, err := repository.OpenStore(cfg.History.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化用餐紀錄儲存: %w", err)
+	}
+	historyService := service.NewHistoryService(repository.NewHistoryRepository(historyStore), favoriteService, restaurantRepo, cfg.History)
+
+	// 初始化回饋與偏好服務
+	feedbackStore, err := repository.OpenStore(cfg.Feedback.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化回饋儲存: %w", err)
+	}
+	feedbackService := service.NewFeedbackService(repository.NewFeedbackRepository(feedbackStore), favoriteService, restaurantRepo, cfg.Feedback)
+
+	// 初始化使用者身分服務
+	userStore, err := repository.OpenStore(cfg.Auth.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化使用者儲存: %w", err)
+	}
+	authService := service.NewAuthService(
+		repository.NewUserRepository(userStore),
+		newIdentityProvider(cfg.Auth),
+		newMagicLinkSender(cfg.Auth),
+		cfg.Auth,
+	)
+	authService.RegisterMerger(favoriteService)
+	authService.RegisterMerger(historyService)
+	authService.RegisterMerger(feedbackService)
+
 	// 初始化 Service
-	restaurantService := service.NewRestaurantService(restaurantRepo, counterService)
+	// 初始化交通時間服務，Distance Matrix 使用獨立的每日額度
+	var travelProvider service.TravelTimeProvider
+	if cfg.Travel.Provider == "google" {
+		travelProvider = infrastructure.NewGoogleDistanceMatrix(mapsClient)
+	}
+	travelCounter := service.NewNamedCounterService("travel_counter", cfg.Travel.DailyLimit)
+	travelService := service.NewTravelService(travelProvider, service.NewHaversineEstimator(), travelCounter, cfg.Travel)
+
+	// 初始化地點解析與區域標籤服務，本地資料優先，Geocoding API 使用獨立的每日額度
+	gazetteer, err := repository.NewGazetteer(cfg.Geocode.GazetteerFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法載入地名資料: %w", err)
+	}
+	geocodeStore, err := repository.OpenStore(cfg.Geocode.CacheFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化地點快取: %w", err)
+	}
+	districtIndex, err := repository.NewDistrictIndex(cfg.Geocode.DistrictDataset)
+	if err != nil {
+		return nil, fmt.Errorf("無法載入行政區邊界: %w", err)
+	}
+	var remoteGeocoder service.Geocoder
+	var remoteReverseGeocoder service.ReverseGeocoder
+	if cfg.Geocode.Provider == "google" {
+		googleGeocoder := infrastructure.NewGoogleGeocoder(mapsClient, cfg.Geocode.Region)
+		remoteGeocoder = googleGeocoder
+		remoteReverseGeocoder = googleGeocoder
+	}
+	geocodeRepo := repository.NewGeocodeRepository(geocodeStore)
+	geocodeCounter := service.NewNamedCounterService("geocode_counter", cfg.Geocode.DailyLimit)
+	geocodeService := service.NewGeocodeService(gazetteer, remoteGeocoder, geocodeRepo, geocodeCounter, cfg.Geocode)
+	areaService := service.NewAreaService(districtIndex, remoteReverseGeocoder, geocodeRepo, geocodeCounter, cfg.Geocode)
+
+	restaurantService := service.NewRestaurantService(restaurantRepo, counterService, favoriteService, historyService, feedbackService, travelService, areaService, cfg)
+
+	// 初始化投票房間服務 (可選擇持久化到檔案)
+	roomStore, err := repository.OpenStore(cfg.Room.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化投票房間儲存: %w", err)
+	}
+	roomService := service.NewRoomService(restaurantService, roomStore, cfg.Room)
+
+	// 初始化 LINE Bot
+	lineClient := infrastructure.NewLineClient(cfg.Line.APIBaseURL, cfg.Line.ChannelAccessToken)
+	lineBotService := service.NewLineBotService(restaurantService, lineClient)
+
+	// 初始化 Slack 指令與午餐投票
+	slackStore, err := repository.OpenStore(cfg.Slack.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化 Slack 儲存: %w", err)
+	}
+	slackService := service.NewSlackService(roomService, infrastructure.NewSlackClient(cfg.Slack.APIBaseURL, cfg.Slack.BotToken), slackStore, cfg.Slack)
+
+	// 初始化定期推送排程（未設定 LINE access token 時不提供 LINE 推送）
+	scheduleStore, err := repository.OpenStore(cfg.Scheduler.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化排程儲存: %w", err)
+	}
+	var linePusher service.LinePusher
+	if cfg.Line.ChannelAccessToken != "" {
+		linePusher = lineClient
+	}
+	schedulerService := service.NewSchedulerService(repository.NewScheduleRepository(scheduleStore), restaurantService, counterService, linePusher, infrastructure.NewWebhookClient(cfg.Scheduler.AllowPrivateURL), authService, cfg.Scheduler)
+
+	// 初始化推薦結果分享
+	shareStore, err := repository.OpenStore(cfg.Share.StoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化分享儲存: %w", err)
+	}
+	shareService := service.NewShareService(repository.NewShareRepository(shareStore), cfg.Share)
+	cardRenderer, err := ogcard.NewRenderer()
+	if err != nil {
+		return nil, fmt.Errorf("無法初始化分享卡片: %w", err)
+	}
+	shareCardService := service.NewShareCardService(shareService, cardRenderer, cfg.Share)
 
 	// 初始化 Handler
-	restaurantHandler := handler.NewRestaurantHandler(restaurantService, counterService)
+	restaurantHandler := handler.NewRestaurantHandler(restaurantService, counterService, geocodeService)
+	roomHandler := handler.NewRoomHandler(roomService, counterService)
+	favoriteHandler := handler.NewFavoriteHandler(favoriteService)
+	authHandler := handler.NewAuthHandler(authService)
+	historyHandler := handler.NewHistoryHandler(historyService)
+	feedbackHandler := handler.NewFeedbackHandler(feedbackService)
+	lineHandler := handler.NewLineHandler(lineBotService, cfg.Line.ChannelSecret)
+	slackHandler := handler.NewSlackHandler(slackService, cfg.Slack.SigningSecret)
+	scheduleHandler := handler.NewScheduleHandler(schedulerService)
+	shareHandler := handler.NewShareHandler(shareService, shareCardService)
+
+	// API 文件，啟用時依文件檢查查詢參數
+	spec, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+	openAPIHandler, err := handler.NewOpenAPIHandler(spec)
+	if err != nil {
+		return nil, err
+	}
+	var requestValidation *requestValidators
+	if cfg.OpenAPI.ValidateRequests {
+		requestValidation = &requestValidators{
+			api: middleware.ValidateQuery(spec, middleware.RespondValidationError),
+			v2:  middleware.ValidateQuery(spec, restaurantHandler.RespondValidationErrorV2),
+		}
+	}
 
 	// 設定 Gin 路由
 	r := gin.Default()
 
+	// Server-Sent Events 的長連線路由，不套用請求超時
+	streamRoutes := []string{"/api/restaurants/stream", "/api/rooms/:id/events"}
+
+	// 速率限制器由 REST 與 gRPC 共用，兩種協定的請求計入同一份額度
+	limiters := middleware.NewRateLimiters()
+
 	// 安全中間件 (按順序套用)
-	r.Use(middleware.SecurityHeaders())                   // 安全標頭
-	r.Use(middleware.RequestSizeLimit(1024 * 1024))       // 1MB 請求大小限制
-	r.Use(middleware.TimeoutMiddleware(30 * time.Second)) // 30 秒超時
-	r.Use(middleware.GlobalRateLimit())                   // 全域流量限制
-	r.Use(middleware.IPRateLimit())                       // IP 流量限制
+	r.Use(middleware.SecurityHeaders())                                  // 安全標頭
+	r.Use(middleware.RequestSizeLimit(1024 * 1024))                      // 1MB 請求大小限制
+	r.Use(middleware.TimeoutMiddleware(30*time.Second, streamRoutes...)) // 30 秒超時
+	r.Use(middleware.GlobalRateLimit(limiters))                          // 全域流量限制
+	r.Use(middleware.IPRateLimit(limiters))                              // IP 流量限制
 
 	// CORS 設定
 	config := cors.DefaultConfig()
@@ -63,30 +258,175 @@ func main() {
 		"http://127.0.0.1:5173",
 		"http://127.0.0.1:5500",
 	}
-	config.AllowMethods = []string{"GET", "POST", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept"}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	config.ExposeHeaders = []string{middleware.AuthTokenHeader}
 	r.Use(cors.New(config))
 
 	// 註冊路由
-	registerRoutes(r, restaurantHandler)
+	registerRoutes(r, authService, restaurantHandler, roomHandler, favoriteHandler, authHandler, historyHandler, feedbackHandler, lineHandler, slackHandler, scheduleHandler, shareHandler, openAPIHandler, requestValidation, limiters)
+
+	// gRPC 服務與 REST API 共用推薦服務與每日額度
+	grpcServer := grpcapi.NewGRPCServer(grpcapi.NewServer(restaurantService, counterService), limiters)
+
+	return &app{router: r, grpcServer: grpcServer, scheduler: schedulerService, limiters: limiters}, nil
 
-	// 啟動服務器
-	fmt.Printf("服務器啟動在端口 %s\n", cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		fmt.Printf("服務器啟動失敗: %v\n", err)
-		return
-	}
 }
 
-func registerRoutes(r *gin.Engine, restaurantHandler *handler.RestaurantHandler) {
+func registerRoutes(r *gin.Engine, auth middleware.Authenticator, restaurantHandler *handler.RestaurantHandler, roomHandler *handler.RoomHandler, favoriteHandler *handler.FavoriteHandler, authHandler *handler.AuthHandler, historyHandler *handler.HistoryHandler, feedbackHandler *handler.FeedbackHandler, lineHandler *handler.LineHandler, slackHandler *handler.SlackHandler, scheduleHandler *handler.ScheduleHandler, shareHandler *handler.ShareHandler, openAPIHandler *handler.OpenAPIHandler, requestValidation *requestValidators, limiters *middleware.RateLimiters) {
 	r.GET("/health", restaurantHandler.HealthCheck)
 
+	// API 文件 (OpenAPI 3)
+	r.GET("/openapi.json", openAPIHandler.Spec)
+
+	// LINE Messaging API webhook (以 X-Line-Signature 驗證)
+	r.POST("/webhooks/line", lineHandler.Webhook)
+
+	// Slack slash command 與互動元件回呼 (以 X-Slack-Signature 驗證)
+	r.POST("/webhooks/slack/commands", slackHandler.Command)
+	r.POST("/webhooks/slack/interactions", slackHandler.Interaction)
+
+	// 分享連結的預覽頁面與卡片圖片 (給 LINE、Slack 等爬蟲讀取)
+	r.GET("/s/:id", shareHandler.SharePage)
+	r.GET("/s/:id/card.png", shareHandler.ShareCard)
+
+	// API 專用流量限制 (更嚴格)，/api 與 /api/v2 共用同一個限制器
+	apiRateLimit := middleware.APIRateLimit(limiters)
+
 	api := r.Group("/api")
 	{
-		// API 專用流量限制 (更嚴格)
-		api.Use(middleware.APIRateLimit())
+		api.Use(apiRateLimit)
+		if requestValidation != nil {
+			api.Use(requestValidation.api)
+		}
 
 		// 只保留 GET 方法
-		api.GET("/restaurants", restaurantHandler.GetRestaurants)
+		api.GET("/restaurants", middleware.OptionalUser(auth), restaurantHandler.GetRestaurants)
+
+		// 以 Server-Sent Events 逐步推送推薦結果
+		api.GET("/restaurants/stream", middleware.OptionalUser(auth), restaurantHandler.GetRestaurantsStream)
+
+		// 地圖範圍內的所有候選餐廳
+		api.GET("/restaurants/area", restaurantHandler.GetRestaurantsInArea)
+
+		// 多人會面地點推薦
+		api.POST("/recommend/group", restaurantHandler.GetGroupRecommendations)
+
+		// 團體投票房間
+		api.POST("/rooms", middleware.RequireUser(auth), roomHandler.CreateRoom)
+		api.GET("/rooms/:id", roomHandler.GetRoom)
+		api.GET("/rooms/:id/events", roomHandler.Events)
+		api.POST("/rooms/:id/join", roomHandler.JoinRoom)
+		api.POST("/rooms/:id/vote", roomHandler.Vote)
+		api.POST("/rooms/:id/veto", roomHandler.Veto)
+		api.POST("/rooms/:id/close", middleware.RequireUser(auth), roomHandler.CloseRoom)
+
+		// 使用者身分：匿名裝置權杖與帳號綁定
+		authGroup := api.Group("/auth")
+		{
+			authGroup.POST("/device", authHandler.IssueDeviceToken)
+			authGroup.GET("/me", middleware.RequireUser(auth), authHandler.Me)
+			authGroup.POST("/email", middleware.RequireUser(auth), authHandler.RequestMagicLink)
+			authGroup.GET("/email/verify", authHandler.VerifyMagicLink)
+			authGroup.GET("/oauth/start", middleware.RequireUser(auth), authHandler.StartOAuth)
+			authGroup.GET("/oauth/callback", authHandler.OAuthCallback)
+			authGroup.POST("/link", middleware.RequireUser(auth), authHandler.CompleteLink)
+		}
+
+		// 個人收藏 (需要使用者身分，未帶權杖時自動發放匿名權杖)
+		favorites := api.Group("/favorites", middleware.RequireUser(auth))
+		{
+			favorites.GET("", favoriteHandler.ListFavorites)
+			favorites.POST("", favoriteHandler.AddFavorite)
+			favorites.GET("/:place_id", favoriteHandler.GetFavorite)
+			favorites.PATCH("/:place_id", favoriteHandler.UpdateFavorite)
+			favorites.DELETE("/:place_id", favoriteHandler.DeleteFavorite)
+		}
+
+		// 用餐紀錄 (需要使用者身分)
+		history := api.Group("/history", middleware.RequireUser(auth))
+		{
+			history.GET("", historyHandler.ListHistory)
+			history.POST("", historyHandler.RecordMeal)
+			history.DELETE("/:id", historyHandler.DeleteMeal)
+		}
+
+		// 回饋與偏好檔案 (需要使用者身分)
+		feedback := api.Group("/feedback", middleware.RequireUser(auth))
+		{
+			feedback.GET("", feedbackHandler.ListFeedback)
+			feedback.POST("", feedbackHandler.SubmitFeedback)
+			feedback.DELETE("/:place_id", feedbackHandler.DeleteFeedback)
+		}
+		preferences := api.Group("/preferences", middleware.RequireUser(auth))
+		{
+			preferences.GET("", feedbackHandler.GetPreferences)
+			preferences.DELETE("", feedbackHandler.ResetPreferences)
+		}
+
+		// 定期推送排程
+		schedules := api.Group("/schedules", middleware.RequireUser(auth))
+		{
+			schedules.GET("", scheduleHandler.ListSchedules)
+			schedules.POST("", scheduleHandler.CreateSchedule)
+			schedules.GET("/:id", scheduleHandler.GetSchedule)
+			schedules.PUT("/:id", scheduleHandler.UpdateSchedule)
+			schedules.DELETE("/:id", scheduleHandler.DeleteSchedule)
+			schedules.POST("/:id/run", scheduleHandler.RunSchedule)
+			schedules.GET("/:id/deliveries", scheduleHandler.ListDeliveries)
+		}
+
+		// 分享推薦結果
+		api.POST("/shares", shareHandler.CreateShare)
+		api.GET("/shares/:id", shareHandler.GetShare)
+	}
+
+	// API v2：固定的回應格式 (data / meta / errors) 與錯誤代碼，/api 保留為相容層
+	v2 := r.Group("/api/v2")
+	{
+		v2.Use(apiRateLimit)
+		if requestValidation != nil {
+			v2.Use(requestValidation.v2)
+		}
+
+		v2.GET("/restaurants", middleware.OptionalUser(auth), restaurantHandler.GetRestaurantsV2)
+		v2.GET("/restaurants/area", restaurantHandler.GetRestaurantsInAreaV2)
+		v2.POST("/recommend/group", restaurantHandler.GetGroupRecommendationsV2)
+		v2.GET("/usage", restaurantHandler.GetUsageV2)
+	}
+}
+
+// 依設定建立 OAuth 身分提供者，未設定時返回 nil（停用 OAuth 綁定）
+func newIdentityProvider(cfg config.AuthConfig) service.IdentityProvider {
+	switch cfg.OAuthProvider {
+	case "local":
+		return infrastructure.NewLocalIdentityProvider(cfg.OAuthRedirectURL)
+	case "oauth":
+		return infrastructure.NewOAuthProvider(infrastructure.OAuthConfig{
+			Name:         cfg.OAuthName,
+			ClientID:     cfg.OAuthClientID,
+			ClientSecret: cfg.OAuthClientSecret,
+			AuthURL:      cfg.OAuthAuthURL,
+			TokenURL:     cfg.OAuthTokenURL,
+			UserInfoURL:  cfg.OAuthUserInfoURL,
+			RedirectURL:  cfg.OAuthRedirectURL,
+			Scopes:       strings.Fields(cfg.OAuthScopes),
+		})
+	default:
+		return nil
+	}
+}
+
+// 依設定建立登入連結寄送方式，未設定 SMTP 時只輸出到日誌
+func newMagicLinkSender(cfg config.AuthConfig) service.MagicLinkSender {
+	if cfg.SMTPHost == "" {
+		return infrastructure.NewLogMailer()
 	}
+	return infrastructure.NewSMTPMailer(infrastructure.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	})
 }
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/openapi"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+	"googlemaps.github.io/maps"
+)
+
+// 假的 Google Places 附近搜尋，以查詢位置為中心返回固定的餐廳
+func fakePlaces(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !strings.HasSuffix(r.URL.Path, "/nearbysearch/json") {
+		w.Write([]byte(`{"status":"OK"}`))
+		return
+	}
+
+	var lat, lng float64
+	fmt.Sscanf(r.URL.Query().Get("location"), "%f,%f", &lat, &lng)
+	results := make([]map[string]any, 0, 8)
+	for i := range 8 {
+		results = append(results, map[string]any{
+			"place_id":           fmt.Sprintf("place-%.3f-%.3f-%d", lat, lng, i),
+			"name":               fmt.Sprintf("測試餐廳 %d", i),
+			"vicinity":           fmt.Sprintf("測試路 %d 號", i+1),
+			"rating":             3.6 + float64(i)*0.15,
+			"user_ratings_total": 20 * (i + 1),
+			"price_level":        i%4 + 1,
+			"geometry": map[string]any{"location": map[string]any{
+				"lat": lat + float64(i)*0.0012,
+				"lng": lng - float64(i)*0.0009,
+			}},
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]any{"status": "OK", "results": results})
+}
+
+// 以記憶體儲存與假的 Google API 建立完整的應用程式，工作目錄切換到暫存目錄
+func newTestApp(t *testing.T, env map[string]string) *app {
+	t.Helper()
+	return newTestAppWithUpstream(t, env, http.HandlerFunc(fakePlaces))
+}
+
+// 與 newTestApp 相同，Google API 改由 upstream 回應
+func newTestAppWithUpstream(t *testing.T, env map[string]string, upstreamHandler http.Handler) *app {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Chdir(t.TempDir())
+
+	t.Setenv("GOOGLE_MAPS_API_KEY", "test-key")
+	t.Setenv("AUTH_TOKEN_SECRET", "test-secret")
+	t.Setenv("TRAVEL_PROVIDER", "estimate")
+	t.Setenv("GEOCODE_PROVIDER", "gazetteer")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	upstream := httptest.NewServer(upstreamHandler)
+	t.Cleanup(upstream.Close)
+	mapsClient, err := maps.NewClient(maps.WithAPIKey("test-key"), maps.WithBaseURL(upstream.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application, err := newApp(config.Load(), mapsClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return application
+}
+
+func loadSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// 所有註冊的路由都需要出現在文件中，文件中也不能有不存在的路由
+func TestOpenAPICoversRoutes(t *testing.T) {
+	application := newTestApp(t, nil)
+	doc := loadSpec(t)
+
+	registered := make(map[string]bool)
+	for _, route := range application.router.Routes() {
+		path := strings.NewReplacer(":id", "{id}", ":place_id", "{place_id}").Replace(route.Path)
+		key := route.Method + " " + path
+		registered[key] = true
+
+		pathItem := doc.Paths.Value(path)
+		if pathItem == nil || pathItem.GetOperation(route.Method) == nil {
+			t.Errorf("route %s is not documented in openapi.yaml", key)
+		}
+	}
+
+	for path, pathItem := range doc.Paths.Map() {
+		for method := range pathItem.Operations() {
+			if !registered[method+" "+path] {
+				t.Errorf("openapi.yaml documents %s %s, which is not registered", method, path)
+			}
+		}
+	}
+}
+
+// 以實際請求驗證各 handler 的回應符合文件（狀態碼、Content-Type 與內容）
+func TestOpenAPIResponses(t *testing.T) {
+	openapi3filter.RegisterBodyDecoder("application/geo+json", openapi3filter.JSONBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.PlainBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("text/event-stream", openapi3filter.PlainBodyDecoder)
+
+	application := newTestApp(t, map[string]string{"OPENAPI_VALIDATE_REQUESTS": "true"})
+	client := newSpecClient(t, application.router, loadSpec(t))
+
+	// 系統
+	client.do("GET", "/health", nil, http.StatusOK)
+	client.do("GET", "/openapi.json", nil, http.StatusOK)
+
+	// 餐廳推薦（/api）
+	client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654", nil, http.StatusOK)
+	client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&type=拉麵&strategy=weighted&budget=2&travel_mode=walking&lang=en", nil, http.StatusOK)
+	client.do("GET", "/api/restaurants?near="+url.QueryEscape("台北101"), nil, http.StatusOK)
+	client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&format=geojson", nil, http.StatusOK)
+	client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&format=csv", nil, http.StatusOK)
+	client.do("GET", "/api/restaurants?near="+url.QueryEscape("不存在的地方xyz"), nil, http.StatusNotFound)
+	client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&source=favorites", nil, http.StatusUnauthorized)
+	client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&budget=9", nil, http.StatusBadRequest)
+	client.do("GET", "/api/restaurants/stream?lat=25.0330&lng=121.5654&travel_mode=walking", nil, http.StatusOK)
+	client.do("GET", "/api/restaurants/stream?lat=25.0330&lng=121.5654&budget=9", nil, http.StatusBadRequest)
+	client.do("GET", "/api/restaurants/stream?lat=25.0330&lng=121.5654&source=favorites", nil, http.StatusUnauthorized)
+	client.do("GET", "/api/restaurants/area?sw=25.030,121.560&ne=25.034,121.565", nil, http.StatusOK)
+	client.do("GET", "/api/restaurants/area?sw=25.030,121.560&ne=25.034,121.565&format=geojson", nil, http.StatusOK)
+	client.do("GET", "/api/restaurants/area?sw=24.0,120.0&ne=26.0,122.0", nil, http.StatusBadRequest)
+	group := gin.H{"participants": []gin.H{{"name": "A", "lat": 25.033, "lng": 121.565}, {"name": "B", "lat": 25.047, "lng": 121.517}}, "method": "minimax"}
+	client.do("POST", "/api/recommend/group", group, http.StatusOK)
+	client.do("POST", "/api/recommend/group", gin.H{"participants": []gin.H{}}, http.StatusBadRequest)
+
+	// API v2
+	client.do("GET", "/api/v2/restaurants?lat=25.0330&lng=121.5654&travel_mode=walking", nil, http.StatusOK)
+	client.do("GET", "/api/v2/restaurants?near="+url.QueryEscape("台北車站"), nil, http.StatusOK)
+	client.do("GET", "/api/v2/restaurants?lat=95&lng=121.5654", nil, http.StatusBadRequest)
+	client.do("GET", "/api/v2/restaurants?lat=25.0330&lng=121.5654&source=mixed", nil, http.StatusUnauthorized)
+	client.do("GET", "/api/v2/restaurants/area?sw=25.030,121.560&ne=25.034,121.565&page_size=5", nil, http.StatusOK)
+	client.do("GET", "/api/v2/restaurants/area?sw=24.0,120.0&ne=26.0,122.0", nil, http.StatusBadRequest)
+	client.do("POST", "/api/v2/recommend/group", group, http.StatusOK)
+	client.do("GET", "/api/v2/usage", nil, http.StatusOK)
+
+	// 團體投票房間
+	var created struct {
+		Room struct {
+			ID         string `json:"id"`
+			Candidates []struct {
+				Restaurant struct {
+					PlaceID string `json:"place_id"`
+				} `json:"restaurant"`
+			} `json:"candidates"`
+		} `json:"room"`
+	}
+	createdRoom := client.do("POST", "/api/rooms", gin.H{"lat": 25.033, "lng": 121.565, "pool_size": 4, "quorum": 3}, http.StatusCreated)
+	client.decode(createdRoom, &created)
+	creatorToken := createdRoom.Header().Get("X-Auth-Token")
+	roomPath := "/api/rooms/" + created.Room.ID
+	client.do("GET", roomPath, nil, http.StatusOK)
+	client.do("GET", "/api/rooms/missing", nil, http.StatusNotFound)
+	var joined struct {
+		ParticipantID string `json:"participant_id"`
+	}
+	client.decode(client.do("POST", roomPath+"/join", gin.H{"name": "小明"}, http.StatusOK), &joined)
+	candidates := created.Room.Candidates
+	client.do("POST", roomPath+"/veto", gin.H{"participant_id": joined.ParticipantID, "place_id": candidates[1].Restaurant.PlaceID}, http.StatusOK)
+	client.do("POST", roomPath+"/vote", gin.H{"participant_id": joined.ParticipantID, "place_id": candidates[0].Restaurant.PlaceID}, http.StatusOK)
+	client.do("POST", roomPath+"/vote", gin.H{"participant_id": "nobody", "place_id": candidates[0].Restaurant.PlaceID}, http.StatusForbidden)
+	client.do("POST", roomPath+"/close", nil, http.StatusForbidden)
+	client.token = creatorToken
+	client.do("POST", roomPath+"/close", nil, http.StatusOK)
+	client.do("POST", roomPath+"/close", nil, http.StatusConflict)
+	client.token = ""
+
+	// 使用者身分
+	var device struct {
+		Token string `json:"token"`
+	}
+	client.decode(client.do("POST", "/api/auth/device", nil, http.StatusCreated), &device)
+	client.token = device.Token
+	client.do("GET", "/api/auth/me", nil, http.StatusOK)
+	var magic struct {
+		RequestID string `json:"request_id"`
+	}
+	client.decode(client.do("POST", "/api/auth/email", gin.H{"email": "someone@example.com"}, http.StatusAccepted), &magic)
+	client.do("POST", "/api/auth/link", gin.H{"request_id": magic.RequestID}, http.StatusConflict)
+	client.do("POST", "/api/auth/link", gin.H{"request_id": "missing"}, http.StatusNotFound)
+	client.do("GET", "/api/auth/email/verify?token=invalid", nil, http.StatusUnauthorized)
+	client.do("GET", "/api/auth/oauth/start", nil, http.StatusNotImplemented)
+	client.do("GET", "/api/auth/oauth/callback?error=access_denied", nil, http.StatusUnauthorized)
+
+	// 收藏
+	client.do("GET", "/api/favorites", nil, http.StatusOK)
+	restaurant := gin.H{"place_id": "place-fav", "name": "收藏餐廳", "rating": 4.5, "lat": 25.034, "lng": 121.566, "address": "測試路 1 號"}
+	client.do("POST", "/api/favorites", gin.H{"place_id": "place-fav", "restaurant": restaurant, "category": "拉麵", "tags": []string{"午餐"}}, http.StatusCreated)
+	client.do("POST", "/api/favorites", gin.H{"place_id": "place-fav", "restaurant": restaurant}, http.StatusConflict)
+	client.do("POST", "/api/favorites", gin.H{"place_id": "place-null-island", "restaurant": gin.H{"place_id": "place-null-island", "name": "赤道餐廳"}, "location": gin.H{"lat": 0, "lng": 0}}, http.StatusCreated)
+	client.do("POST", "/api/favorites", gin.H{"place_id": "place-no-lng", "location": gin.H{"lat": 0}}, http.StatusBadRequest)
+	client.do("GET", "/api/favorites?lat=25.033&lng=121.565", nil, http.StatusOK)
+	client.do("GET", "/api/favorites/place-fav", nil, http.StatusOK)
+	client.do("PATCH", "/api/favorites/place-fav", gin.H{"note": "湯頭濃郁"}, http.StatusOK)
+	client.do("GET", "/api/restaurants?lat=25.0330&lng=121.5654&source=mixed", nil, http.StatusOK)
+	client.do("DELETE", "/api/favorites/place-fav", nil, http.StatusOK)
+	client.do("GET", "/api/favorites/place-fav", nil, http.StatusNotFound)
+
+	// 用餐紀錄
+	var meal struct {
+		Meal struct {
+			ID string `json:"id"`
+		} `json:"meal"`
+	}
+	client.decode(client.do("POST", "/api/history", gin.H{"place_id": "place-fav", "restaurant": restaurant}, http.StatusCreated), &meal)
+	client.do("GET", "/api/history?weeks=4", nil, http.StatusOK)
+	client.do("GET", "/api/history?weeks=99", nil, http.StatusBadRequest)
+	client.do("DELETE", "/api/history/"+meal.Meal.ID, nil, http.StatusOK)
+	client.do("DELETE", "/api/history/missing", nil, http.StatusNotFound)
+
+	// 回饋與偏好
+	client.do("POST", "/api/feedback", gin.H{"place_id": "place-fav", "kind": "like", "restaurant": restaurant}, http.StatusOK)
+	client.do("GET", "/api/feedback", nil, http.StatusOK)
+	client.do("GET", "/api/preferences", nil, http.StatusOK)
+	client.do("DELETE", "/api/feedback/place-fav", nil, http.StatusOK)
+	client.do("DELETE", "/api/preferences?keep_blocked=true", nil, http.StatusOK)
+
+	// 定期推送排程
+	// 預設拒絕連線到內部網路位址，本地的 webhook 不應收到請求
+	var hookCalls atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hookCalls.Add(1) }))
+	defer hook.Close()
+	schedule := gin.H{"name": "午餐", "cron": "30 11 * * 1-5", "lat": 25.033, "lng": 121.565, "count": 2, "target": gin.H{"type": "webhook", "url": hook.URL}}
+	var scheduled struct {
+		Schedule struct {
+			ID string `json:"id"`
+		} `json:"schedule"`
+	}
+	client.decode(client.do("POST", "/api/schedules", schedule, http.StatusCreated), &scheduled)
+	schedulePath := "/api/schedules/" + scheduled.Schedule.ID
+	client.do("POST", "/api/schedules", gin.H{"cron": "not a cron"}, http.StatusBadRequest)
+	client.do("GET", "/api/schedules", nil, http.StatusOK)
+	client.do("GET", schedulePath, nil, http.StatusOK)
+	schedule["enabled"] = false
+	client.do("PUT", schedulePath, schedule, http.StatusOK)
+	client.do("POST", schedulePath+"/run", nil, http.StatusAccepted)
+	if status, errText := client.waitForDelivery(schedulePath + "/deliveries"); status != "failed" || !strings.Contains(errText, "內部網路") {
+		t.Errorf("delivery to private webhook = %s (%s), want failed as blocked", status, errText)
+	}
+	if hookCalls.Load() != 0 {
+		t.Errorf("private webhook received %d requests, want 0", hookCalls.Load())
+	}
+	client.do("POST", schedulePath+"/run", nil, http.StatusTooManyRequests)
+	client.do("DELETE", schedulePath, nil, http.StatusOK)
+	client.do("GET", schedulePath, nil, http.StatusNotFound)
+
+	// 分享
+	client.token = ""
+	var share struct {
+		Share struct {
+			ID string `json:"id"`
+		} `json:"share"`
+	}
+	client.decode(client.do("POST", "/api/shares", gin.H{"restaurants": []gin.H{restaurant}, "query": gin.H{"lat": 25.033, "lng": 121.565, "budget": -1}}, http.StatusCreated), &share)
+	client.do("GET", "/api/shares/"+share.Share.ID, nil, http.StatusOK)
+	client.do("GET", "/api/shares/missing", nil, http.StatusNotFound)
+	client.do("GET", "/s/"+share.Share.ID, nil, http.StatusOK)
+	client.do("GET", "/s/"+share.Share.ID+"/card.png", nil, http.StatusOK)
+	client.do("GET", "/s/missing", nil, http.StatusNotFound)
+
+	// LINE 與 Slack 未設定時停用
+	client.do("POST", "/webhooks/line", gin.H{"events": []gin.H{}}, http.StatusServiceUnavailable)
+}
+
+// 發送請求並以 OpenAPI 文件驗證回應
+type specClient struct {
+	t      *testing.T
+	router *gin.Engine
+	doc    *openapi3.T
+	routes routers.Router
+	token  string
+
+	// 請求間隔低於 /api 的流量限制，並以不同的來源 IP 避開 IP 流量限制
+	pace     *rate.Limiter
+	requests int
+}
+
+func newSpecClient(t *testing.T, router *gin.Engine, doc *openapi3.T) *specClient {
+	routes, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &specClient{t: t, router: router, doc: doc, routes: routes, pace: rate.NewLimiter(9, 15)}
+}
+
+func (c *specClient) do(method, target string, body any, wantStatus int) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(payload))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	c.requests++
+	req.RemoteAddr = fmt.Sprintf("192.0.2.%d:1234", c.requests%250+1)
+	if strings.HasPrefix(target, "/api/") {
+		c.pace.Wait(context.Background())
+	}
+
+	rec := httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+	if rec.Code != wantStatus {
+		c.t.Errorf("%s %s: status %d, want %d: %s", method, target, rec.Code, wantStatus, rec.Body.String())
+	}
+
+	route, pathParams, err := c.routes.FindRoute(req)
+	if err != nil {
+		c.t.Errorf("%s %s: %v", method, target, err)
+		return rec
+	}
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route},
+		Status:                 rec.Code,
+		Header:                 rec.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+	})
+	if err != nil {
+		c.t.Errorf("%s %s: response does not match openapi.yaml: %v", method, target, err)
+	}
+	return rec
+}
+
+func (c *specClient) decode(rec *httptest.ResponseRecorder, v any) {
+	c.t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		c.t.Fatalf("cannot decode response: %v", err)
+	}
+}
+
+// 等待背景發送結束，並驗證完成後的發送紀錄
+// waitForDelivery 等待最新的發送紀錄完成，返回其狀態與錯誤訊息
+func (c *specClient) waitForDelivery(path string) (string, string) {
+	c.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var list struct {
+			Deliveries []struct {
+				Status string `json:"status"`
+				Error  string `json:"error"`
+			} `json:"deliveries"`
+		}
+		c.decode(c.do("GET", path, nil, http.StatusOK), &list)
+		if len(list.Deliveries) > 0 && list.Deliveries[0].Status != "pending" {
+			return list.Deliveries[0].Status, list.Deliveries[0].Error
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	c.t.Fatalf("delivery for %s did not finish", path)
+	return "", ""
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 假的 Slack Web API 與 response_url，將收到的呼叫依序送到通道
+type fakeSlackAPI struct {
+	calls chan slackCall
+}
+
+type slackCall struct {
+	Method        string
+	Authorization string
+	Body          map[string]any
+}
+
+func (f *fakeSlackAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.calls <- slackCall{Method: strings.TrimPrefix(r.URL.Path, "/"), Authorization: r.Header.Get("Authorization"), Body: body}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"ok":true,"ts":"1700000000.000100"}`))
+}
+
+// 等待下一個指定方法的呼叫，略過其他呼叫（例如投票訊息的更新）
+func (f *fakeSlackAPI) waitFor(t *testing.T, method string) slackCall {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case call := <-f.calls:
+			if call.Method == method {
+				return call
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", method)
+		}
+	}
+}
+
+type slackTestApp struct {
+	*app
+	api       *fakeSlackAPI
+	serverURL string
+}
+
+func newSlackTestApp(t *testing.T) *slackTestApp {
+	t.Helper()
+	api := &fakeSlackAPI{calls: make(chan slackCall, 32)}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	application := newTestApp(t, map[string]string{
+		"SLACK_SIGNING_SECRET": "slack-secret",
+		"SLACK_BOT_TOKEN":      "xoxb-test",
+		"SLACK_API_BASE_URL":   server.URL,
+		"SLACK_STORE_FILE":     "",
+		"DAILY_API_LIMIT":      "600",
+	})
+	return &slackTestApp{app: application, api: api, serverURL: server.URL}
+}
+
+func slackSignature(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// 以表單送出 Slack 請求，signature 為空字串時以正確的金鑰簽署
+func (a *slackTestApp) post(t *testing.T, path string, form url.Values, timestamp time.Time, signature string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := form.Encode()
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	if signature == "" {
+		signature = slackSignature("slack-secret", ts, body)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", signature)
+	a.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func (a *slackTestApp) command(t *testing.T, userID, text string) map[string]any {
+	t.Helper()
+	rec := a.post(t, "/webhooks/slack/commands", url.Values{
+		"channel_id":   {"C1"},
+		"user_id":      {userID},
+		"command":      {"/what2eat"},
+		"text":         {text},
+		"response_url": {a.serverURL + "/respond"},
+	}, time.Now(), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("command %q: status %d: %s", text, rec.Code, rec.Body.String())
+	}
+	var reply map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+		t.Fatal(err)
+	}
+	return reply
+}
+
+func (a *slackTestApp) click(t *testing.T, userID, actionID, value string) {
+	t.Helper()
+	payload, _ := json.Marshal(map[string]any{
+		"type":         "block_actions",
+		"user":         map[string]any{"id": userID, "name": userID},
+		"channel":      map[string]any{"id": "C1"},
+		"message":      map[string]any{"ts": "1700000000.000100"},
+		"actions":      []map[string]any{{"action_id": actionID, "value": value}},
+		"response_url": a.serverURL + "/respond",
+	})
+	if rec := a.post(t, "/webhooks/slack/interactions", url.Values{"payload": {string(payload)}}, time.Now(), ""); rec.Code != http.StatusOK {
+		t.Fatalf("interaction %s: status %d", actionID, rec.Code)
+	}
+}
+
+func TestSlackRejectsInvalidSignature(t *testing.T) {
+	application := newSlackTestApp(t)
+	form := url.Values{"channel_id": {"C1"}, "user_id": {"U1"}, "text": {"help"}}
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		signature string
+	}{
+		{name: "其他金鑰簽署", timestamp: now, signature: slackSignature("other-secret", strconv.FormatInt(now.Unix(), 10), form.Encode())},
+		{name: "時間戳記過舊", timestamp: now.Add(-10 * time.Minute)},
+		{name: "缺少簽章", timestamp: now, signature: "v0="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := application.post(t, "/webhooks/slack/commands", form, tt.timestamp, tt.signature); rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", rec.Code)
+			}
+		})
+	}
+
+	if rec := application.post(t, "/webhooks/slack/commands", form, now, ""); rec.Code != http.StatusOK {
+		t.Errorf("signed request: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestSlackPollFlow(t *testing.T) {
+	application := newSlackTestApp(t)
+
+	if reply := application.command(t, "U1", ""); !strings.Contains(reply["text"].(string), "location") {
+		t.Fatalf("reply without location = %v", reply)
+	}
+	if reply := application.command(t, "U1", "location 25.0330,121.5654"); reply["response_type"] != "in_channel" {
+		t.Fatalf("location reply = %v", reply)
+	}
+
+	if reply := application.command(t, "U1", ""); reply["response_type"] != "ephemeral" {
+		t.Fatalf("poll reply = %v", reply)
+	}
+	posted := application.api.waitFor(t, "chat.postMessage")
+	if posted.Authorization != "Bearer xoxb-test" || posted.Body["channel"] != "C1" {
+		t.Fatalf("poll message = %+v", posted)
+	}
+
+	var blocks []struct {
+		Accessory struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"accessory"`
+	}
+	raw, _ := json.Marshal(posted.Body["blocks"])
+	json.Unmarshal(raw, &blocks)
+	var voteAction, placeID string
+	for _, block := range blocks {
+		if strings.HasPrefix(block.Accessory.ActionID, "vote_") {
+			voteAction, placeID = block.Accessory.ActionID, block.Accessory.Value
+			break
+		}
+	}
+	if placeID == "" {
+		t.Fatalf("poll message has no vote buttons: %s", raw)
+	}
+
+	// 投票訊息發送後才保存投票並開始監看，重試直到投票反映在訊息上
+	voted := false
+	for attempt := 0; !voted && attempt < 20; attempt++ {
+		application.click(t, "U2", voteAction, placeID)
+		for waiting := true; waiting && !voted; {
+			select {
+			case call := <-application.api.calls:
+				raw, _ := json.Marshal(call.Body["blocks"])
+				voted = call.Method == "chat.update" && strings.Contains(string(raw), "已投票（1 人）：U2")
+			case <-time.After(100 * time.Millisecond):
+				waiting = false
+			}
+		}
+	}
+	if !voted {
+		t.Fatal("poll message was not updated with the vote")
+	}
+
+	// 其他人不能結束投票
+	application.click(t, "U2", "close_poll", "")
+	if respond := application.api.waitFor(t, "respond"); !strings.Contains(respond.Body["text"].(string), "只有發起投票的人") {
+		t.Errorf("close by other user = %v", respond.Body)
+	}
+
+	application.click(t, "U1", "close_poll", "")
+	result := application.api.waitFor(t, "chat.postMessage")
+	if text := result.Body["text"].(string); !strings.Contains(text, "午餐投票結果") || !strings.Contains(text, placeID) {
+		t.Errorf("result = %q, want winner %s", text, placeID)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type sseEvent struct {
+	name string
+	data map[string]any
+}
+
+// 讀取下一個 SSE 事件，串流結束時返回 false
+func readEvent(t *testing.T, scanner *bufio.Scanner) (sseEvent, bool) {
+	t.Helper()
+	var event sseEvent
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event.name = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event.data); err != nil {
+				t.Fatalf("invalid event data %q: %v", line, err)
+			}
+		case line == "" && event.name != "":
+			return event, true
+		}
+	}
+	return event, false
+}
+
+func openStream(t *testing.T, ctx context.Context, server *httptest.Server, query string) *bufio.Scanner {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/restaurants/stream?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return bufio.NewScanner(resp.Body)
+}
+
+func TestRestaurantStreamEvents(t *testing.T) {
+	application := newTestApp(t, map[string]string{"DAILY_API_LIMIT": "600"})
+	server := httptest.NewServer(application.router)
+	t.Cleanup(server.Close)
+
+	scanner := openStream(t, context.Background(), server, "lat=25.0330&lng=121.5654")
+
+	var names []string
+	var events []sseEvent
+	for {
+		event, ok := readEvent(t, scanner)
+		if !ok {
+			break
+		}
+		names = append(names, event.name)
+		events = append(events, event)
+	}
+
+	want := []string{"started", "candidates", "pick", "pick", "pick", "done"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %v, want %v", names, want)
+	}
+	if events[0].data["cache"] != "miss" {
+		t.Errorf("started = %v, want cache miss", events[0].data)
+	}
+	if events[1].data["count"] != float64(8) {
+		t.Errorf("candidates = %v, want 8", events[1].data)
+	}
+	for i, event := range events[2:5] {
+		restaurant, _ := event.data["restaurant"].(map[string]any)
+		if event.data["index"] != float64(i) || restaurant["place_id"] == "" {
+			t.Errorf("pick %d = %v", i, event.data)
+		}
+	}
+	if events[5].data["count"] != float64(3) || events[5].data["usage"] != "1/600" {
+		t.Errorf("done = %v", events[5].data)
+	}
+}
+
+// 客戶端中斷連線後，伺服器不再繼續後續的名稱關鍵字搜尋
+func TestRestaurantStreamCancelsOnDisconnect(t *testing.T) {
+	var nameSearches atomic.Int32
+	upstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 主要搜尋沒有結果，觸發逐一的名稱關鍵字搜尋，每次都很慢
+		if r.URL.Query().Get("name") != "" {
+			nameSearches.Add(1)
+			select {
+			case <-time.After(200 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ZERO_RESULTS","results":[]}`))
+	})
+	application := newTestAppWithUpstream(t, nil, upstream)
+	server := httptest.NewServer(application.router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scanner := openStream(t, ctx, server, "lat=25.0330&lng=121.5654&type="+url.QueryEscape("火鍋"))
+	if event, ok := readEvent(t, scanner); !ok || event.name != "started" {
+		t.Fatalf("first event = %v", event)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	cancel()
+
+	// 進行中的請求會被取消，之後不應再有新的名稱搜尋
+	time.Sleep(100 * time.Millisecond)
+	afterCancel := nameSearches.Load()
+	time.Sleep(600 * time.Millisecond)
+	if got := nameSearches.Load(); got != afterCancel {
+		t.Errorf("name searches continued after disconnect: %d -> %d", afterCancel, got)
+	}
+	if afterCancel >= 10 {
+		t.Errorf("all %d name searches ran before the disconnect took effect", afterCancel)
+	}
+}
@@ -1,15 +1,181 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
 	GoogleMapsAPIKey string
 	Port             string
+	GRPCPort         string // gRPC 服務的端口，空字串表示不啟動
 	DailyAPILimit    int
+	Scoring          ScoringConfig
+	Diversity        DiversityConfig
+	Room             RoomConfig
+	Favorites        FavoritesConfig
+	Auth             AuthConfig
+	History          HistoryConfig
+	Feedback         FeedbackConfig
+	Line             LineConfig
+	Slack            SlackConfig
+	Scheduler        SchedulerConfig
+	Share            ShareConfig
+	Travel           TravelConfig
+	Geocode          GeocodeConfig
+	AreaSearch       AreaSearchConfig
+	OpenAPI          OpenAPIConfig
+}
+
+// HistoryConfig 用餐紀錄與「最近吃過」推薦規則的參數
+type HistoryConfig struct {
+	StoreFile     string        // 持久化檔案路徑，空字串表示只保存在記憶體
+	PlaceWindow   time.Duration // 同一家餐廳在此期間內吃過就不再推薦
+	CuisineWindow time.Duration // 同一種料理在此期間內吃過就不再推薦
+	Mode          string        // downrank：排到其他候選之後；exclude：直接排除
+	Timezone      string        // 每週摘要使用的時區
+}
+
+// FeedbackConfig 使用者回饋與偏好的參數
+type FeedbackConfig struct {
+	StoreFile        string  // 持久化檔案路徑，空字串表示只保存在記憶體
+	PreferenceWeight float64 // 偏好對推薦權重的影響程度，0 表示不調整
+}
+
+// LineConfig LINE Messaging API 的參數，未設定 ChannelSecret 時停用 webhook
+type LineConfig struct {
+	ChannelSecret      string
+	ChannelAccessToken string
+	APIBaseURL         string // 可指向本地的假 LINE 伺服器以便測試
+}
+
+// SlackConfig Slack 指令與互動投票的參數，未設定 SigningSecret 時停用
+type SlackConfig struct {
+	SigningSecret    string
+	BotToken         string
+	APIBaseURL       string        // 可指向本地的 Slack 替身伺服器以便測試
+	StoreFile        string        // 頻道位置與投票對應的持久化檔案，空字串表示只保存在記憶體
+	ChannelLocations string        // 各頻道的辦公室位置，格式 "C123=25.0330,121.5654;C456=..."
+	DefaultLocation  string        // 未設定頻道位置時使用的位置，格式 "25.0330,121.5654"
+	PollDuration     time.Duration // 投票時間
+}
+
+// SchedulerConfig 定期推送午餐推薦的參數
+type SchedulerConfig struct {
+	StoreFile       string        // 排程與發送紀錄的持久化檔案，空字串表示只保存在記憶體
+	Timezone        string        // 排程未指定時區時使用的時區
+	Holidays        string        // 不發送的日期，格式 "2026-01-01,2026-02-16"
+	MaxAttempts     int           // 每次發送的最多嘗試次數
+	RetryDelay      time.Duration // 第一次重試前的等待時間，之後每次加倍
+	DeliveryHistory int           // 每個排程保留的發送紀錄筆數，0 表示不清除
+	MaxPerUser      int           // 每個使用者最多的排程數量
+	AllowPrivateURL bool          // 允許 webhook 目標連線到內部網路位址，僅供本地開發與測試
+}
+
+// TravelConfig 交通時間計算的參數
+type TravelConfig struct {
+	Provider     string        // google：Google Distance Matrix；estimate：只以直線距離估算
+	DailyLimit   int           // Distance Matrix 每日可查詢的目的地數量，與附近搜尋的額度分開計算
+	CacheTTL     time.Duration // 交通時間的快取時間
+	CellDecimals int           // 起點格子的座標小數位數，3 位約 100 公尺
+}
+
+// GeocodeConfig 地址與地標解析的參數
+type GeocodeConfig struct {
+	Provider      string        // google：本地地名資料查無時改用 Google Geocoding；gazetteer：只使用本地地名資料
+	GazetteerFile string        // 補充的地名資料檔案（JSON），空字串只使用內建地標
+	CacheFile     string        // 解析結果的快取檔案，空字串表示只保存在記憶體
+	CacheTTL      time.Duration // 解析結果的快取時間
+	DailyLimit    int           // Geocoding API 每日請求數，與附近搜尋的額度分開計算
+	Region        string        // 偏好的國家代碼，用於消除同名地點的歧義
+
+	DistrictDataset string // 行政區邊界的 GeoJSON 檔案，空字串使用內建的臺北市簡化邊界
+	AreaPrecision   int    // 區域標籤快取的 geohash 長度，6 約 1.2km × 0.6km
+}
+
+// AreaSearchConfig 地圖範圍搜尋的參數
+type AreaSearchConfig struct {
+	TileMeters       float64 // 將範圍切成搜尋格子的邊長（公尺）
+	MaxTiles         int     // 單次請求最多的格子數，超過表示範圍太大
+	MaxUpstreamCalls int     // 單次請求最多呼叫 Google API 的格子數，已緩存的格子不計
+}
+
+// OpenAPIConfig API 文件 (/openapi.json) 的參數
+type OpenAPIConfig struct {
+	ValidateRequests bool // 依 API 文件檢查 /api 與 /api/v2 的查詢參數，不符合時直接回應 400
+}
+
+// ShareConfig 分享推薦結果的參數
+type ShareConfig struct {
+	StoreFile          string        // 持久化檔案路徑，空字串表示只保存在記憶體
+	TTL                time.Duration // 分享連結的有效時間
+	CoordinateDecimals int           // 保存的座標小數位數，2 位約 1 公里
+	PublicBaseURL      string        // 後端對外的網址，用於預覽卡片的絕對網址
+	AppURL             string        // 前端網址，分享頁面會連到 {AppURL}?share={id}
+	CardCacheSize      int           // 快取的預覽卡片數量
+}
+
+// AuthConfig 使用者身分與帳號綁定的參數
+type AuthConfig struct {
+	TokenSecret  string        // 權杖簽章金鑰
+	StoreFile    string        // 帳號資料的持久化檔案路徑，空字串表示只保存在記憶體
+	MagicLinkURL string        // Email 登入連結指向的網址（前端頁面或 /api/auth/email/verify）
+	MagicLinkTTL time.Duration // Email 登入連結、OAuth state 與帳號綁定請求的有效時間
+
+	// OAuth 身分提供者：留空停用、local 為本地測試替身、oauth 為標準 OAuth 2.0
+	OAuthProvider     string
+	OAuthName         string
+	OAuthClientID     string
+	OAuthClientSecret string
+	OAuthAuthURL      string
+	OAuthTokenURL     string
+	OAuthUserInfoURL  string
+	OAuthRedirectURL  string
+	OAuthScopes       string
+
+	// SMTP 設定，未設定 SMTPHost 時登入連結只會輸出到日誌
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+}
+
+// FavoritesConfig 收藏功能的參數
+type FavoritesConfig struct {
+	StoreFile         string  // 持久化檔案路徑，空字串表示只保存在記憶體
+	MaxDistanceMeters float64 // 從收藏推薦時的預設最大距離（公尺）
+	MixedRatio        float64 // mixed 來源中收藏餐廳所佔比例 (0-1)
+}
+
+// RoomConfig 團體投票房間的參數
+type RoomConfig struct {
+	DefaultDeadline time.Duration // 預設投票時間
+	TTL             time.Duration // 投票結束後房間保留的時間
+	DefaultPoolSize int           // 預設候選餐廳數量
+	MaxPoolSize     int           // 候選餐廳數量上限
+	StoreFile       string        // 持久化檔案路徑，空字串表示只保存在記憶體
+}
+
+// DiversityConfig 推薦結果多樣性限制的參數
+type DiversityConfig struct {
+	MinSpacingMeters float64 // 推薦餐廳之間的最小距離（公尺），0 表示不限制
+}
+
+// ScoringConfig 加權推薦演算法的參數
+type ScoringConfig struct {
+	DefaultStrategy     string  // 未指定 strategy 參數時使用的策略 (random / weighted)
+	RatingWeight        float64 // 評價分數權重
+	DistanceWeight      float64 // 距離分數權重
+	PriceWeight         float64 // 價格符合度權重
+	JitterWeight        float64 // 隨機擾動權重
+	PriorRating         float64 // 貝氏平均的先驗評分
+	PriorReviews        float64 // 貝氏平均的先驗評論數
+	DistanceDecayMeters float64 // 距離衰減常數（公尺），距離每增加此值分數降為 1/e
 }
 
 func Load() *Config {
@@ -21,8 +187,136 @@ func Load() *Config {
 	return &Config{
 		GoogleMapsAPIKey: apiKey,
 		Port:             getEnv("PORT", "8080"),
+		GRPCPort:         getEnv("GRPC_PORT", ""),
 		DailyAPILimit:    getEnvInt("DAILY_API_LIMIT", 600),
+		Scoring: ScoringConfig{
+			DefaultStrategy:     getEnv("RECOMMEND_STRATEGY", "random"),
+			RatingWeight:        getEnvFloat("SCORE_RATING_WEIGHT", 0.4),
+			DistanceWeight:      getEnvFloat("SCORE_DISTANCE_WEIGHT", 0.3),
+			PriceWeight:         getEnvFloat("SCORE_PRICE_WEIGHT", 0.2),
+			JitterWeight:        getEnvFloat("SCORE_JITTER_WEIGHT", 0.1),
+			PriorRating:         getEnvFloat("SCORE_PRIOR_RATING", 4.0),
+			PriorReviews:        getEnvFloat("SCORE_PRIOR_REVIEWS", 50),
+			DistanceDecayMeters: getEnvFloat("SCORE_DISTANCE_DECAY_METERS", 800),
+		},
+		Diversity: DiversityConfig{
+			MinSpacingMeters: getEnvFloat("DIVERSITY_MIN_SPACING_METERS", 100),
+		},
+		Room: RoomConfig{
+			DefaultDeadline: time.Duration(getEnvInt("ROOM_DEADLINE_MINUTES", 15)) * time.Minute,
+			TTL:             time.Duration(getEnvInt("ROOM_TTL_MINUTES", 120)) * time.Minute,
+			DefaultPoolSize: getEnvInt("ROOM_POOL_SIZE", 6),
+			MaxPoolSize:     getEnvInt("ROOM_MAX_POOL_SIZE", 10),
+			StoreFile:       getEnv("ROOM_STORE_FILE", ""),
+		},
+		Favorites: FavoritesConfig{
+			StoreFile:         getEnv("FAVORITE_STORE_FILE", "data/favorites.json"),
+			MaxDistanceMeters: getEnvFloat("FAVORITE_MAX_DISTANCE_METERS", 3000),
+			MixedRatio:        getEnvFloat("FAVORITE_MIXED_RATIO", 0.34),
+		},
+		Auth: AuthConfig{
+			TokenSecret:       getTokenSecret(),
+			StoreFile:         getEnv("AUTH_STORE_FILE", "data/users.json"),
+			MagicLinkURL:      getEnv("MAGIC_LINK_URL", "http://localhost:8080/api/auth/email/verify"),
+			MagicLinkTTL:      time.Duration(getEnvInt("MAGIC_LINK_TTL_MINUTES", 15)) * time.Minute,
+			OAuthProvider:     getEnv("OAUTH_PROVIDER", ""),
+			OAuthName:         getEnv("OAUTH_NAME", "oauth"),
+			OAuthClientID:     getEnv("OAUTH_CLIENT_ID", ""),
+			OAuthClientSecret: getEnv("OAUTH_CLIENT_SECRET", ""),
+			OAuthAuthURL:      getEnv("OAUTH_AUTH_URL", ""),
+			OAuthTokenURL:     getEnv("OAUTH_TOKEN_URL", ""),
+			OAuthUserInfoURL:  getEnv("OAUTH_USERINFO_URL", ""),
+			OAuthRedirectURL:  getEnv("OAUTH_REDIRECT_URL", "http://localhost:8080/api/auth/oauth/callback"),
+			OAuthScopes:       getEnv("OAUTH_SCOPES", "openid email"),
+			SMTPHost:          getEnv("SMTP_HOST", ""),
+			SMTPPort:          getEnv("SMTP_PORT", "587"),
+			SMTPUsername:      getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:      getEnv("SMTP_PASSWORD", ""),
+			SMTPFrom:          getEnv("SMTP_FROM", ""),
+		},
+		History: HistoryConfig{
+			StoreFile:     getEnv("HISTORY_STORE_FILE", "data/history.json"),
+			PlaceWindow:   time.Duration(getEnvInt("HISTORY_PLACE_WINDOW_DAYS", 7)) * 24 * time.Hour,
+			CuisineWindow: time.Duration(getEnvInt("HISTORY_CUISINE_WINDOW_DAYS", 3)) * 24 * time.Hour,
+			Mode:          getEnv("HISTORY_MODE", "downrank"),
+			Timezone:      getEnv("HISTORY_TIMEZONE", "Asia/Taipei"),
+		},
+		Feedback: FeedbackConfig{
+			StoreFile:        getEnv("FEEDBACK_STORE_FILE", "data/feedback.json"),
+			PreferenceWeight: getEnvFloat("FEEDBACK_PREFERENCE_WEIGHT", 0.5),
+		},
+		Line: LineConfig{
+			ChannelSecret:      getEnv("LINE_CHANNEL_SECRET", ""),
+			ChannelAccessToken: getEnv("LINE_CHANNEL_ACCESS_TOKEN", ""),
+			APIBaseURL:         getEnv("LINE_API_BASE_URL", "https://api.line.me"),
+		},
+		Slack: SlackConfig{
+			SigningSecret:    getEnv("SLACK_SIGNING_SECRET", ""),
+			BotToken:         getEnv("SLACK_BOT_TOKEN", ""),
+			APIBaseURL:       getEnv("SLACK_API_BASE_URL", "https://slack.com/api"),
+			StoreFile:        getEnv("SLACK_STORE_FILE", "data/slack.json"),
+			ChannelLocations: getEnv("SLACK_CHANNEL_LOCATIONS", ""),
+			DefaultLocation:  getEnv("SLACK_DEFAULT_LOCATION", ""),
+			PollDuration:     time.Duration(getEnvInt("SLACK_POLL_MINUTES", 30)) * time.Minute,
+		},
+		Scheduler: SchedulerConfig{
+			StoreFile:       getEnv("SCHEDULER_STORE_FILE", "data/schedules.json"),
+			Timezone:        getEnv("SCHEDULER_TIMEZONE", "Asia/Taipei"),
+			Holidays:        getEnv("SCHEDULER_HOLIDAYS", ""),
+			MaxAttempts:     getEnvInt("SCHEDULER_MAX_ATTEMPTS", 3),
+			RetryDelay:      time.Duration(getEnvInt("SCHEDULER_RETRY_SECONDS", 60)) * time.Second,
+			DeliveryHistory: getEnvInt("SCHEDULER_DELIVERY_HISTORY", 50),
+			MaxPerUser:      getEnvInt("SCHEDULER_MAX_PER_USER", 10),
+			AllowPrivateURL: getEnvBool("SCHEDULER_ALLOW_PRIVATE_URLS", false),
+		},
+		Share: ShareConfig{
+			StoreFile:          getEnv("SHARE_STORE_FILE", "data/shares.json"),
+			TTL:                time.Duration(getEnvInt("SHARE_TTL_HOURS", 168)) * time.Hour,
+			CoordinateDecimals: getEnvInt("SHARE_COORDINATE_DECIMALS", 2),
+			PublicBaseURL:      getEnv("SHARE_PUBLIC_BASE_URL", "http://localhost:8080"),
+			AppURL:             getEnv("SHARE_APP_URL", "https://kevinsuu.github.io/what2eat/"),
+			CardCacheSize:      getEnvInt("SHARE_CARD_CACHE_SIZE", 200),
+		},
+		Travel: TravelConfig{
+			Provider:     getEnv("TRAVEL_PROVIDER", "google"),
+			DailyLimit:   getEnvInt("TRAVEL_DAILY_API_LIMIT", 300),
+			CacheTTL:     time.Duration(getEnvInt("TRAVEL_CACHE_MINUTES", 30)) * time.Minute,
+			CellDecimals: getEnvInt("TRAVEL_CELL_DECIMALS", 3),
+		},
+		AreaSearch: AreaSearchConfig{
+			TileMeters:       getEnvFloat("AREA_TILE_METERS", 400),
+			MaxTiles:         getEnvInt("AREA_MAX_TILES", 64),
+			MaxUpstreamCalls: getEnvInt("AREA_MAX_UPSTREAM_CALLS", 6),
+		},
+		Geocode: GeocodeConfig{
+			Provider:      getEnv("GEOCODE_PROVIDER", "google"),
+			GazetteerFile: getEnv("GEOCODE_GAZETTEER_FILE", ""),
+			CacheFile:     getEnv("GEOCODE_CACHE_FILE", "data/geocode_cache.json"),
+			CacheTTL:      time.Duration(getEnvInt("GEOCODE_CACHE_DAYS", 30)) * 24 * time.Hour,
+			DailyLimit:    getEnvInt("GEOCODE_DAILY_API_LIMIT", 200),
+			Region:        getEnv("GEOCODE_REGION", "tw"),
+
+			DistrictDataset: getEnv("REVERSE_GEOCODE_DATASET", ""),
+			AreaPrecision:   getEnvInt("REVERSE_GEOCODE_PRECISION", 6),
+		},
+		OpenAPI: OpenAPIConfig{
+			ValidateRequests: getEnvBool("OPENAPI_VALIDATE_REQUESTS", false),
+		},
+	}
+}
+
+// 取得權杖簽章金鑰，未設定時使用隨機金鑰（重啟後既有權杖將失效）
+func getTokenSecret() string {
+	if secret := os.Getenv("AUTH_TOKEN_SECRET"); secret != "" {
+		return secret
+	}
+
+	log.Println("警告: 未設定 AUTH_TOKEN_SECRET，使用隨機金鑰，伺服器重啟後既有權杖將失效")
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("無法產生權杖金鑰: %v", err)
 	}
+	return hex.EncodeToString(b)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -40,3 +334,21 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
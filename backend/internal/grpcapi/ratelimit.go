@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"what2eat-backend/internal/middleware"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimit gRPC 的流量限制，limiters 與 REST API 的中間件為同一組時，兩種協定計入同一份額度
+func RateLimit(limiters *middleware.RateLimiters) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !limiters.AllowGlobal() {
+			return nil, status.Error(codes.ResourceExhausted, "系統繁忙，請稍後再試")
+		}
+		if !limiters.AllowIP(peerIP(ctx)) || !limiters.AllowAPI() {
+			return nil, status.Error(codes.ResourceExhausted, "請求過於頻繁，請稍後再試")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// 取得客戶端的 IP，無法解析時使用完整的位址
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
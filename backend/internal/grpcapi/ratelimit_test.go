@@ -0,0 +1,53 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"what2eat-backend/internal/middleware"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func callRateLimit(t *testing.T, interceptor grpc.UnaryServerInterceptor, ip string) error {
+	t.Helper()
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50051}})
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	return err
+}
+
+// 攔截器使用傳入的限制器，與 REST 中間件共用同一組時額度互通，重建攔截器也不會重置額度
+func TestRateLimitSharesBuckets(t *testing.T) {
+	tests := []struct {
+		name  string
+		drain func(*middleware.RateLimiters) bool
+	}{
+		{"API 限制器", (*middleware.RateLimiters).AllowAPI},
+		{"全域限制器", (*middleware.RateLimiters).AllowGlobal},
+		{"IP 限制器", func(l *middleware.RateLimiters) bool { return l.AllowIP("198.51.100.10") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiters := middleware.NewRateLimiters()
+			for tt.drain(limiters) {
+			}
+
+			for _, interceptor := range []grpc.UnaryServerInterceptor{RateLimit(limiters), RateLimit(limiters)} {
+				if err := callRateLimit(t, interceptor, "198.51.100.10"); status.Code(err) != codes.ResourceExhausted {
+					t.Errorf("RateLimit() error = %v, want ResourceExhausted", err)
+				}
+			}
+
+			// 其他限制器組的額度不受影響
+			if err := callRateLimit(t, RateLimit(middleware.NewRateLimiters()), "198.51.100.10"); err != nil {
+				t.Errorf("fresh limiters error = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"what2eat-backend/internal/grpcapi/what2eatv1"
+	"what2eat-backend/internal/i18n"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+	"what2eat-backend/internal/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server What2Eat gRPC 服務，與 REST API 共用同一組 RestaurantService 與 CounterService
+type Server struct {
+	what2eatv1.UnimplementedWhat2EatServer
+	restaurantService *service.RestaurantService
+	counterService    *service.CounterService
+}
+
+func NewServer(restaurantService *service.RestaurantService, counterService *service.CounterService) *Server {
+	return &Server{
+		restaurantService: restaurantService,
+		counterService:    counterService,
+	}
+}
+
+// NewGRPCServer 建立已註冊 What2Eat 服務並以 limiters 套用流量限制的 gRPC 服務器
+func NewGRPCServer(srv *Server, limiters *middleware.RateLimiters, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(RateLimit(limiters))}, opts...)
+	grpcServer := grpc.NewServer(opts...)
+	what2eatv1.RegisterWhat2EatServer(grpcServer, srv)
+	return grpcServer
+}
+
+// Recommend 推薦附近的餐廳
+func (s *Server) Recommend(ctx context.Context, req *what2eatv1.RecommendRequest) (*what2eatv1.RecommendResponse, error) {
+	query, err := recommendQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.recommend(ctx, what2eatv1.What2Eat_Recommend_FullMethodName, query)
+}
+
+// Reroll 以相同條件重新推薦，排除上一批的餐廳
+func (s *Server) Reroll(ctx context.Context, req *what2eatv1.RerollRequest) (*what2eatv1.RecommendResponse, error) {
+	if req.GetQuery() == nil {
+		return nil, status.Error(codes.InvalidArgument, "缺少推薦條件")
+	}
+	query, err := recommendQuery(req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	query.ExcludePlaceIDs = req.GetExcludePlaceIds()
+	return s.recommend(ctx, what2eatv1.What2Eat_Reroll_FullMethodName, query)
+}
+
+// GetCategories 返回支援的餐廳類型
+func (s *Server) GetCategories(ctx context.Context, req *what2eatv1.GetCategoriesRequest) (*what2eatv1.GetCategoriesResponse, error) {
+	categories := make([]string, len(repository.RestaurantTypes))
+	copy(categories, repository.RestaurantTypes)
+	return &what2eatv1.GetCategoriesResponse{Categories: categories}, nil
+}
+
+// GetUsage 返回每日額度使用量，不呼叫 Google API
+func (s *Server) GetUsage(ctx context.Context, req *what2eatv1.GetUsageRequest) (*what2eatv1.GetUsageResponse, error) {
+	return &what2eatv1.GetUsageResponse{
+		Usage:         s.usage(),
+		LimitExceeded: s.counterService.IsLimitExceeded(),
+	}, nil
+}
+
+// 檢查每日額度後呼叫推薦流程，請求結果與 REST API 記錄在同一份日誌
+func (s *Server) recommend(ctx context.Context, endpoint string, query model.RecommendQuery) (*what2eatv1.RecommendResponse, error) {
+	fmt.Printf("接收到 gRPC 餐廳搜尋請求: 位置 [%.4f, %.4f], 類型: %s\n", query.Lat, query.Lng, query.RestaurantType)
+
+	if err := s.counterService.CheckDailyLimit(); err != nil {
+		s.counterService.LogAPIRequest(endpoint, query.Lat, query.Lng, query.RestaurantType, false, err.Error())
+		return nil, statusError(err)
+	}
+
+	result, err := s.restaurantService.RecommendRestaurants(ctx, query)
+	if err != nil {
+		errMsg := fmt.Sprintf("餐廳搜尋錯誤: %v", err)
+		fmt.Printf("%s\n", errMsg)
+		s.counterService.LogAPIRequest(endpoint, query.Lat, query.Lng, query.RestaurantType, false, errMsg)
+		// Google API 回報額度用完時標記為超過限制，之後的請求不再呼叫
+		if errors.Is(err, model.ErrUpstreamQuota) {
+			s.counterService.SetLimitExceeded(true)
+		}
+		return nil, statusError(err)
+	}
+	s.counterService.LogAPIRequest(endpoint, query.Lat, query.Lng, query.RestaurantType, true, "")
+
+	return &what2eatv1.RecommendResponse{
+		Restaurants: toRestaurants(result.Restaurants),
+		Diversity: &what2eatv1.Diversity{
+			Relaxed:            result.Diversity.Relaxed,
+			RelaxedConstraints: result.Diversity.RelaxedConstraints,
+		},
+		Area:  toArea(result.Area),
+		Usage: s.usage(),
+	}, nil
+}
+
+func (s *Server) usage() *what2eatv1.Usage {
+	used, limit := s.counterService.GetUsage()
+	return &what2eatv1.Usage{
+		Used:           int32(used),
+		Limit:          int32(limit),
+		Remaining:      int32(max(limit-used, 0)),
+		ResetInSeconds: int64(s.counterService.GetTimeUntilReset().Seconds()),
+	}
+}
+
+// 檢查請求參數並轉為推薦條件，規則與 REST API 相同
+func recommendQuery(req *what2eatv1.RecommendRequest) (model.RecommendQuery, error) {
+	query := model.RecommendQuery{
+		Lat:            req.GetLat(),
+		Lng:            req.GetLng(),
+		RestaurantType: req.GetType(),
+		Strategy:       req.GetStrategy(),
+		Budget:         -1,
+		Language:       i18n.NormalizeLang(req.GetLanguage()),
+		Source:         model.SourceNearby,
+		TravelMode:     req.GetTravelMode(),
+	}
+
+	if query.Strategy != "" && query.Strategy != model.StrategyRandom && query.Strategy != model.StrategyWeighted {
+		return query, status.Error(codes.InvalidArgument, "無效的推薦策略參數，僅支援 random 或 weighted")
+	}
+	if req.Budget != nil {
+		if req.GetBudget() < 0 || req.GetBudget() > 4 {
+			return query, status.Error(codes.InvalidArgument, "無效的預算參數，需為 0-4 的價格等級")
+		}
+		query.Budget = int(req.GetBudget())
+	}
+	if query.TravelMode != "" && !service.IsValidTravelMode(query.TravelMode) {
+		return query, status.Error(codes.InvalidArgument, "無效的交通方式參數，僅支援 walking、driving 或 transit")
+	}
+	return query, nil
+}
+
+// 將錯誤對應到 gRPC 狀態碼，錯誤類別與 REST API 的 HTTP 狀態碼一一對應
+func statusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, model.ErrUnauthorized):
+		code = codes.Unauthenticated
+	case errors.Is(err, model.ErrInvalidLocation):
+		code = codes.InvalidArgument
+	case errors.Is(err, model.ErrQuotaExceeded), errors.Is(err, model.ErrUpstreamQuota):
+		code = codes.ResourceExhausted
+	case errors.Is(err, model.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, model.ErrUpstreamUnavailable):
+		code = codes.Unavailable
+	case errors.Is(err, model.ErrNoResults):
+		code = codes.NotFound
+	}
+	return status.Error(code, err.Error())
+}
+
+func toRestaurants(restaurants []model.Restaurant) []*what2eatv1.Restaurant {
+	result := make([]*what2eatv1.Restaurant, len(restaurants))
+	for i, r := range restaurants {
+		restaurant := &what2eatv1.Restaurant{
+			PlaceId:          r.PlaceID,
+			Name:             r.Name,
+			Rating:           r.Rating,
+			UserRatingsTotal: int32(r.UserRatingsTotal),
+			Distance:         r.Distance,
+			DistanceMeters:   r.DistanceMeters,
+			Lat:              r.Lat,
+			Lng:              r.Lng,
+			Address:          r.Address,
+			PhotoUrl:         r.PhotoURL,
+			PriceLevel:       int32(r.PriceLevel),
+			AveragePrice:     r.AveragePrice,
+			RestaurantType:   r.RestaurantType,
+			Score:            r.Score,
+			Reasons:          make([]*what2eatv1.Reason, len(r.Reasons)),
+		}
+		for j, reason := range r.Reasons {
+			restaurant.Reasons[j] = &what2eatv1.Reason{
+				Code:    reason.Code,
+				Stage:   reason.Stage,
+				Params:  reason.Params,
+				Message: reason.Message,
+			}
+		}
+		if r.Travel != nil {
+			restaurant.Travel = &what2eatv1.TravelTime{
+				Mode:            r.Travel.Mode,
+				DurationSeconds: int32(r.Travel.DurationSeconds),
+				DistanceMeters:  int32(r.Travel.DistanceMeters),
+				Provider:        r.Travel.Provider,
+				Estimated:       r.Travel.Estimated,
+				Message:         r.Travel.Message,
+			}
+		}
+		result[i] = restaurant
+	}
+	return result
+}
+
+func toArea(area *model.Area) *what2eatv1.Area {
+	if area == nil {
+		return nil
+	}
+	return &what2eatv1.Area{
+		District: area.District,
+		City:     area.City,
+		Country:  area.Country,
+		Source:   area.Source,
+	}
+}
@@ -0,0 +1,1080 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: what2eat/v1/what2eat.proto
+
+package what2eatv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RecommendRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Lat   float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lng   float64                `protobuf:"fixed64,2,opt,name=lng,proto3" json:"lng,omitempty"`
+	// 餐廳類型，空字串表示不限，可用類型見 GetCategories
+	Type string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	// 推薦策略 (random / weighted)，空字串使用伺服器預設值
+	Strategy string `protobuf:"bytes,4,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	// 預算 (價格等級 0-4)，未指定表示不限
+	Budget *int32 `protobuf:"varint,5,opt,name=budget,proto3,oneof" json:"budget,omitempty"`
+	// 交通方式 (walking / driving / transit)，空字串只提供直線距離
+	TravelMode string `protobuf:"bytes,6,opt,name=travel_mode,json=travelMode,proto3" json:"travel_mode,omitempty"`
+	// 推薦理由的語系 (zh-TW / en)，空字串使用預設語系
+	Language      string `protobuf:"bytes,7,opt,name=language,proto3" json:"language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecommendRequest) Reset() {
+	*x = RecommendRequest{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendRequest) ProtoMessage() {}
+
+func (x *RecommendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendRequest.ProtoReflect.Descriptor instead.
+func (*RecommendRequest) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RecommendRequest) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetLng() float64 {
+	if x != nil {
+		return x.Lng
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RecommendRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *RecommendRequest) GetBudget() int32 {
+	if x != nil && x.Budget != nil {
+		return *x.Budget
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetTravelMode() string {
+	if x != nil {
+		return x.TravelMode
+	}
+	return ""
+}
+
+func (x *RecommendRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type RerollRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query *RecommendRequest      `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// 上一批推薦的餐廳，這次不會再推薦
+	ExcludePlaceIds []string `protobuf:"bytes,2,rep,name=exclude_place_ids,json=excludePlaceIds,proto3" json:"exclude_place_ids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RerollRequest) Reset() {
+	*x = RerollRequest{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerollRequest) ProtoMessage() {}
+
+func (x *RerollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RerollRequest.ProtoReflect.Descriptor instead.
+func (*RerollRequest) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RerollRequest) GetQuery() *RecommendRequest {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *RerollRequest) GetExcludePlaceIds() []string {
+	if x != nil {
+		return x.ExcludePlaceIds
+	}
+	return nil
+}
+
+type RecommendResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Restaurants []*Restaurant          `protobuf:"bytes,1,rep,name=restaurants,proto3" json:"restaurants,omitempty"`
+	Diversity   *Diversity             `protobuf:"bytes,2,opt,name=diversity,proto3" json:"diversity,omitempty"`
+	// 查詢位置所在的行政區，無法判斷時為空
+	Area          *Area  `protobuf:"bytes,3,opt,name=area,proto3" json:"area,omitempty"`
+	Usage         *Usage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecommendResponse) Reset() {
+	*x = RecommendResponse{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendResponse) ProtoMessage() {}
+
+func (x *RecommendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendResponse.ProtoReflect.Descriptor instead.
+func (*RecommendResponse) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RecommendResponse) GetRestaurants() []*Restaurant {
+	if x != nil {
+		return x.Restaurants
+	}
+	return nil
+}
+
+func (x *RecommendResponse) GetDiversity() *Diversity {
+	if x != nil {
+		return x.Diversity
+	}
+	return nil
+}
+
+func (x *RecommendResponse) GetArea() *Area {
+	if x != nil {
+		return x.Area
+	}
+	return nil
+}
+
+func (x *RecommendResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type Restaurant struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PlaceId          string                 `protobuf:"bytes,1,opt,name=place_id,json=placeId,proto3" json:"place_id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Rating           float32                `protobuf:"fixed32,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	UserRatingsTotal int32                  `protobuf:"varint,4,opt,name=user_ratings_total,json=userRatingsTotal,proto3" json:"user_ratings_total,omitempty"`
+	Distance         string                 `protobuf:"bytes,5,opt,name=distance,proto3" json:"distance,omitempty"`
+	DistanceMeters   float64                `protobuf:"fixed64,6,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	Lat              float64                `protobuf:"fixed64,7,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lng              float64                `protobuf:"fixed64,8,opt,name=lng,proto3" json:"lng,omitempty"`
+	Address          string                 `protobuf:"bytes,9,opt,name=address,proto3" json:"address,omitempty"`
+	PhotoUrl         string                 `protobuf:"bytes,10,opt,name=photo_url,json=photoUrl,proto3" json:"photo_url,omitempty"`
+	PriceLevel       int32                  `protobuf:"varint,11,opt,name=price_level,json=priceLevel,proto3" json:"price_level,omitempty"`
+	AveragePrice     string                 `protobuf:"bytes,12,opt,name=average_price,json=averagePrice,proto3" json:"average_price,omitempty"`
+	RestaurantType   string                 `protobuf:"bytes,13,opt,name=restaurant_type,json=restaurantType,proto3" json:"restaurant_type,omitempty"`
+	Score            float64                `protobuf:"fixed64,14,opt,name=score,proto3" json:"score,omitempty"`
+	Reasons          []*Reason              `protobuf:"bytes,15,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	// 只在請求指定 travel_mode 時提供
+	Travel        *TravelTime `protobuf:"bytes,16,opt,name=travel,proto3" json:"travel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Restaurant) Reset() {
+	*x = Restaurant{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Restaurant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Restaurant) ProtoMessage() {}
+
+func (x *Restaurant) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Restaurant.ProtoReflect.Descriptor instead.
+func (*Restaurant) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Restaurant) GetPlaceId() string {
+	if x != nil {
+		return x.PlaceId
+	}
+	return ""
+}
+
+func (x *Restaurant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Restaurant) GetRating() float32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Restaurant) GetUserRatingsTotal() int32 {
+	if x != nil {
+		return x.UserRatingsTotal
+	}
+	return 0
+}
+
+func (x *Restaurant) GetDistance() string {
+	if x != nil {
+		return x.Distance
+	}
+	return ""
+}
+
+func (x *Restaurant) GetDistanceMeters() float64 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+func (x *Restaurant) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Restaurant) GetLng() float64 {
+	if x != nil {
+		return x.Lng
+	}
+	return 0
+}
+
+func (x *Restaurant) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Restaurant) GetPhotoUrl() string {
+	if x != nil {
+		return x.PhotoUrl
+	}
+	return ""
+}
+
+func (x *Restaurant) GetPriceLevel() int32 {
+	if x != nil {
+		return x.PriceLevel
+	}
+	return 0
+}
+
+func (x *Restaurant) GetAveragePrice() string {
+	if x != nil {
+		return x.AveragePrice
+	}
+	return ""
+}
+
+func (x *Restaurant) GetRestaurantType() string {
+	if x != nil {
+		return x.RestaurantType
+	}
+	return ""
+}
+
+func (x *Restaurant) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Restaurant) GetReasons() []*Reason {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *Restaurant) GetTravel() *TravelTime {
+	if x != nil {
+		return x.Travel
+	}
+	return nil
+}
+
+type Reason struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Stage         string                 `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`
+	Params        map[string]string      `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reason) Reset() {
+	*x = Reason{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reason) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reason) ProtoMessage() {}
+
+func (x *Reason) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reason.ProtoReflect.Descriptor instead.
+func (*Reason) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Reason) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Reason) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Reason) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Reason) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TravelTime struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Mode            string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	DurationSeconds int32                  `protobuf:"varint,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	DistanceMeters  int32                  `protobuf:"varint,3,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	Provider        string                 `protobuf:"bytes,4,opt,name=provider,proto3" json:"provider,omitempty"`
+	Estimated       bool                   `protobuf:"varint,5,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	Message         string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TravelTime) Reset() {
+	*x = TravelTime{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TravelTime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TravelTime) ProtoMessage() {}
+
+func (x *TravelTime) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TravelTime.ProtoReflect.Descriptor instead.
+func (*TravelTime) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TravelTime) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *TravelTime) GetDurationSeconds() int32 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *TravelTime) GetDistanceMeters() int32 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+func (x *TravelTime) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *TravelTime) GetEstimated() bool {
+	if x != nil {
+		return x.Estimated
+	}
+	return false
+}
+
+func (x *TravelTime) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Diversity struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Relaxed            bool                   `protobuf:"varint,1,opt,name=relaxed,proto3" json:"relaxed,omitempty"`
+	RelaxedConstraints []string               `protobuf:"bytes,2,rep,name=relaxed_constraints,json=relaxedConstraints,proto3" json:"relaxed_constraints,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Diversity) Reset() {
+	*x = Diversity{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Diversity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diversity) ProtoMessage() {}
+
+func (x *Diversity) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diversity.ProtoReflect.Descriptor instead.
+func (*Diversity) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Diversity) GetRelaxed() bool {
+	if x != nil {
+		return x.Relaxed
+	}
+	return false
+}
+
+func (x *Diversity) GetRelaxedConstraints() []string {
+	if x != nil {
+		return x.RelaxedConstraints
+	}
+	return nil
+}
+
+type Area struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	District      string                 `protobuf:"bytes,1,opt,name=district,proto3" json:"district,omitempty"`
+	City          string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Area) Reset() {
+	*x = Area{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Area) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Area) ProtoMessage() {}
+
+func (x *Area) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Area.ProtoReflect.Descriptor instead.
+func (*Area) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Area) GetDistrict() string {
+	if x != nil {
+		return x.District
+	}
+	return ""
+}
+
+func (x *Area) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Area) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Area) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetCategoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoriesRequest) Reset() {
+	*x = GetCategoriesRequest{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoriesRequest) ProtoMessage() {}
+
+func (x *GetCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{8}
+}
+
+type GetCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []string               `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoriesResponse) Reset() {
+	*x = GetCategoriesResponse{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoriesResponse) ProtoMessage() {}
+
+func (x *GetCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*GetCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCategoriesResponse) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{10}
+}
+
+type GetUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usage         *Usage                 `protobuf:"bytes,1,opt,name=usage,proto3" json:"usage,omitempty"`
+	LimitExceeded bool                   `protobuf:"varint,2,opt,name=limit_exceeded,json=limitExceeded,proto3" json:"limit_exceeded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetUsageResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetLimitExceeded() bool {
+	if x != nil {
+		return x.LimitExceeded
+	}
+	return false
+}
+
+type Usage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Used           int32                  `protobuf:"varint,1,opt,name=used,proto3" json:"used,omitempty"`
+	Limit          int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Remaining      int32                  `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	ResetInSeconds int64                  `protobuf:"varint,4,opt,name=reset_in_seconds,json=resetInSeconds,proto3" json:"reset_in_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Usage) Reset() {
+	*x = Usage{}
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Usage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Usage) ProtoMessage() {}
+
+func (x *Usage) ProtoReflect() protoreflect.Message {
+	mi := &file_what2eat_v1_what2eat_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Usage.ProtoReflect.Descriptor instead.
+func (*Usage) Descriptor() ([]byte, []int) {
+	return file_what2eat_v1_what2eat_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Usage) GetUsed() int32 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *Usage) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *Usage) GetRemaining() int32 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *Usage) GetResetInSeconds() int64 {
+	if x != nil {
+		return x.ResetInSeconds
+	}
+	return 0
+}
+
+var File_what2eat_v1_what2eat_proto protoreflect.FileDescriptor
+
+const file_what2eat_v1_what2eat_proto_rawDesc = "" +
+	"\n" +
+	"\x1awhat2eat/v1/what2eat.proto\x12\vwhat2eat.v1\"\xcb\x01\n" +
+	"\x10RecommendRequest\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lng\x18\x02 \x01(\x01R\x03lng\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1a\n" +
+	"\bstrategy\x18\x04 \x01(\tR\bstrategy\x12\x1b\n" +
+	"\x06budget\x18\x05 \x01(\x05H\x00R\x06budget\x88\x01\x01\x12\x1f\n" +
+	"\vtravel_mode\x18\x06 \x01(\tR\n" +
+	"travelMode\x12\x1a\n" +
+	"\blanguage\x18\a \x01(\tR\blanguageB\t\n" +
+	"\a_budget\"p\n" +
+	"\rRerollRequest\x123\n" +
+	"\x05query\x18\x01 \x01(\v2\x1d.what2eat.v1.RecommendRequestR\x05query\x12*\n" +
+	"\x11exclude_place_ids\x18\x02 \x03(\tR\x0fexcludePlaceIds\"\xd5\x01\n" +
+	"\x11RecommendResponse\x129\n" +
+	"\vrestaurants\x18\x01 \x03(\v2\x17.what2eat.v1.RestaurantR\vrestaurants\x124\n" +
+	"\tdiversity\x18\x02 \x01(\v2\x16.what2eat.v1.DiversityR\tdiversity\x12%\n" +
+	"\x04area\x18\x03 \x01(\v2\x11.what2eat.v1.AreaR\x04area\x12(\n" +
+	"\x05usage\x18\x04 \x01(\v2\x12.what2eat.v1.UsageR\x05usage\"\x86\x04\n" +
+	"\n" +
+	"Restaurant\x12\x19\n" +
+	"\bplace_id\x18\x01 \x01(\tR\aplaceId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x02R\x06rating\x12,\n" +
+	"\x12user_ratings_total\x18\x04 \x01(\x05R\x10userRatingsTotal\x12\x1a\n" +
+	"\bdistance\x18\x05 \x01(\tR\bdistance\x12'\n" +
+	"\x0fdistance_meters\x18\x06 \x01(\x01R\x0edistanceMeters\x12\x10\n" +
+	"\x03lat\x18\a \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lng\x18\b \x01(\x01R\x03lng\x12\x18\n" +
+	"\aaddress\x18\t \x01(\tR\aaddress\x12\x1b\n" +
+	"\tphoto_url\x18\n" +
+	" \x01(\tR\bphotoUrl\x12\x1f\n" +
+	"\vprice_level\x18\v \x01(\x05R\n" +
+	"priceLevel\x12#\n" +
+	"\raverage_price\x18\f \x01(\tR\faveragePrice\x12'\n" +
+	"\x0frestaurant_type\x18\r \x01(\tR\x0erestaurantType\x12\x14\n" +
+	"\x05score\x18\x0e \x01(\x01R\x05score\x12-\n" +
+	"\areasons\x18\x0f \x03(\v2\x13.what2eat.v1.ReasonR\areasons\x12/\n" +
+	"\x06travel\x18\x10 \x01(\v2\x17.what2eat.v1.TravelTimeR\x06travel\"\xc0\x01\n" +
+	"\x06Reason\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x14\n" +
+	"\x05stage\x18\x02 \x01(\tR\x05stage\x127\n" +
+	"\x06params\x18\x03 \x03(\v2\x1f.what2eat.v1.Reason.ParamsEntryR\x06params\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc8\x01\n" +
+	"\n" +
+	"TravelTime\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12)\n" +
+	"\x10duration_seconds\x18\x02 \x01(\x05R\x0fdurationSeconds\x12'\n" +
+	"\x0fdistance_meters\x18\x03 \x01(\x05R\x0edistanceMeters\x12\x1a\n" +
+	"\bprovider\x18\x04 \x01(\tR\bprovider\x12\x1c\n" +
+	"\testimated\x18\x05 \x01(\bR\testimated\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\"V\n" +
+	"\tDiversity\x12\x18\n" +
+	"\arelaxed\x18\x01 \x01(\bR\arelaxed\x12/\n" +
+	"\x13relaxed_constraints\x18\x02 \x03(\tR\x12relaxedConstraints\"h\n" +
+	"\x04Area\x12\x1a\n" +
+	"\bdistrict\x18\x01 \x01(\tR\bdistrict\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\"\x16\n" +
+	"\x14GetCategoriesRequest\"7\n" +
+	"\x15GetCategoriesResponse\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\tR\n" +
+	"categories\"\x11\n" +
+	"\x0fGetUsageRequest\"c\n" +
+	"\x10GetUsageResponse\x12(\n" +
+	"\x05usage\x18\x01 \x01(\v2\x12.what2eat.v1.UsageR\x05usage\x12%\n" +
+	"\x0elimit_exceeded\x18\x02 \x01(\bR\rlimitExceeded\"y\n" +
+	"\x05Usage\x12\x12\n" +
+	"\x04used\x18\x01 \x01(\x05R\x04used\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1c\n" +
+	"\tremaining\x18\x03 \x01(\x05R\tremaining\x12(\n" +
+	"\x10reset_in_seconds\x18\x04 \x01(\x03R\x0eresetInSeconds2\xbd\x02\n" +
+	"\bWhat2Eat\x12J\n" +
+	"\tRecommend\x12\x1d.what2eat.v1.RecommendRequest\x1a\x1e.what2eat.v1.RecommendResponse\x12D\n" +
+	"\x06Reroll\x12\x1a.what2eat.v1.RerollRequest\x1a\x1e.what2eat.v1.RecommendResponse\x12V\n" +
+	"\rGetCategories\x12!.what2eat.v1.GetCategoriesRequest\x1a\".what2eat.v1.GetCategoriesResponse\x12G\n" +
+	"\bGetUsage\x12\x1c.what2eat.v1.GetUsageRequest\x1a\x1d.what2eat.v1.GetUsageResponseB9Z7what2eat-backend/internal/grpcapi/what2eatv1;what2eatv1b\x06proto3"
+
+var (
+	file_what2eat_v1_what2eat_proto_rawDescOnce sync.Once
+	file_what2eat_v1_what2eat_proto_rawDescData []byte
+)
+
+func file_what2eat_v1_what2eat_proto_rawDescGZIP() []byte {
+	file_what2eat_v1_what2eat_proto_rawDescOnce.Do(func() {
+		file_what2eat_v1_what2eat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_what2eat_v1_what2eat_proto_rawDesc), len(file_what2eat_v1_what2eat_proto_rawDesc)))
+	})
+	return file_what2eat_v1_what2eat_proto_rawDescData
+}
+
+var file_what2eat_v1_what2eat_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_what2eat_v1_what2eat_proto_goTypes = []any{
+	(*RecommendRequest)(nil),      // 0: what2eat.v1.RecommendRequest
+	(*RerollRequest)(nil),         // 1: what2eat.v1.RerollRequest
+	(*RecommendResponse)(nil),     // 2: what2eat.v1.RecommendResponse
+	(*Restaurant)(nil),            // 3: what2eat.v1.Restaurant
+	(*Reason)(nil),                // 4: what2eat.v1.Reason
+	(*TravelTime)(nil),            // 5: what2eat.v1.TravelTime
+	(*Diversity)(nil),             // 6: what2eat.v1.Diversity
+	(*Area)(nil),                  // 7: what2eat.v1.Area
+	(*GetCategoriesRequest)(nil),  // 8: what2eat.v1.GetCategoriesRequest
+	(*GetCategoriesResponse)(nil), // 9: what2eat.v1.GetCategoriesResponse
+	(*GetUsageRequest)(nil),       // 10: what2eat.v1.GetUsageRequest
+	(*GetUsageResponse)(nil),      // 11: what2eat.v1.GetUsageResponse
+	(*Usage)(nil),                 // 12: what2eat.v1.Usage
+	nil,                           // 13: what2eat.v1.Reason.ParamsEntry
+}
+var file_what2eat_v1_what2eat_proto_depIdxs = []int32{
+	0,  // 0: what2eat.v1.RerollRequest.query:type_name -> what2eat.v1.RecommendRequest
+	3,  // 1: what2eat.v1.RecommendResponse.restaurants:type_name -> what2eat.v1.Restaurant
+	6,  // 2: what2eat.v1.RecommendResponse.diversity:type_name -> what2eat.v1.Diversity
+	7,  // 3: what2eat.v1.RecommendResponse.area:type_name -> what2eat.v1.Area
+	12, // 4: what2eat.v1.RecommendResponse.usage:type_name -> what2eat.v1.Usage
+	4,  // 5: what2eat.v1.Restaurant.reasons:type_name -> what2eat.v1.Reason
+	5,  // 6: what2eat.v1.Restaurant.travel:type_name -> what2eat.v1.TravelTime
+	13, // 7: what2eat.v1.Reason.params:type_name -> what2eat.v1.Reason.ParamsEntry
+	12, // 8: what2eat.v1.GetUsageResponse.usage:type_name -> what2eat.v1.Usage
+	0,  // 9: what2eat.v1.What2Eat.Recommend:input_type -> what2eat.v1.RecommendRequest
+	1,  // 10: what2eat.v1.What2Eat.Reroll:input_type -> what2eat.v1.RerollRequest
+	8,  // 11: what2eat.v1.What2Eat.GetCategories:input_type -> what2eat.v1.GetCategoriesRequest
+	10, // 12: what2eat.v1.What2Eat.GetUsage:input_type -> what2eat.v1.GetUsageRequest
+	2,  // 13: what2eat.v1.What2Eat.Recommend:output_type -> what2eat.v1.RecommendResponse
+	2,  // 14: what2eat.v1.What2Eat.Reroll:output_type -> what2eat.v1.RecommendResponse
+	9,  // 15: what2eat.v1.What2Eat.GetCategories:output_type -> what2eat.v1.GetCategoriesResponse
+	11, // 16: what2eat.v1.What2Eat.GetUsage:output_type -> what2eat.v1.GetUsageResponse
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_what2eat_v1_what2eat_proto_init() }
+func file_what2eat_v1_what2eat_proto_init() {
+	if File_what2eat_v1_what2eat_proto != nil {
+		return
+	}
+	file_what2eat_v1_what2eat_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_what2eat_v1_what2eat_proto_rawDesc), len(file_what2eat_v1_what2eat_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_what2eat_v1_what2eat_proto_goTypes,
+		DependencyIndexes: file_what2eat_v1_what2eat_proto_depIdxs,
+		MessageInfos:      file_what2eat_v1_what2eat_proto_msgTypes,
+	}.Build()
+	File_what2eat_v1_what2eat_proto = out.File
+	file_what2eat_v1_what2eat_proto_goTypes = nil
+	file_what2eat_v1_what2eat_proto_depIdxs = nil
+}
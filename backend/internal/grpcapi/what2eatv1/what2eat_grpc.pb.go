@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: what2eat/v1/what2eat.proto
+
+package what2eatv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	What2Eat_Recommend_FullMethodName     = "/what2eat.v1.What2Eat/Recommend"
+	What2Eat_Reroll_FullMethodName        = "/what2eat.v1.What2Eat/Reroll"
+	What2Eat_GetCategories_FullMethodName = "/what2eat.v1.What2Eat/GetCategories"
+	What2Eat_GetUsage_FullMethodName      = "/what2eat.v1.What2Eat/GetUsage"
+)
+
+// What2EatClient is the client API for What2Eat service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// What2Eat 餐廳推薦服務，與 REST API 共用相同的推薦流程、每日額度與流量限制
+type What2EatClient interface {
+	// Recommend 推薦附近的餐廳，會計入每日額度
+	Recommend(ctx context.Context, in *RecommendRequest, opts ...grpc.CallOption) (*RecommendResponse, error)
+	// Reroll 以相同條件重新推薦，排除上一批已推薦過的餐廳
+	Reroll(ctx context.Context, in *RerollRequest, opts ...grpc.CallOption) (*RecommendResponse, error)
+	// GetCategories 返回支援的餐廳類型
+	GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error)
+	// GetUsage 返回每日額度使用量，不呼叫 Google API
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+}
+
+type what2EatClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWhat2EatClient(cc grpc.ClientConnInterface) What2EatClient {
+	return &what2EatClient{cc}
+}
+
+func (c *what2EatClient) Recommend(ctx context.Context, in *RecommendRequest, opts ...grpc.CallOption) (*RecommendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecommendResponse)
+	err := c.cc.Invoke(ctx, What2Eat_Recommend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *what2EatClient) Reroll(ctx context.Context, in *RerollRequest, opts ...grpc.CallOption) (*RecommendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecommendResponse)
+	err := c.cc.Invoke(ctx, What2Eat_Reroll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *what2EatClient) GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCategoriesResponse)
+	err := c.cc.Invoke(ctx, What2Eat_GetCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *what2EatClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, What2Eat_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// What2EatServer is the server API for What2Eat service.
+// All implementations must embed UnimplementedWhat2EatServer
+// for forward compatibility.
+//
+// What2Eat 餐廳推薦服務，與 REST API 共用相同的推薦流程、每日額度與流量限制
+type What2EatServer interface {
+	// Recommend 推薦附近的餐廳，會計入每日額度
+	Recommend(context.Context, *RecommendRequest) (*RecommendResponse, error)
+	// Reroll 以相同條件重新推薦，排除上一批已推薦過的餐廳
+	Reroll(context.Context, *RerollRequest) (*RecommendResponse, error)
+	// GetCategories 返回支援的餐廳類型
+	GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error)
+	// GetUsage 返回每日額度使用量，不呼叫 Google API
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	mustEmbedUnimplementedWhat2EatServer()
+}
+
+// UnimplementedWhat2EatServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWhat2EatServer struct{}
+
+func (UnimplementedWhat2EatServer) Recommend(context.Context, *RecommendRequest) (*RecommendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Recommend not implemented")
+}
+func (UnimplementedWhat2EatServer) Reroll(context.Context, *RerollRequest) (*RecommendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Reroll not implemented")
+}
+func (UnimplementedWhat2EatServer) GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCategories not implemented")
+}
+func (UnimplementedWhat2EatServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedWhat2EatServer) mustEmbedUnimplementedWhat2EatServer() {}
+func (UnimplementedWhat2EatServer) testEmbeddedByValue()                  {}
+
+// UnsafeWhat2EatServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to What2EatServer will
+// result in compilation errors.
+type UnsafeWhat2EatServer interface {
+	mustEmbedUnimplementedWhat2EatServer()
+}
+
+func RegisterWhat2EatServer(s grpc.ServiceRegistrar, srv What2EatServer) {
+	// If the following call panics, it indicates UnimplementedWhat2EatServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&What2Eat_ServiceDesc, srv)
+}
+
+func _What2Eat_Recommend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecommendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(What2EatServer).Recommend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: What2Eat_Recommend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(What2EatServer).Recommend(ctx, req.(*RecommendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _What2Eat_Reroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RerollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(What2EatServer).Reroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: What2Eat_Reroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(What2EatServer).Reroll(ctx, req.(*RerollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _What2Eat_GetCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(What2EatServer).GetCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: What2Eat_GetCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(What2EatServer).GetCategories(ctx, req.(*GetCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _What2Eat_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(What2EatServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: What2Eat_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(What2EatServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// What2Eat_ServiceDesc is the grpc.ServiceDesc for What2Eat service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var What2Eat_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "what2eat.v1.What2Eat",
+	HandlerType: (*What2EatServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Recommend",
+			Handler:    _What2Eat_Recommend_Handler,
+		},
+		{
+			MethodName: "Reroll",
+			Handler:    _What2Eat_Reroll_Handler,
+		},
+		{
+			MethodName: "GetCategories",
+			Handler:    _What2Eat_GetCategories_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _What2Eat_GetUsage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "what2eat/v1/what2eat.proto",
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"what2eat-backend/internal/i18n"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 請求參數錯誤，Field 為出錯的參數名稱；Kind 不為 nil 時同時屬於該錯誤類別（如無效的位置）
+type paramError struct {
+	Field   string
+	Message string
+	Details string
+	Kind    error
+}
+
+func (e *paramError) Error() string {
+	return e.Message
+}
+
+func (e *paramError) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+// 以 near 參數解析地點失敗，/api 的回應會附上原始的 near 參數
+type locationError struct {
+	err error
+}
+
+func (e *locationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *locationError) Unwrap() error {
+	return e.err
+}
+
+// apiErrorStatus 將錯誤對應到 HTTP 狀態碼與 API v2 錯誤代碼，是所有 handler 唯一的對應表
+// 需要專屬錯誤代碼的 sentinel 錯誤先比對，其餘依 model 中的錯誤類別判斷
+func apiErrorStatus(err error) (int, string) {
+	var paramErr *paramError
+	switch {
+	case errors.Is(err, service.ErrAreaTooLarge):
+		return http.StatusBadRequest, model.ErrorCodeAreaTooLarge
+	case errors.Is(err, service.ErrLocationNotFound):
+		return http.StatusNotFound, model.ErrorCodeLocationNotFound
+	case errors.Is(err, model.ErrInvalidLocation):
+		return http.StatusBadRequest, model.ErrorCodeInvalidLocation
+	case errors.As(err, &paramErr), errors.Is(err, model.ErrInvalidArgument):
+		return http.StatusBadRequest, model.ErrorCodeInvalidArgument
+	case errors.Is(err, model.ErrUnauthorized):
+		return http.StatusUnauthorized, model.ErrorCodeUnauthorized
+	case errors.Is(err, model.ErrForbidden):
+		return http.StatusForbidden, model.ErrorCodeForbidden
+	case errors.Is(err, model.ErrNotFound):
+		return http.StatusNotFound, model.ErrorCodeNotFound
+	case errors.Is(err, model.ErrConflict):
+		return http.StatusConflict, model.ErrorCodeConflict
+	case errors.Is(err, model.ErrGone):
+		return http.StatusGone, model.ErrorCodeGone
+	case errors.Is(err, model.ErrRateLimited):
+		return http.StatusTooManyRequests, model.ErrorCodeRateLimited
+	case errors.Is(err, model.ErrNotEnabled):
+		return http.StatusNotImplemented, model.ErrorCodeNotImplemented
+	case errors.Is(err, model.ErrQuotaExceeded):
+		return http.StatusTooManyRequests, model.ErrorCodeQuotaExceeded
+	case errors.Is(err, model.ErrUpstreamQuota):
+		return http.StatusTooManyRequests, model.ErrorCodeUpstreamQuota
+	case errors.Is(err, model.ErrTimeout):
+		return http.StatusGatewayTimeout, model.ErrorCodeTimeout
+	case errors.Is(err, model.ErrUpstreamUnavailable):
+		return http.StatusBadGateway, model.ErrorCodeUpstreamUnavailable
+	case errors.Is(err, model.ErrNoResults):
+		return http.StatusNotFound, model.ErrorCodeNoResults
+	default:
+		return http.StatusInternalServerError, model.ErrorCodeInternal
+	}
+}
+
+// errorStatus 錯誤對應的 HTTP 狀態碼，回應格式不是 API v2 的 handler 使用
+func errorStatus(err error) int {
+	status, _ := apiErrorStatus(err)
+	return status
+}
+
+// 出錯的參數名稱
+func apiErrorField(err error) string {
+	var paramErr *paramError
+	switch {
+	case errors.As(err, &paramErr):
+		return paramErr.Field
+	case errors.Is(err, service.ErrInvalidLocationQuery):
+		return "near"
+	case errors.Is(err, service.ErrInvalidCoordinates):
+		return "lat,lng"
+	case errors.Is(err, service.ErrNoParticipants):
+		return "participants"
+	case errors.Is(err, service.ErrInvalidArea), errors.Is(err, service.ErrAreaTooLarge):
+		return "sw,ne"
+	default:
+		return ""
+	}
+}
+
+// 以 API v2 的格式回應錯誤，訊息依請求語系產生
+func (h *RestaurantHandler) respondAPIError(c *gin.Context, err error) {
+	status, code := apiErrorStatus(err)
+	field := apiErrorField(err)
+	meta := h.apiMeta(c)
+
+	message := i18n.Translate(meta.Language, "error."+strings.ToLower(code), map[string]string{
+		"field":    field,
+		"reset_in": formatDuration(h.counterService.GetTimeUntilReset()),
+	})
+	c.JSON(status, model.APIResponse{
+		Meta:   meta,
+		Errors: []model.APIError{{Code: code, Message: message, Field: field}},
+	})
+}
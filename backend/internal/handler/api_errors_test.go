@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIErrorStatus(t *testing.T) {
+	overQueryLimit := model.NewUpstreamError("Google Places API", errors.New("maps: OVER_QUERY_LIMIT - You have exceeded your daily request quota"))
+	unavailable := model.NewUpstreamError("Google Places API", errors.New("maps: UNKNOWN_ERROR - "))
+	deadline := model.NewUpstreamError("Google Geocoding API", fmt.Errorf("Get \"https://maps.googleapis.com\": %w", context.DeadlineExceeded))
+	netTimeout := model.NewUpstreamError("Google Places API", &net.DNSError{Err: "i/o timeout", IsTimeout: true})
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		field  string
+	}{
+		{"使用者身分", service.ErrUserRequired, http.StatusUnauthorized, model.ErrorCodeUnauthorized, ""},
+		{"範圍太大", fmt.Errorf("%w（需要 304 個格子，上限 64）", service.ErrAreaTooLarge), http.StatusBadRequest, model.ErrorCodeAreaTooLarge, "sw,ne"},
+		{"無效的範圍", service.ErrInvalidArea, http.StatusBadRequest, model.ErrorCodeInvalidLocation, "sw,ne"},
+		{"找不到地點", &locationError{err: service.ErrLocationNotFound}, http.StatusNotFound, model.ErrorCodeLocationNotFound, ""},
+		{"無效的地點", &locationError{err: service.ErrInvalidLocationQuery}, http.StatusBadRequest, model.ErrorCodeInvalidLocation, "near"},
+		{"經緯度超出範圍", service.ErrInvalidCoordinates, http.StatusBadRequest, model.ErrorCodeInvalidLocation, "lat,lng"},
+		{"沒有參與者", service.ErrNoParticipants, http.StatusBadRequest, model.ErrorCodeInvalidLocation, "participants"},
+		{"緯度格式", &paramError{Field: "lat", Message: "無效的緯度參數", Kind: model.ErrInvalidLocation}, http.StatusBadRequest, model.ErrorCodeInvalidLocation, "lat"},
+		{"一般參數", &paramError{Field: "budget", Message: "無效的預算參數"}, http.StatusBadRequest, model.ErrorCodeInvalidArgument, "budget"},
+		{"每日額度", service.ErrDailyLimitExceeded, http.StatusTooManyRequests, model.ErrorCodeQuotaExceeded, ""},
+		{"每日額度（包裝）", fmt.Errorf("API 每日請求數已達上限 600/600: %w", service.ErrDailyLimitExceeded), http.StatusTooManyRequests, model.ErrorCodeQuotaExceeded, ""},
+		{"地點解析額度", &locationError{err: service.ErrGeocodeLimitExceeded}, http.StatusTooManyRequests, model.ErrorCodeQuotaExceeded, ""},
+		{"Google 額度", fmt.Errorf("搜尋餐廳失敗: %w", overQueryLimit), http.StatusTooManyRequests, model.ErrorCodeUpstreamQuota, ""},
+		{"外部服務錯誤", fmt.Errorf("搜尋餐廳失敗: %w", unavailable), http.StatusBadGateway, model.ErrorCodeUpstreamUnavailable, ""},
+		{"地點解析服務錯誤", &locationError{err: unavailable}, http.StatusBadGateway, model.ErrorCodeUpstreamUnavailable, ""},
+		{"逾時", deadline, http.StatusGatewayTimeout, model.ErrorCodeTimeout, ""},
+		{"網路逾時", netTimeout, http.StatusGatewayTimeout, model.ErrorCodeTimeout, ""},
+		{"沒有結果", model.ErrNoResults, http.StatusNotFound, model.ErrorCodeNoResults, ""},
+		{"其他錯誤", errors.New("無法讀取資料"), http.StatusInternalServerError, model.ErrorCodeInternal, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := apiErrorStatus(tt.err)
+			if status != tt.status || code != tt.code {
+				t.Errorf("apiErrorStatus(%v) = %d %s, want %d %s", tt.err, status, code, tt.status, tt.code)
+			}
+			if got := errorStatus(tt.err); got != tt.status {
+				t.Errorf("errorStatus(%v) = %d, want %d", tt.err, got, tt.status)
+			}
+			if field := apiErrorField(tt.err); field != tt.field {
+				t.Errorf("apiErrorField(%v) = %q, want %q", tt.err, field, tt.field)
+			}
+		})
+	}
+}
+
+// 各 service 的 sentinel 錯誤只以所屬類別決定狀態碼，所有 handler 共用 apiErrorStatus
+func TestDomainErrorStatus(t *testing.T) {
+	quota := fmt.Errorf("無法建立投票房間: %w", model.NewUpstreamError("Google Places API", errors.New("maps: OVER_QUERY_LIMIT - ")))
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+		code string
+	}{
+		{"房間不存在", service.ErrRoomNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"候選餐廳不存在", service.ErrCandidateNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"沒有候選餐廳", service.ErrNoCandidates, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"房間已結束", service.ErrRoomClosed, http.StatusConflict, model.ErrorCodeConflict},
+		{"餐廳已被否決", service.ErrCandidateVetoed, http.StatusConflict, model.ErrorCodeConflict},
+		{"否決次數上限", service.ErrVetoLimit, http.StatusConflict, model.ErrorCodeConflict},
+		{"不是參與者", service.ErrParticipantNotFound, http.StatusForbidden, model.ErrorCodeForbidden},
+		{"不是房間建立者", service.ErrNotRoomCreator, http.StatusForbidden, model.ErrorCodeForbidden},
+		{"顯示名稱為空", service.ErrInvalidName, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"房間 Google 額度", quota, http.StatusTooManyRequests, model.ErrorCodeUpstreamQuota},
+		{"房間每日額度", service.ErrDailyLimitExceeded, http.StatusTooManyRequests, model.ErrorCodeQuotaExceeded},
+		{"無效的權杖", service.ErrInvalidToken, http.StatusUnauthorized, model.ErrorCodeUnauthorized},
+		{"權杖過期", service.ErrTokenExpired, http.StatusUnauthorized, model.ErrorCodeUnauthorized},
+		{"無效的 Email", service.ErrInvalidEmail, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"綁定請求不存在", service.ErrLinkRequestNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"綁定請求屬於其他裝置", service.ErrLinkRequestForbidden, http.StatusForbidden, model.ErrorCodeForbidden},
+		{"綁定請求已使用", service.ErrLinkRequestUsed, http.StatusConflict, model.ErrorCodeConflict},
+		{"綁定請求尚未驗證", service.ErrLinkRequestPending, http.StatusConflict, model.ErrorCodeConflict},
+		{"裝置已綁定", service.ErrAlreadyLinked, http.StatusConflict, model.ErrorCodeConflict},
+		{"未設定 OAuth", service.ErrOAuthNotConfigured, http.StatusNotImplemented, model.ErrorCodeNotImplemented},
+		{"未啟用 Email 登入", service.ErrMagicLinkNotEnabled, http.StatusNotImplemented, model.ErrorCodeNotImplemented},
+		{"OAuth 提供者錯誤", model.NewUpstreamError("OAuth 身分提供者", errors.New("OAuth token 交換失敗")), http.StatusBadGateway, model.ErrorCodeUpstreamUnavailable},
+		{"分享不存在", service.ErrShareNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"分享已過期", service.ErrShareExpired, http.StatusGone, model.ErrorCodeGone},
+		{"無效的分享", service.ErrInvalidShare, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"分享其他錯誤", errors.New("無法讀取資料"), http.StatusInternalServerError, model.ErrorCodeInternal},
+		{"收藏不存在", service.ErrFavoriteNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"收藏已存在", service.ErrFavoriteExists, http.StatusConflict, model.ErrorCodeConflict},
+		{"收藏資料不完整", service.ErrInvalidFavorite, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"標籤過多", service.ErrTooManyTags, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"排程不存在", service.ErrScheduleNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"無效的排程（包裝）", fmt.Errorf("%w: 無效的時區", service.ErrInvalidSchedule), http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"排程數量上限", service.ErrScheduleLimit, http.StatusConflict, model.ErrorCodeConflict},
+		{"排程執行過於頻繁", service.ErrScheduleTooSoon, http.StatusTooManyRequests, model.ErrorCodeRateLimited},
+		{"排程 Google 錯誤", model.NewUpstreamError("Google Places API", errors.New("maps: UNKNOWN_ERROR - ")), http.StatusBadGateway, model.ErrorCodeUpstreamUnavailable},
+		{"用餐紀錄不存在", service.ErrMealNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"未知的餐廳", service.ErrUnknownPlace, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"用餐時間在未來", service.ErrInvalidEatenAt, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+		{"回饋不存在", service.ErrFeedbackNotFound, http.StatusNotFound, model.ErrorCodeNotFound},
+		{"無效的回饋", service.ErrInvalidFeedback, http.StatusBadRequest, model.ErrorCodeInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := apiErrorStatus(tt.err)
+			if status != tt.want || code != tt.code {
+				t.Errorf("apiErrorStatus(%v) = %d %s, want %d %s", tt.err, status, code, tt.want, tt.code)
+			}
+		})
+	}
+
+	// 同一類別的 sentinel 錯誤彼此仍可區分
+	if errors.Is(service.ErrLinkRequestPending, service.ErrLinkRequestUsed) || errors.Is(service.ErrRoomNotFound, service.ErrCandidateNotFound) {
+		t.Error("sentinels of the same kind must not match each other")
+	}
+}
+
+// API v2 的錯誤回應：每種錯誤類別都有對應的狀態碼、錯誤代碼、參數名稱與依語系產生的訊息
+func TestRespondAPIError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// 計數器會寫入 data 目錄
+	t.Chdir(t.TempDir())
+	h := NewRestaurantHandler(nil, service.NewCounterService(100), nil)
+
+	tests := []struct {
+		err    error
+		status int
+		code   string
+		field  string
+		wantEn string // 英文訊息的開頭，額度相關訊息結尾為重置時間
+	}{
+		{&paramError{Field: "budget", Message: "無效的預算參數"}, http.StatusBadRequest, model.ErrorCodeInvalidArgument, "budget", "invalid value for budget"},
+		{service.ErrInvalidCoordinates, http.StatusBadRequest, model.ErrorCodeInvalidLocation, "lat,lng", "invalid location lat,lng"},
+		{service.ErrUserRequired, http.StatusUnauthorized, model.ErrorCodeUnauthorized, "", "a user identity is required"},
+		{&locationError{err: service.ErrLocationNotFound}, http.StatusNotFound, model.ErrorCodeLocationNotFound, "", "no matching location found"},
+		{service.ErrAreaTooLarge, http.StatusBadRequest, model.ErrorCodeAreaTooLarge, "sw,ne", "the area is too large"},
+		{model.ErrNoResults, http.StatusNotFound, model.ErrorCodeNoResults, "", "no matching results"},
+		{service.ErrDailyLimitExceeded, http.StatusTooManyRequests, model.ErrorCodeQuotaExceeded, "", "today's free quota is used up; try again in "},
+		{model.NewUpstreamError("Google Places API", errors.New("maps: OVER_QUERY_LIMIT - ")), http.StatusTooManyRequests, model.ErrorCodeUpstreamQuota, "", "the Google Maps API quota is used up"},
+		{model.NewUpstreamError("Google Places API", errors.New("maps: UNKNOWN_ERROR - ")), http.StatusBadGateway, model.ErrorCodeUpstreamUnavailable, "", "the map service is temporarily unavailable"},
+		{model.NewUpstreamError("Google Places API", context.DeadlineExceeded), http.StatusGatewayTimeout, model.ErrorCodeTimeout, "", "the map service timed out"},
+		{errors.New("無法讀取資料"), http.StatusInternalServerError, model.ErrorCodeInternal, "", "could not load restaurants"},
+		{service.ErrNotRoomCreator, http.StatusForbidden, model.ErrorCodeForbidden, "", "you are not allowed to do this"},
+		{service.ErrRoomNotFound, http.StatusNotFound, model.ErrorCodeNotFound, "", "not found"},
+		{service.ErrRoomClosed, http.StatusConflict, model.ErrorCodeConflict, "", "conflicts with the current state"},
+		{service.ErrShareExpired, http.StatusGone, model.ErrorCodeGone, "", "this has expired"},
+		{service.ErrScheduleTooSoon, http.StatusTooManyRequests, model.ErrorCodeRateLimited, "", "too many requests"},
+		{service.ErrOAuthNotConfigured, http.StatusNotImplemented, model.ErrorCodeNotImplemented, "", "this feature is not enabled on the server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			for _, lang := range []string{"en", "zh-TW"} {
+				recorder := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(recorder)
+				c.Request = httptest.NewRequest("GET", "/api/v2/restaurants?lang="+lang, nil)
+				h.respondAPIError(c, tt.err)
+
+				if recorder.Code != tt.status {
+					t.Errorf("%s: status = %d, want %d", lang, recorder.Code, tt.status)
+				}
+				var response struct {
+					Data   any              `json:"data"`
+					Meta   model.APIMeta    `json:"meta"`
+					Errors []model.APIError `json:"errors"`
+				}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+					t.Fatalf("%s: cannot decode response: %v", lang, err)
+				}
+				if response.Data != nil || response.Meta.Language != lang || response.Meta.Usage.Limit != 100 {
+					t.Errorf("%s: data = %v, meta = %+v", lang, response.Data, response.Meta)
+				}
+				if len(response.Errors) != 1 {
+					t.Fatalf("%s: errors = %+v, want 1", lang, response.Errors)
+				}
+
+				got := response.Errors[0]
+				if got.Code != tt.code || got.Field != tt.field {
+					t.Errorf("%s: error = %+v, want code %s field %q", lang, got, tt.code, tt.field)
+				}
+				// 訊息已翻譯且參數都已代入
+				if got.Message == "" || strings.HasPrefix(got.Message, "error.") || strings.Contains(got.Message, "{") {
+					t.Errorf("%s: message = %q", lang, got.Message)
+				}
+				if lang == "en" && !strings.HasPrefix(got.Message, tt.wantEn) {
+					t.Errorf("message = %q, want prefix %q", got.Message, tt.wantEn)
+				}
+			}
+		})
+	}
+}
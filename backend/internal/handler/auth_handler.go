@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authService *service.AuthService
+}
+
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+type magicLinkRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type completeLinkRequest struct {
+	RequestID string `json:"request_id" binding:"required"`
+}
+
+// IssueDeviceToken 發放匿名裝置權杖
+func (h *AuthHandler) IssueDeviceToken(c *gin.Context) {
+	token, userID, err := h.authService.IssueDeviceToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "無法建立使用者身分", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"token": token, "user_id": userID})
+}
+
+// Me 取得目前使用者身分與帳號綁定狀態
+func (h *AuthHandler) Me(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	account, err := h.authService.GetAccount(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "無法取得帳號資訊", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":   userID,
+		"anonymous": account == nil,
+		"account":   account,
+	})
+}
+
+// RequestMagicLink 寄送 Email 登入連結
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req magicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供 Email"})
+		return
+	}
+
+	requestID, err := h.authService.RequestMagicLink(middleware.GetUserID(c), req.Email)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "登入連結已寄出，請至信箱確認後在此裝置完成綁定", "request_id": requestID})
+}
+
+// VerifyMagicLink 驗證 Email 登入連結，綁定需回到發起請求的裝置完成
+func (h *AuthHandler) VerifyMagicLink(c *gin.Context) {
+	requestID, err := h.authService.VerifyMagicLink(c.Query("token"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email 驗證成功，請回到原裝置完成綁定", "request_id": requestID})
+}
+
+// StartOAuth 返回 OAuth 授權網址與綁定請求 ID
+func (h *AuthHandler) StartOAuth(c *gin.Context) {
+	authURL, requestID, err := h.authService.OAuthURL(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"auth_url": authURL, "request_id": requestID})
+}
+
+// OAuthCallback 處理 OAuth 提供者的回呼，綁定需回到發起請求的裝置完成
+func (h *AuthHandler) OAuthCallback(c *gin.Context) {
+	if errParam := c.Query("error"); errParam != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "OAuth 授權失敗", "details": errParam})
+		return
+	}
+
+	requestID, err := h.authService.CompleteOAuth(c, c.Query("state"), c.Query("code"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "授權成功，請回到原裝置完成綁定", "request_id": requestID})
+}
+
+// CompleteLink 由發起綁定的裝置確認已驗證的請求，綁定帳號並返回帳號權杖
+func (h *AuthHandler) CompleteLink(c *gin.Context) {
+	var req completeLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供 request_id"})
+		return
+	}
+
+	token, account, err := h.authService.CompleteLink(middleware.GetUserID(c), req.RequestID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": token, "user_id": account.ID, "account": account})
+}
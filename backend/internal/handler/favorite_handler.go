@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 備註長度上限（字元）
+const maxFavoriteNoteLength = 500
+
+type FavoriteHandler struct {
+	favoriteService *service.FavoriteService
+}
+
+func NewFavoriteHandler(favoriteService *service.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{favoriteService: favoriteService}
+}
+
+type favoriteLocationRequest struct {
+	// 使用指標讓赤道或本初子午線上的 0 也能通過必填檢查
+	Lat *float64 `json:"lat" binding:"required"`
+	Lng *float64 `json:"lng" binding:"required"`
+}
+
+type addFavoriteRequest struct {
+	PlaceID    string                   `json:"place_id"`
+	Restaurant model.Restaurant         `json:"restaurant"`
+	Location   *favoriteLocationRequest `json:"location"`
+	Category   string                   `json:"category"`
+	Note       string                   `json:"note"`
+	Tags       []string                 `json:"tags"`
+}
+
+type updateFavoriteRequest struct {
+	Category *string   `json:"category"`
+	Note     *string   `json:"note"`
+	Tags     *[]string `json:"tags"`
+}
+
+// ListFavorites 列出收藏，可依類型或標籤篩選，提供 lat/lng 時依距離排序
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	filter := model.FavoriteFilter{
+		Category: c.Query("type"),
+		Tag:      c.Query("tag"),
+	}
+
+	if c.Query("lat") != "" || c.Query("lng") != "" {
+		lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+		if latErr != nil || lngErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的經緯度參數"})
+			return
+		}
+		filter.Origin = &model.Location{Lat: lat, Lng: lng}
+	}
+
+	favorites, err := h.favoriteService.ListFavorites(middleware.GetUserID(c), filter)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "無法取得收藏清單", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"favorites": favorites, "count": len(favorites)})
+}
+
+// AddFavorite 新增收藏
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	var req addFavoriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+	if len([]rune(req.Note)) > maxFavoriteNoteLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "備註內容過長"})
+		return
+	}
+
+	var location *model.Location
+	if req.Location != nil {
+		location = &model.Location{Lat: *req.Location.Lat, Lng: *req.Location.Lng}
+	}
+
+	favorite, err := h.favoriteService.AddFavorite(model.Favorite{
+		UserID:     middleware.GetUserID(c),
+		PlaceID:    req.PlaceID,
+		Restaurant: req.Restaurant,
+		Location:   location,
+		Category:   req.Category,
+		Note:       req.Note,
+		Tags:       req.Tags,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"favorite": favorite})
+}
+
+// GetFavorite 取得單一收藏
+func (h *FavoriteHandler) GetFavorite(c *gin.Context) {
+	favorite, err := h.favoriteService.GetFavorite(middleware.GetUserID(c), c.Param("place_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"favorite": favorite})
+}
+
+// UpdateFavorite 更新收藏的分類、備註或標籤
+func (h *FavoriteHandler) UpdateFavorite(c *gin.Context) {
+	var req updateFavoriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+	if req.Note != nil && len([]rune(*req.Note)) > maxFavoriteNoteLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "備註內容過長"})
+		return
+	}
+
+	favorite, err := h.favoriteService.UpdateFavorite(middleware.GetUserID(c), c.Param("place_id"), service.FavoriteUpdate{
+		Category: req.Category,
+		Note:     req.Note,
+		Tags:     req.Tags,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"favorite": favorite})
+}
+
+// DeleteFavorite 移除收藏
+func (h *FavoriteHandler) DeleteFavorite(c *gin.Context) {
+	if err := h.favoriteService.DeleteFavorite(middleware.GetUserID(c), c.Param("place_id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已移除收藏"})
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedbackHandler struct {
+	feedbackService *service.FeedbackService
+}
+
+func NewFeedbackHandler(feedbackService *service.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{feedbackService: feedbackService}
+}
+
+type feedbackRequest struct {
+	PlaceID    string            `json:"place_id"`
+	Kind       string            `json:"kind"`       // like / dislike / never_again
+	Restaurant *model.Restaurant `json:"restaurant"` // 可選，未提供時從收藏或搜尋緩存查找
+}
+
+// SubmitFeedback 對餐廳按讚、倒讚或標記不再推薦
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	var req feedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+	if req.PlaceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供 place_id"})
+		return
+	}
+
+	feedback, profile, err := h.feedbackService.SubmitFeedback(middleware.GetUserID(c), req.PlaceID, req.Kind, req.Restaurant)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"feedback": feedback, "profile": profile})
+}
+
+// ListFeedback 列出使用者的所有回饋
+func (h *FeedbackHandler) ListFeedback(c *gin.Context) {
+	feedback, err := h.feedbackService.ListFeedback(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "無法取得回饋紀錄", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"feedback": feedback, "count": len(feedback)})
+}
+
+// DeleteFeedback 撤回對餐廳的回饋
+func (h *FeedbackHandler) DeleteFeedback(c *gin.Context) {
+	profile, err := h.feedbackService.DeleteFeedback(middleware.GetUserID(c), c.Param("place_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已撤回回饋", "profile": profile})
+}
+
+// GetPreferences 查看由回饋累積出的偏好檔案
+func (h *FeedbackHandler) GetPreferences(c *gin.Context) {
+	profile, err := h.feedbackService.GetProfile(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "無法取得偏好檔案", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"profile": profile})
+}
+
+// ResetPreferences 重設偏好檔案，keep_blocked=true 時保留「不再推薦」的餐廳
+func (h *FeedbackHandler) ResetPreferences(c *gin.Context) {
+	keepBlocked := c.Query("keep_blocked") == "true"
+
+	profile, err := h.feedbackService.ResetProfile(middleware.GetUserID(c), keepBlocked)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "無法重設偏好檔案", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已重設偏好檔案", "profile": profile})
+}
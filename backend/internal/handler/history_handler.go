@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 每週摘要的預設與最大週數
+const (
+	defaultHistoryWeeks = 4
+	maxHistoryWeeks     = 52
+)
+
+type HistoryHandler struct {
+	historyService *service.HistoryService
+}
+
+func NewHistoryHandler(historyService *service.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService}
+}
+
+type recordMealRequest struct {
+	PlaceID    string            `json:"place_id"`
+	Restaurant *model.Restaurant `json:"restaurant"` // 可選，未提供時從收藏或搜尋緩存查找
+	EatenAt    *time.Time        `json:"eaten_at"`   // 可選，預設為現在
+}
+
+// RecordMeal 記錄「我們去了這家」
+func (h *HistoryHandler) RecordMeal(c *gin.Context) {
+	var req recordMealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+	if req.PlaceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供 place_id"})
+		return
+	}
+
+	var eatenAt time.Time
+	if req.EatenAt != nil {
+		eatenAt = *req.EatenAt
+	}
+
+	record, err := h.historyService.RecordMeal(middleware.GetUserID(c), req.PlaceID, req.Restaurant, eatenAt)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"meal": record})
+}
+
+// ListHistory 返回最近幾週的用餐紀錄與每週摘要
+func (h *HistoryHandler) ListHistory(c *gin.Context) {
+	weeks := defaultHistoryWeeks
+	if weeksParam := c.Query("weeks"); weeksParam != "" {
+		var err error
+		weeks, err = strconv.Atoi(weeksParam)
+		if err != nil || weeks < 1 || weeks > maxHistoryWeeks {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "無效的週數參數，需為 1-52"})
+			return
+		}
+	}
+
+	meals, summaries, err := h.historyService.RecentHistory(middleware.GetUserID(c), weeks)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "無法取得用餐紀錄", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"meals": meals,
+		"count": len(meals),
+		"weeks": summaries,
+	})
+}
+
+// DeleteMeal 刪除一筆用餐紀錄
+func (h *HistoryHandler) DeleteMeal(c *gin.Context) {
+	if err := h.historyService.DeleteMeal(middleware.GetUserID(c), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已刪除用餐紀錄"})
+}
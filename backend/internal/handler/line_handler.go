@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"what2eat-backend/internal/infrastructure"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LineHandler struct {
+	lineBotService *service.LineBotService
+	channelSecret  string
+}
+
+func NewLineHandler(lineBotService *service.LineBotService, channelSecret string) *LineHandler {
+	return &LineHandler{
+		lineBotService: lineBotService,
+		channelSecret:  channelSecret,
+	}
+}
+
+// Webhook 接收 LINE Messaging API 的 webhook 事件
+func (h *LineHandler) Webhook(c *gin.Context) {
+	if h.channelSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LINE Bot 未啟用"})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無法讀取請求內容"})
+		return
+	}
+
+	if !infrastructure.ValidateLineSignature(h.channelSecret, body, c.GetHeader("X-Line-Signature")) {
+		fmt.Printf("LINE webhook 簽章驗證失敗，來源 IP: %s\n", c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "無效的簽章"})
+		return
+	}
+
+	var webhook model.LineWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容"})
+		return
+	}
+
+	// 個別事件處理失敗只記錄錯誤，仍回應 200 避免 LINE 重送
+	for _, event := range webhook.Events {
+		if err := h.lineBotService.HandleEvent(c, event); err != nil {
+			fmt.Printf("處理 LINE 事件失敗 (%s): %v\n", event.Type, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
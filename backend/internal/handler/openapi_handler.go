@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+// NewOpenAPIHandler 預先將 API 文件轉為 JSON，之後的請求直接回應
+func NewOpenAPIHandler(doc *openapi3.T) (*OpenAPIHandler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("無法輸出 OpenAPI 文件: %w", err)
+	}
+	return &OpenAPIHandler{spec: spec}, nil
+}
+
+// Spec 返回 OpenAPI 3 文件
+func (h *OpenAPIHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 餐廳結果支援的輸出格式
+const (
+	formatJSON    = "json"
+	formatGeoJSON = "geojson"
+	formatCSV     = "csv"
+
+	mimeGeoJSON = "application/geo+json"
+	mimeCSV     = "text/csv"
+)
+
+// 解析輸出格式：format 參數優先，其次依 Accept 標頭協商，無法判斷時使用 JSON
+// 錯誤回應一律為 JSON，不受輸出格式影響
+func restaurantFormat(c *gin.Context) (string, error) {
+	switch format := strings.ToLower(c.Query("format")); format {
+	case formatJSON, formatGeoJSON, formatCSV:
+		return format, nil
+	case "":
+	default:
+		return "", &paramError{Field: "format", Message: "無效的輸出格式參數，僅支援 json、geojson 或 csv"}
+	}
+
+	switch c.NegotiateFormat(gin.MIMEJSON, mimeGeoJSON, mimeCSV) {
+	case mimeGeoJSON:
+		return formatGeoJSON, nil
+	case mimeCSV:
+		return formatCSV, nil
+	default:
+		return formatJSON, nil
+	}
+}
+
+// 依輸出格式回應餐廳結果
+// JSON 直接回應 response；GeoJSON 將餐廳轉為 FeatureCollection，其餘欄位保留在最上層；CSV 只輸出餐廳
+func respondRestaurants(c *gin.Context, format string, restaurants []model.Restaurant, response gin.H) {
+	// 回應內容依 Accept 標頭而不同，避免快取混用
+	c.Header("Vary", "Accept")
+
+	switch format {
+	case formatGeoJSON:
+		collection := gin.H{}
+		for key, value := range response {
+			if key != "restaurants" {
+				collection[key] = value
+			}
+		}
+		collection["type"] = "FeatureCollection"
+		collection["features"] = restaurantFeatures(restaurants)
+
+		c.Header("Content-Type", mimeGeoJSON+"; charset=utf-8")
+		c.JSON(http.StatusOK, collection)
+	case formatCSV:
+		data, err := restaurantsCSV(restaurants)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "無法產生 CSV", "details": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="restaurants.csv"`)
+		c.Data(http.StatusOK, mimeCSV+"; charset=utf-8", data)
+	default:
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+type geoJSONFeature struct {
+	Type       string           `json:"type"`
+	ID         string           `json:"id,omitempty"`
+	Geometry   *geoJSONPoint    `json:"geometry"` // 沒有座標的餐廳為 null
+	Properties model.Restaurant `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"` // GeoJSON 順序為 [經度, 緯度]
+}
+
+func restaurantFeatures(restaurants []model.Restaurant) []geoJSONFeature {
+	features := make([]geoJSONFeature, 0, len(restaurants))
+	for _, r := range restaurants {
+		feature := geoJSONFeature{Type: "Feature", ID: r.PlaceID, Properties: r}
+		if r.Lat != 0 || r.Lng != 0 {
+			feature.Geometry = &geoJSONPoint{Type: "Point", Coordinates: [2]float64{r.Lng, r.Lat}}
+		}
+		features = append(features, feature)
+	}
+	return features
+}
+
+// CSV 欄位順序固定，新增欄位只能加在最後，避免破壞既有的匯入設定
+var restaurantCSVColumns = []struct {
+	name  string
+	value func(r model.Restaurant) string
+}{
+	{"place_id", func(r model.Restaurant) string { return csvText(r.PlaceID) }},
+	{"name", func(r model.Restaurant) string { return csvText(r.Name) }},
+	{"lat", func(r model.Restaurant) string { return strconv.FormatFloat(r.Lat, 'f', -1, 64) }},
+	{"lng", func(r model.Restaurant) string { return strconv.FormatFloat(r.Lng, 'f', -1, 64) }},
+	{"address", func(r model.Restaurant) string { return csvText(r.Address) }},
+	{"rating", func(r model.Restaurant) string { return strconv.FormatFloat(float64(r.Rating), 'f', -1, 32) }},
+	{"user_ratings_total", func(r model.Restaurant) string { return strconv.Itoa(r.UserRatingsTotal) }},
+	{"price_level", func(r model.Restaurant) string { return strconv.Itoa(r.PriceLevel) }},
+	{"average_price", func(r model.Restaurant) string { return csvText(r.AveragePrice) }},
+	{"distance_meters", func(r model.Restaurant) string { return strconv.FormatFloat(r.DistanceMeters, 'f', 0, 64) }},
+	{"restaurant_type", func(r model.Restaurant) string { return csvText(r.RestaurantType) }},
+	{"score", func(r model.Restaurant) string {
+		if r.Score == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(r.Score, 'f', 3, 64)
+	}},
+	{"travel_mode", func(r model.Restaurant) string {
+		if r.Travel == nil {
+			return ""
+		}
+		return r.Travel.Mode
+	}},
+	{"travel_duration_seconds", func(r model.Restaurant) string {
+		if r.Travel == nil {
+			return ""
+		}
+		return strconv.Itoa(r.Travel.DurationSeconds)
+	}},
+	{"travel_distance_meters", func(r model.Restaurant) string {
+		if r.Travel == nil {
+			return ""
+		}
+		return strconv.Itoa(r.Travel.DistanceMeters)
+	}},
+	{"reasons", func(r model.Restaurant) string {
+		messages := make([]string, 0, len(r.Reasons))
+		for _, reason := range r.Reasons {
+			messages = append(messages, reason.Message)
+		}
+		return csvText(strings.Join(messages, "; "))
+	}},
+	{"google_maps_url", func(r model.Restaurant) string { return repository.GoogleMapsURL(r.Name, r.PlaceID) }},
+}
+
+func restaurantsCSV(restaurants []model.Restaurant) ([]byte, error) {
+	var buf bytes.Buffer
+	// UTF-8 BOM，讓 Excel 正確顯示中文
+	buf.WriteString("\ufeff")
+
+	writer := csv.NewWriter(&buf)
+	header := make([]string, len(restaurantCSVColumns))
+	for i, column := range restaurantCSVColumns {
+		header[i] = column.name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	row := make([]string, len(restaurantCSVColumns))
+	for _, r := range restaurants {
+		for i, column := range restaurantCSVColumns {
+			row[i] = column.value(r)
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// 文字欄位開頭為公式字元時加上單引號，避免試算表將店名或地址當成公式執行
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
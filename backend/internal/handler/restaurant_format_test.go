@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"what2eat-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRestaurantsCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		restaurant model.Restaurant
+		column     string
+		want       string
+	}{
+		{"逗號與引號", model.Restaurant{Name: `老王"牛肉麵", 本店`}, "name", `老王"牛肉麵", 本店`},
+		{"換行", model.Restaurant{Address: "信義路 1 號\n2 樓"}, "address", "信義路 1 號\n2 樓"},
+		{"等號開頭的公式", model.Restaurant{Name: `=HYPERLINK("http://example.com","點我")`}, "name", `'=HYPERLINK("http://example.com","點我")`},
+		{"加號開頭", model.Restaurant{Name: "+886 小吃"}, "name", "'+886 小吃"},
+		{"減號開頭", model.Restaurant{Address: "-1 樓"}, "address", "'-1 樓"},
+		{"@ 開頭", model.Restaurant{Name: "@SUM(A1)"}, "name", "'@SUM(A1)"},
+		{"Tab 開頭", model.Restaurant{Name: "\t=1+1"}, "name", "'\t=1+1"},
+		{"中間的等號不處理", model.Restaurant{Name: "1+1=2 餐館"}, "name", "1+1=2 餐館"},
+		{"理由中的公式", model.Restaurant{Reasons: []model.Reason{{Message: "=1+1"}, {Message: "隨機選出"}}}, "reasons", "'=1+1; 隨機選出"},
+		{"座標", model.Restaurant{Lat: 25.0339, Lng: 121.5645}, "lng", "121.5645"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := restaurantsCSV([]model.Restaurant{tt.restaurant})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(data, []byte("\ufeff")) {
+				t.Error("missing UTF-8 BOM")
+			}
+
+			records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff")))).ReadAll()
+			if err != nil {
+				t.Fatalf("cannot parse CSV: %v\n%s", err, data)
+			}
+			if len(records) != 2 {
+				t.Fatalf("records = %d, want header and 1 row", len(records))
+			}
+			index := slices.Index(records[0], tt.column)
+			if index < 0 {
+				t.Fatalf("header %v has no %s", records[0], tt.column)
+			}
+			if got := records[1][index]; got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+// GeoJSON 座標順序為 [經度, 緯度]，沒有座標的餐廳 geometry 為 null
+func TestRestaurantFeatures(t *testing.T) {
+	features := restaurantFeatures([]model.Restaurant{
+		{PlaceID: "a", Name: "一蘭拉麵", Lat: 25.0339, Lng: 121.5645},
+		{PlaceID: "b", Name: "沒有座標"},
+	})
+
+	data, err := json.Marshal(features)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []struct {
+		Type     string `json:"type"`
+		ID       string `json:"id"`
+		Geometry *struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			Name string `json:"name"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != 2 {
+		t.Fatalf("features = %d, want 2", len(decoded))
+	}
+	a := decoded[0]
+	if a.Type != "Feature" || a.ID != "a" || a.Properties.Name != "一蘭拉麵" || a.Geometry == nil || a.Geometry.Type != "Point" {
+		t.Fatalf("feature = %s", data)
+	}
+	if !slices.Equal(a.Geometry.Coordinates, []float64{121.5645, 25.0339}) {
+		t.Errorf("coordinates = %v, want [lng, lat]", a.Geometry.Coordinates)
+	}
+	if decoded[1].Geometry != nil || !strings.Contains(string(data), `"geometry":null`) {
+		t.Errorf("feature without location = %s", data)
+	}
+}
+
+func TestRestaurantFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		query     string
+		accept    string
+		want      string
+		wantError bool
+	}{
+		{"預設為 JSON", "", "", formatJSON, false},
+		{"format 參數", "format=CSV", "", formatCSV, false},
+		{"format 參數優先於 Accept", "format=json", mimeGeoJSON, formatJSON, false},
+		{"Accept GeoJSON", "", mimeGeoJSON, formatGeoJSON, false},
+		{"Accept CSV", "", "text/csv, application/json;q=0.5", formatCSV, false},
+		{"不支援的 Accept", "", "text/html", formatJSON, false},
+		{"不支援的 format", "format=xml", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/restaurants?"+tt.query, nil)
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+
+			got, err := restaurantFormat(c)
+			if (err != nil) != tt.wantError || got != tt.want {
+				t.Errorf("restaurantFormat = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
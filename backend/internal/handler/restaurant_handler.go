@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"what2eat-backend/internal/i18n"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/model"
 	"what2eat-backend/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -14,12 +19,14 @@ import (
 type RestaurantHandler struct {
 	restaurantService *service.RestaurantService
 	counterService    *service.CounterService
+	geocodeService    *service.GeocodeService
 }
 
-func NewRestaurantHandler(restaurantService *service.RestaurantService, counterService *service.CounterService) *RestaurantHandler {
+func NewRestaurantHandler(restaurantService *service.RestaurantService, counterService *service.CounterService, geocodeService *service.GeocodeService) *RestaurantHandler {
 	return &RestaurantHandler{
 		restaurantService: restaurantService,
 		counterService:    counterService,
+		geocodeService:    geocodeService,
 	}
 }
 
@@ -29,76 +36,396 @@ func (h *RestaurantHandler) HealthCheck(c *gin.Context) {
 
 // GetRestaurants 處理GET請求，返回附近餐廳
 func (h *RestaurantHandler) GetRestaurants(c *gin.Context) {
-	// 解析 URL 參數
-	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	// 輸出格式 (json / geojson / csv)，在呼叫 API 前檢查
+	format, err := restaurantFormat(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的緯度參數"})
+		respondRequestError(c, err)
 		return
 	}
 
-	lng, err := strconv.ParseFloat(c.Query("lng"), 64)
+	query, resolved, err := h.parseRecommendQuery(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的經度參數"})
+		respondRequestError(c, err)
 		return
 	}
 
-	// 獲取餐廳類型參數
-	restaurantType := c.Query("type")
-
 	// 紀錄請求
-	fmt.Printf("接收到餐廳搜尋請求: 位置 [%.4f, %.4f], 類型: %s\n", lat, lng, restaurantType)
+	fmt.Printf("接收到餐廳搜尋請求: 位置 [%.4f, %.4f], 類型: %s, 來源: %s\n", query.Lat, query.Lng, query.RestaurantType, query.Source)
 
-	// 檢查API限制
-	if err := h.counterService.CheckDailyLimit(); err != nil {
-		h.counterService.LogAPIRequest("/api/restaurants", lat, lng, restaurantType, false, err.Error())
-		c.JSON(http.StatusTooManyRequests, gin.H{
-			"error":        err.Error(),
-			"usage":        h.counterService.GetUsageString(),
-			"reset_in":     formatDuration(h.counterService.GetTimeUntilReset()),
-			"pacific_time": getPacificTimeString(),
-		})
+	// 檢查API限制（只從收藏推薦時不呼叫 Google API）
+	if query.Source != model.SourceFavorites && !h.checkDailyLimit(c, "/api/restaurants", query.Lat, query.Lng, query.RestaurantType) {
 		return
 	}
 
 	// 使用餐廳服務搜尋附近餐廳
-	restaurants, err := h.restaurantService.RecommendRestaurants(c, lat, lng, restaurantType)
+	result, err := h.restaurantService.RecommendRestaurants(c, query)
 	if err != nil {
-		errMsg := fmt.Sprintf("餐廳搜尋錯誤: %v", err)
-		fmt.Printf("%s\n", errMsg)
-		h.counterService.LogAPIRequest("/api/restaurants", lat, lng, restaurantType, false, errMsg)
-
-		// 檢查是否為 OVER_QUERY_LIMIT 錯誤
-		if strings.Contains(err.Error(), "OVER_QUERY_LIMIT") {
-			// 設置 limitExceeded 標記
-			h.counterService.SetLimitExceeded(true)
-
-			// 傳回特定的錯誤狀態碼與信息
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":        "Google Maps API 每日額度已用完，請明天再試",
-				"details":      err.Error(),
-				"usage":        h.counterService.GetUsageString(),
-				"reset_in":     formatDuration(h.counterService.GetTimeUntilReset()),
-				"pacific_time": getPacificTimeString(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "無法獲取餐廳資訊", "details": err.Error()})
+		h.respondSearchError(c, "/api/restaurants", query.Lat, query.Lng, query.RestaurantType, err)
 		return
 	}
 
 	// 記錄成功的API請求
-	h.counterService.LogAPIRequest("/api/restaurants", lat, lng, restaurantType, true, "")
+	h.counterService.LogAPIRequest("/api/restaurants", query.Lat, query.Lng, query.RestaurantType, true, "")
 
-	c.JSON(http.StatusOK, gin.H{
-		"restaurants":  restaurants,
+	response := gin.H{
+		"restaurants":  result.Restaurants,
+		"diversity":    result.Diversity,
+		"area":         result.Area,
+		"source":       query.Source,
 		"message":      "成功獲取餐廳推薦",
 		"usage":        h.counterService.GetUsageString(),
 		"reset_in":     formatDuration(h.counterService.GetTimeUntilReset()),
 		"pacific_time": getPacificTimeString(),
+	}
+	// 以 near 查詢時回傳解析出的位置，方便確認是否解析正確
+	if resolved != nil {
+		response["location"] = resolved
+	}
+	respondRestaurants(c, format, result.Restaurants, response)
+}
+
+// 解析推薦請求的參數（/api 與 /api/v2 共用）
+// 先檢查不需要網路的參數，最後才以 near 參數解析地點，避免參數錯誤時浪費地點解析的額度
+func (h *RestaurantHandler) parseRecommendQuery(c *gin.Context) (model.RecommendQuery, *model.ResolvedLocation, error) {
+	query := model.RecommendQuery{
+		RestaurantType: c.Query("type"),
+		Strategy:       c.Query("strategy"),
+		Budget:         -1,
+		Language:       requestLanguage(c),
+		Source:         c.DefaultQuery("source", model.SourceNearby),
+		UserID:         middleware.GetUserID(c),
+		TravelMode:     c.Query("travel_mode"),
+	}
+
+	// 推薦策略 (random / weighted)，未指定時使用伺服器預設值
+	if query.Strategy != "" && query.Strategy != model.StrategyRandom && query.Strategy != model.StrategyWeighted {
+		return query, nil, &paramError{Field: "strategy", Message: "無效的推薦策略參數，僅支援 random 或 weighted"}
+	}
+
+	// 預算 (價格等級 0-4)，未指定表示不限
+	if budgetParam := c.Query("budget"); budgetParam != "" {
+		budget, err := strconv.Atoi(budgetParam)
+		if err != nil || budget < 0 || budget > 4 {
+			return query, nil, &paramError{Field: "budget", Message: "無效的預算參數，需為 0-4 的價格等級"}
+		}
+		query.Budget = budget
+	}
+
+	// 候選來源 (nearby / favorites / mixed)
+	if query.Source != model.SourceNearby && query.Source != model.SourceFavorites && query.Source != model.SourceMixed {
+		return query, nil, &paramError{Field: "source", Message: "無效的來源參數，僅支援 nearby、favorites 或 mixed"}
+	}
+	if query.Source != model.SourceNearby && query.UserID == "" {
+		return query, nil, service.ErrUserRequired
+	}
+
+	// 收藏餐廳的最大距離（公尺），未指定使用伺服器預設值
+	if maxDistanceParam := c.Query("max_distance"); maxDistanceParam != "" {
+		maxDistance, err := strconv.ParseFloat(maxDistanceParam, 64)
+		if err != nil || maxDistance <= 0 {
+			return query, nil, &paramError{Field: "max_distance", Message: "無效的最大距離參數"}
+		}
+		query.MaxDistanceMeters = maxDistance
+	}
+
+	// 交通時間 (walking / driving / transit)，未指定時只提供直線距離
+	if query.TravelMode != "" && !service.IsValidTravelMode(query.TravelMode) {
+		return query, nil, &paramError{Field: "travel_mode", Message: "無效的交通方式參數，僅支援 walking、driving 或 transit"}
+	}
+
+	// 解析位置：near 參數（地址或地標）優先，否則使用 lat / lng
+	if near := c.Query("near"); near != "" {
+		resolved, err := h.geocodeService.Resolve(c, near)
+		if err != nil {
+			return query, nil, &locationError{err: err}
+		}
+		query.Lat, query.Lng = resolved.Lat, resolved.Lng
+		return query, resolved, nil
+	}
+
+	var err error
+	if query.Lat, err = strconv.ParseFloat(c.Query("lat"), 64); err != nil {
+		return query, nil, &paramError{Field: "lat", Message: "無效的緯度參數", Kind: model.ErrInvalidLocation}
+	}
+	if query.Lng, err = strconv.ParseFloat(c.Query("lng"), 64); err != nil {
+		return query, nil, &paramError{Field: "lng", Message: "無效的經度參數", Kind: model.ErrInvalidLocation}
+	}
+	return query, nil, nil
+}
+
+// GetRestaurantsInArea 返回地圖範圍內的所有候選餐廳（分頁），供地圖模式使用
+func (h *RestaurantHandler) GetRestaurantsInArea(c *gin.Context) {
+	// 輸出格式 (json / geojson / csv)，在呼叫 API 前檢查
+	format, err := restaurantFormat(c)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	query, err := parseAreaQuery(c)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	centerLat, centerLng := (query.South+query.North)/2, (query.West+query.East)/2
+	fmt.Printf("接收到範圍搜尋請求: [%.4f, %.4f] - [%.4f, %.4f], 類型: %s\n", query.South, query.West, query.North, query.East, query.RestaurantType)
+
+	result, err := h.restaurantService.SearchArea(c, query)
+	if err != nil {
+		h.respondSearchError(c, "/api/restaurants/area", centerLat, centerLng, query.RestaurantType, err)
+		return
+	}
+
+	h.counterService.LogAPIRequest("/api/restaurants/area", centerLat, centerLng, query.RestaurantType, true, "")
+
+	respondRestaurants(c, format, result.Restaurants, gin.H{
+		"restaurants":  result.Restaurants,
+		"total":        result.Total,
+		"page":         result.Page,
+		"page_size":    result.PageSize,
+		"total_pages":  result.TotalPages,
+		"tiles":        result.Tiles,
+		"complete":     result.Complete,
+		"message":      "成功獲取範圍內的餐廳",
+		"usage":        h.counterService.GetUsageString(),
+		"reset_in":     formatDuration(h.counterService.GetTimeUntilReset()),
+		"pacific_time": getPacificTimeString(),
+	})
+}
+
+// 解析範圍搜尋的參數（/api 與 /api/v2 共用）
+func parseAreaQuery(c *gin.Context) (model.AreaSearchQuery, error) {
+	query := model.AreaSearchQuery{
+		RestaurantType: c.Query("type"),
+		Language:       requestLanguage(c),
+	}
+
+	var err error
+	if query.South, query.West, err = parseLatLng(c.Query("sw")); err != nil {
+		return query, &paramError{Field: "sw", Message: "無效的西南角參數 sw，格式為 緯度,經度", Kind: model.ErrInvalidLocation}
+	}
+	if query.North, query.East, err = parseLatLng(c.Query("ne")); err != nil {
+		return query, &paramError{Field: "ne", Message: "無效的東北角參數 ne，格式為 緯度,經度", Kind: model.ErrInvalidLocation}
+	}
+
+	if query.Page, err = strconv.Atoi(c.DefaultQuery("page", "1")); err != nil || query.Page < 1 || query.Page > service.MaxAreaPage {
+		return query, &paramError{Field: "page", Message: "無效的頁碼參數"}
+	}
+	if query.PageSize, err = strconv.Atoi(c.DefaultQuery("page_size", "0")); err != nil || query.PageSize < 0 {
+		return query, &paramError{Field: "page_size", Message: "無效的每頁數量參數"}
+	}
+	return query, nil
+}
+
+// 解析「緯度,經度」格式的座標，拒絕 NaN 與 Inf
+func parseLatLng(value string) (float64, float64, error) {
+	latText, lngText, ok := strings.Cut(value, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("座標格式需為 緯度,經度")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngText), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if math.IsNaN(lat) || math.IsInf(lat, 0) || math.IsNaN(lng) || math.IsInf(lng, 0) {
+		return 0, 0, fmt.Errorf("座標需為有限的數值")
+	}
+	return lat, lng, nil
+}
+
+type groupParticipantRequest struct {
+	Name string `json:"name"`
+	// 使用指標讓赤道或本初子午線上的 0 也能通過必填檢查
+	Lat *float64 `json:"lat" binding:"required"`
+	Lng *float64 `json:"lng" binding:"required"`
+}
+
+type groupRecommendRequest struct {
+	Participants []groupParticipantRequest `json:"participants" binding:"required,min=1,max=20,dive"`
+	Method       string                    `json:"method"`
+	Type         string                    `json:"type"`
+	Strategy     string                    `json:"strategy"`
+	Budget       *int                      `json:"budget"`
+}
+
+// GetGroupRecommendations 處理POST請求，依多位參與者的位置計算會面地點並推薦餐廳
+func (h *RestaurantHandler) GetGroupRecommendations(c *gin.Context) {
+	// 輸出格式 (json / geojson / csv)，在呼叫 API 前檢查
+	format, err := restaurantFormat(c)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	query, err := parseGroupQuery(c)
+	if err != nil {
+		var paramErr *paramError
+		if errors.As(err, &paramErr) && paramErr.Field == "participants" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": paramErr.Message, "details": paramErr.Details})
+			return
+		}
+		respondRequestError(c, err)
+		return
+	}
+
+	// 以第一位參與者的位置記錄請求
+	first := query.Participants[0]
+	fmt.Printf("接收到團體推薦請求: %d 位參與者, 類型: %s\n", len(query.Participants), query.RestaurantType)
+
+	if !h.checkDailyLimit(c, "/api/recommend/group", first.Lat, first.Lng, query.RestaurantType) {
+		return
+	}
+
+	result, err := h.restaurantService.RecommendForGroup(c, query)
+	if err != nil {
+		h.respondSearchError(c, "/api/recommend/group", first.Lat, first.Lng, query.RestaurantType, err)
+		return
+	}
+
+	h.counterService.LogAPIRequest("/api/recommend/group", result.MeetingPoint.Lat, result.MeetingPoint.Lng, query.RestaurantType, true, "")
+
+	respondRestaurants(c, format, result.Restaurants, gin.H{
+		"meeting_point":       result.MeetingPoint,
+		"method":              result.Method,
+		"max_distance_meters": result.MaxDistanceMeters,
+		"restaurants":         result.Restaurants,
+		"diversity":           result.Diversity,
+		"area":                result.Area,
+		"message":             "成功獲取團體餐廳推薦",
+		"usage":               h.counterService.GetUsageString(),
+		"reset_in":            formatDuration(h.counterService.GetTimeUntilReset()),
+		"pacific_time":        getPacificTimeString(),
 	})
 }
 
+// 解析團體推薦的請求內容（/api 與 /api/v2 共用）
+func parseGroupQuery(c *gin.Context) (model.GroupRecommendQuery, error) {
+	var req groupRecommendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return model.GroupRecommendQuery{}, &paramError{Field: "participants", Message: "無效的參與者位置", Details: err.Error()}
+	}
+
+	if req.Method != "" && req.Method != model.MeetingMethodMidpoint && req.Method != model.MeetingMethodMinimax {
+		return model.GroupRecommendQuery{}, &paramError{Field: "method", Message: "無效的會面地點計算方式，僅支援 midpoint 或 minimax"}
+	}
+	if req.Strategy != "" && req.Strategy != model.StrategyRandom && req.Strategy != model.StrategyWeighted {
+		return model.GroupRecommendQuery{}, &paramError{Field: "strategy", Message: "無效的推薦策略參數，僅支援 random 或 weighted"}
+	}
+	budget := -1
+	if req.Budget != nil {
+		budget = *req.Budget
+		if budget < 0 || budget > 4 {
+			return model.GroupRecommendQuery{}, &paramError{Field: "budget", Message: "無效的預算參數，需為 0-4 的價格等級"}
+		}
+	}
+
+	participants := make([]model.GroupParticipant, len(req.Participants))
+	for i, p := range req.Participants {
+		participants[i] = model.GroupParticipant{Name: p.Name, Lat: *p.Lat, Lng: *p.Lng}
+	}
+
+	return model.GroupRecommendQuery{
+		Participants: participants,
+		Method:       req.Method,
+		RecommendQuery: model.RecommendQuery{
+			RestaurantType: req.Type,
+			Strategy:       req.Strategy,
+			Budget:         budget,
+			Language:       requestLanguage(c),
+		},
+	}, nil
+}
+
+// 檢查每日API限制，超過時記錄請求並返回錯誤
+func (h *RestaurantHandler) dailyLimitError(endpoint string, lat, lng float64, restaurantType string) error {
+	if err := h.counterService.CheckDailyLimit(); err != nil {
+		h.counterService.LogAPIRequest(endpoint, lat, lng, restaurantType, false, err.Error())
+		return err
+	}
+	return nil
+}
+
+// 檢查每日API限制，超過時直接回應 429 並返回 false
+func (h *RestaurantHandler) checkDailyLimit(c *gin.Context, endpoint string, lat, lng float64, restaurantType string) bool {
+	if err := h.dailyLimitError(endpoint, lat, lng, restaurantType); err != nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":        err.Error(),
+			"usage":        h.counterService.GetUsageString(),
+			"reset_in":     formatDuration(h.counterService.GetTimeUntilReset()),
+			"pacific_time": getPacificTimeString(),
+		})
+		return false
+	}
+	return true
+}
+
+// 記錄餐廳搜尋錯誤，Google API 回報額度用完時標記為超過限制，之後的請求不再呼叫
+func (h *RestaurantHandler) recordSearchError(endpoint string, lat, lng float64, restaurantType string, err error) {
+	errMsg := fmt.Sprintf("餐廳搜尋錯誤: %v", err)
+	fmt.Printf("%s\n", errMsg)
+	h.counterService.LogAPIRequest(endpoint, lat, lng, restaurantType, false, errMsg)
+
+	if errors.Is(err, model.ErrUpstreamQuota) {
+		h.counterService.SetLimitExceeded(true)
+	}
+}
+
+// 記錄並回應餐廳搜尋錯誤
+func (h *RestaurantHandler) respondSearchError(c *gin.Context, endpoint string, lat, lng float64, restaurantType string, err error) {
+	h.recordSearchError(endpoint, lat, lng, restaurantType, err)
+
+	status, code := apiErrorStatus(err)
+	switch code {
+	case model.ErrorCodeUpstreamQuota, model.ErrorCodeQuotaExceeded:
+		message := err.Error()
+		if code == model.ErrorCodeUpstreamQuota {
+			message = "Google Maps API 每日額度已用完，請明天再試"
+		}
+		c.JSON(status, gin.H{
+			"error":        message,
+			"details":      err.Error(),
+			"usage":        h.counterService.GetUsageString(),
+			"reset_in":     formatDuration(h.counterService.GetTimeUntilReset()),
+			"pacific_time": getPacificTimeString(),
+		})
+	case model.ErrorCodeInternal, model.ErrorCodeUpstreamUnavailable, model.ErrorCodeTimeout:
+		c.JSON(status, gin.H{"error": "無法獲取餐廳資訊", "details": err.Error()})
+	default:
+		// 請求本身的問題（如範圍太大），直接回應錯誤訊息
+		c.JSON(status, gin.H{"error": err.Error()})
+	}
+}
+
+// 回應請求參數、使用者身分或地點解析的錯誤（/api 的回應格式）
+func respondRequestError(c *gin.Context, err error) {
+	var paramErr *paramError
+	var locationErr *locationError
+	switch {
+	case errors.As(err, &paramErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": paramErr.Message})
+	case errors.Is(err, model.ErrUnauthorized):
+		status, code := apiErrorStatus(err)
+		c.JSON(status, gin.H{"error": err.Error(), "code": code})
+	case errors.As(err, &locationErr):
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "near": c.Query("near")})
+	default:
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+	}
+}
+
+// 取得請求語系：優先使用 lang 參數，其次為 Accept-Language 標頭
+func requestLanguage(c *gin.Context) string {
+	if lang := c.Query("lang"); lang != "" {
+		return i18n.NormalizeLang(lang)
+	}
+	return i18n.NormalizeLang(c.GetHeader("Accept-Language"))
+}
+
 // 格式化持續時間，移除秒數中的小數點
 func formatDuration(d time.Duration) string {
 	// 將時間轉換為小時、分鐘和秒
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseAreaQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		params    url.Values
+		wantField string
+	}{
+		{name: "有效的範圍", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"25.04, 121.57"}, "page": {"2"}}},
+		{name: "頁碼上限", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"25.04,121.57"}, "page": {strconv.Itoa(service.MaxAreaPage)}}},
+		{name: "頁碼會溢位", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"25.04,121.57"}, "page": {strconv.Itoa(service.MaxAreaPage + 1)}}, wantField: "page"},
+		{name: "頁碼為最大整數", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"25.04,121.57"}, "page": {strconv.Itoa(math.MaxInt)}}, wantField: "page"},
+		{name: "頁碼為 0", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"25.04,121.57"}, "page": {"0"}}, wantField: "page"},
+		{name: "每頁數量為負數", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"25.04,121.57"}, "page_size": {"-1"}}, wantField: "page_size"},
+		{name: "西南角為 NaN", params: url.Values{"sw": {"NaN,121.56"}, "ne": {"25.04,121.57"}}, wantField: "sw"},
+		{name: "東北角為 Inf", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"25.04,+Inf"}}, wantField: "ne"},
+		{name: "東北角為 -Inf", params: url.Values{"sw": {"25.03,121.56"}, "ne": {"-inf,121.57"}}, wantField: "ne"},
+		{name: "缺少經度", params: url.Values{"sw": {"25.03"}, "ne": {"25.04,121.57"}}, wantField: "sw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/restaurants/area?"+tt.params.Encode(), nil)
+
+			_, err := parseAreaQuery(c)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			var paramErr *paramError
+			if !errors.As(err, &paramErr) || paramErr.Field != tt.wantField {
+				t.Errorf("err = %v, want paramError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestParseGroupQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		body      string
+		wantField string
+	}{
+		{name: "一般位置", body: `{"participants":[{"name":"A","lat":25.03,"lng":121.56}]}`},
+		{name: "赤道與本初子午線上的 0", body: `{"participants":[{"name":"A","lat":0,"lng":0},{"name":"B","lat":0.5,"lng":-0.5}]}`},
+		{name: "缺少緯度", body: `{"participants":[{"name":"A","lng":121.56}]}`, wantField: "participants"},
+		{name: "沒有參與者", body: `{"participants":[]}`, wantField: "participants"},
+		{name: "無效的計算方式", body: `{"participants":[{"lat":0,"lng":0}],"method":"center"}`, wantField: "method"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/api/recommend/group", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			query, err := parseGroupQuery(c)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				if len(query.Participants) == 0 {
+					t.Error("participants are empty")
+				}
+				return
+			}
+			var paramErr *paramError
+			if !errors.As(err, &paramErr) || paramErr.Field != tt.wantField {
+				t.Errorf("err = %v, want paramError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"what2eat-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRestaurantsStream 以 Server-Sent Events 推送推薦流程的進度，參數與 GetRestaurants 相同
+// 事件依序為 started、candidates、每家完成的 pick，最後是 done（額度使用量）或 error
+// 參數錯誤與超過每日額度時在串流開始前以一般 JSON 回應；客戶端中斷連線時停止之後的搜尋
+func (h *RestaurantHandler) GetRestaurantsStream(c *gin.Context) {
+	query, resolved, err := h.parseRecommendQuery(c)
+	if err != nil {
+		respondRequestError(c, err)
+		return
+	}
+
+	fmt.Printf("接收到串流餐廳搜尋請求: 位置 [%.4f, %.4f], 類型: %s, 來源: %s\n", query.Lat, query.Lng, query.RestaurantType, query.Source)
+
+	// 檢查API限制（只從收藏推薦時不呼叫 Google API）
+	if query.Source != model.SourceFavorites && !h.checkDailyLimit(c, "/api/restaurants/stream", query.Lat, query.Lng, query.RestaurantType) {
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	send := func(event string, data any) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+
+	// 使用請求本身的 context，客戶端中斷連線時取消名稱搜尋與照片之間的等待
+	ctx := c.Request.Context()
+	result, err := h.restaurantService.RecommendRestaurantsStream(ctx, query, func(event model.RecommendEvent) {
+		switch event.Type {
+		case model.RecommendEventStarted:
+			started := gin.H{"source": event.Source, "type": query.RestaurantType, "lat": query.Lat, "lng": query.Lng}
+			if event.CacheStatus != "" {
+				started["cache"] = event.CacheStatus
+			}
+			if resolved != nil {
+				started["location"] = resolved
+			}
+			send("started", started)
+		case model.RecommendEventCandidates:
+			send("candidates", gin.H{"count": event.Candidates})
+		case model.RecommendEventPick:
+			send("pick", gin.H{"index": event.Index, "restaurant": event.Restaurant})
+		}
+	})
+	if err != nil {
+		h.recordSearchError("/api/restaurants/stream", query.Lat, query.Lng, query.RestaurantType, err)
+		if ctx.Err() != nil {
+			// 客戶端已中斷連線，不需要回應
+			return
+		}
+
+		_, code := apiErrorStatus(err)
+		message := err.Error()
+		switch code {
+		case model.ErrorCodeUpstreamQuota:
+			message = "Google Maps API 每日額度已用完，請明天再試"
+		case model.ErrorCodeInternal, model.ErrorCodeUpstreamUnavailable, model.ErrorCodeTimeout:
+			message = "無法獲取餐廳資訊"
+		}
+		send("error", gin.H{"error": message, "code": code, "details": err.Error()})
+		return
+	}
+
+	// 記錄成功的API請求
+	h.counterService.LogAPIRequest("/api/restaurants/stream", query.Lat, query.Lng, query.RestaurantType, true, "")
+
+	send("done", gin.H{
+		"count":        len(result.Restaurants),
+		"diversity":    result.Diversity,
+		"area":         result.Area,
+		"source":       query.Source,
+		"message":      "成功獲取餐廳推薦",
+		"usage":        h.counterService.GetUsageString(),
+		"reset_in":     formatDuration(h.counterService.GetTimeUntilReset()),
+		"pacific_time": getPacificTimeString(),
+	})
+}
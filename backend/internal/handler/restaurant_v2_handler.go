@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"what2eat-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API v2 與 /api 共用參數解析與 RestaurantService，差別只在回應格式：
+// 成功與失敗皆為 model.APIResponse，錯誤以固定的代碼表示，不需要比對訊息文字
+
+// GetRestaurantsV2 推薦附近餐廳
+func (h *RestaurantHandler) GetRestaurantsV2(c *gin.Context) {
+	query, resolved, err := h.parseRecommendQuery(c)
+	if err != nil {
+		h.respondAPIError(c, err)
+		return
+	}
+
+	fmt.Printf("接收到餐廳搜尋請求 (v2): 位置 [%.4f, %.4f], 類型: %s, 來源: %s\n", query.Lat, query.Lng, query.RestaurantType, query.Source)
+
+	// 只從收藏推薦時不呼叫 Google API
+	if query.Source != model.SourceFavorites {
+		if err := h.dailyLimitError("/api/v2/restaurants", query.Lat, query.Lng, query.RestaurantType); err != nil {
+			h.respondAPIError(c, err)
+			return
+		}
+	}
+
+	result, err := h.restaurantService.RecommendRestaurants(c, query)
+	if err != nil {
+		h.recordSearchError("/api/v2/restaurants", query.Lat, query.Lng, query.RestaurantType, err)
+		h.respondAPIError(c, err)
+		return
+	}
+
+	h.counterService.LogAPIRequest("/api/v2/restaurants", query.Lat, query.Lng, query.RestaurantType, true, "")
+
+	data := gin.H{
+		"restaurants": result.Restaurants,
+		"diversity":   result.Diversity,
+		"area":        result.Area,
+		"source":      query.Source,
+	}
+	if resolved != nil {
+		data["location"] = resolved
+	}
+
+	meta := h.apiMeta(c)
+	meta.Cache = result.CacheStatus
+	meta.Providers = result.Providers
+	c.JSON(http.StatusOK, model.APIResponse{Data: data, Meta: meta})
+}
+
+// GetRestaurantsInAreaV2 返回地圖範圍內的所有候選餐廳（分頁）
+func (h *RestaurantHandler) GetRestaurantsInAreaV2(c *gin.Context) {
+	query, err := parseAreaQuery(c)
+	if err != nil {
+		h.respondAPIError(c, err)
+		return
+	}
+
+	centerLat, centerLng := (query.South+query.North)/2, (query.West+query.East)/2
+	result, err := h.restaurantService.SearchArea(c, query)
+	if err != nil {
+		h.recordSearchError("/api/v2/restaurants/area", centerLat, centerLng, query.RestaurantType, err)
+		h.respondAPIError(c, err)
+		return
+	}
+
+	h.counterService.LogAPIRequest("/api/v2/restaurants/area", centerLat, centerLng, query.RestaurantType, true, "")
+
+	meta := h.apiMeta(c)
+	meta.Cache = result.Tiles.CacheStatus()
+	meta.Providers = []string{model.ProviderGooglePlaces}
+	c.JSON(http.StatusOK, model.APIResponse{Data: result, Meta: meta})
+}
+
+// GetGroupRecommendationsV2 依多位參與者的位置計算會面地點並推薦餐廳
+func (h *RestaurantHandler) GetGroupRecommendationsV2(c *gin.Context) {
+	query, err := parseGroupQuery(c)
+	if err != nil {
+		h.respondAPIError(c, err)
+		return
+	}
+
+	first := query.Participants[0]
+	if err := h.dailyLimitError("/api/v2/recommend/group", first.Lat, first.Lng, query.RestaurantType); err != nil {
+		h.respondAPIError(c, err)
+		return
+	}
+
+	result, err := h.restaurantService.RecommendForGroup(c, query)
+	if err != nil {
+		h.recordSearchError("/api/v2/recommend/group", first.Lat, first.Lng, query.RestaurantType, err)
+		h.respondAPIError(c, err)
+		return
+	}
+
+	h.counterService.LogAPIRequest("/api/v2/recommend/group", result.MeetingPoint.Lat, result.MeetingPoint.Lng, query.RestaurantType, true, "")
+
+	meta := h.apiMeta(c)
+	meta.Cache = result.CacheStatus
+	meta.Providers = result.Providers
+	c.JSON(http.StatusOK, model.APIResponse{Data: result, Meta: meta})
+}
+
+// GetUsageV2 返回每日額度使用量，不呼叫 Google API
+func (h *RestaurantHandler) GetUsageV2(c *gin.Context) {
+	meta := h.apiMeta(c)
+	c.JSON(http.StatusOK, model.APIResponse{
+		Data: gin.H{
+			"usage":          meta.Usage,
+			"limit_exceeded": h.counterService.IsLimitExceeded(),
+		},
+		Meta: meta,
+	})
+}
+
+// RespondValidationErrorV2 以 API v2 的格式回應 OpenAPI 參數驗證失敗（middleware.ValidateQuery 使用）
+func (h *RestaurantHandler) RespondValidationErrorV2(c *gin.Context, field string, err error) {
+	paramErr := &paramError{Field: field, Message: err.Error()}
+	switch field {
+	case "lat", "lng", "sw", "ne":
+		paramErr.Kind = model.ErrInvalidLocation
+	}
+	h.respondAPIError(c, paramErr)
+}
+
+// API v2 回應的 meta：額度使用量與重置時間
+func (h *RestaurantHandler) apiMeta(c *gin.Context) model.APIMeta {
+	used, limit := h.counterService.GetUsage()
+	resetIn := h.counterService.GetTimeUntilReset()
+	return model.APIMeta{
+		Usage:          model.APIUsage{Used: used, Limit: limit, Remaining: max(limit-used, 0)},
+		ResetAt:        time.Now().Add(resetIn).UTC().Truncate(time.Second),
+		ResetInSeconds: int(resetIn.Seconds()),
+		Language:       requestLanguage(c),
+	}
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSE 心跳間隔，避免代理伺服器因閒置而中斷連線
+const sseHeartbeatInterval = 15 * time.Second
+
+type RoomHandler struct {
+	roomService    *service.RoomService
+	counterService *service.CounterService
+}
+
+func NewRoomHandler(roomService *service.RoomService, counterService *service.CounterService) *RoomHandler {
+	return &RoomHandler{
+		roomService:    roomService,
+		counterService: counterService,
+	}
+}
+
+type createRoomRequest struct {
+	// 使用指標讓赤道或本初子午線上的 0 也能通過必填檢查
+	Lat             *float64 `json:"lat" binding:"required"`
+	Lng             *float64 `json:"lng" binding:"required"`
+	Type            string   `json:"type"`
+	Strategy        string   `json:"strategy"`
+	Budget          *int     `json:"budget"`
+	PoolSize        int      `json:"pool_size"`
+	DeadlineMinutes int      `json:"deadline_minutes"`
+	Quorum          int      `json:"quorum"`
+}
+
+type joinRoomRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type roomBallotRequest struct {
+	ParticipantID string `json:"participant_id" binding:"required"`
+	PlaceID       string `json:"place_id" binding:"required"`
+}
+
+// CreateRoom 建立團體投票房間
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	var req createRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+
+	budget := -1
+	if req.Budget != nil {
+		budget = *req.Budget
+	}
+	if budget > 4 || req.PoolSize < 0 || req.DeadlineMinutes < 0 || req.Quorum < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的房間參數"})
+		return
+	}
+
+	// 建立房間會呼叫推薦流程，需先檢查API限制
+	if err := h.counterService.CheckDailyLimit(); err != nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":    err.Error(),
+			"usage":    h.counterService.GetUsageString(),
+			"reset_in": formatDuration(h.counterService.GetTimeUntilReset()),
+		})
+		return
+	}
+
+	room, err := h.roomService.CreateRoom(c, service.CreateRoomRequest{
+		Query: model.RoomQuery{
+			Lat:            *req.Lat,
+			Lng:            *req.Lng,
+			RestaurantType: req.Type,
+			Strategy:       req.Strategy,
+			Budget:         budget,
+		},
+		PoolSize: req.PoolSize,
+		Deadline: time.Duration(req.DeadlineMinutes) * time.Minute,
+		Quorum:   req.Quorum,
+		Language: requestLanguage(c),
+		Creator:  middleware.GetUserID(c),
+	})
+	if err != nil {
+		fmt.Printf("建立投票房間失敗: %v\n", err)
+		c.JSON(errorStatus(err), gin.H{"error": "無法建立投票房間", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"room": room, "usage": h.counterService.GetUsageString()})
+}
+
+// GetRoom 取得房間目前狀態
+func (h *RoomHandler) GetRoom(c *gin.Context) {
+	room, err := h.roomService.GetRoom(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room": room})
+}
+
+// JoinRoom 以顯示名稱加入房間
+func (h *RoomHandler) JoinRoom(c *gin.Context) {
+	var req joinRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供顯示名稱"})
+		return
+	}
+
+	participantID, room, err := h.roomService.Join(c.Param("id"), req.Name)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"participant_id": participantID, "room": room})
+}
+
+// Vote 投票給候選餐廳
+func (h *RoomHandler) Vote(c *gin.Context) {
+	var req roomBallotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供 participant_id 與 place_id"})
+		return
+	}
+
+	room, err := h.roomService.Vote(c.Param("id"), req.ParticipantID, req.PlaceID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room": room})
+}
+
+// Veto 否決候選餐廳
+func (h *RoomHandler) Veto(c *gin.Context) {
+	var req roomBallotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供 participant_id 與 place_id"})
+		return
+	}
+
+	room, err := h.roomService.Veto(c.Param("id"), req.ParticipantID, req.PlaceID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room": room})
+}
+
+// CloseRoom 提前結束投票，只有建立房間的使用者可以操作
+func (h *RoomHandler) CloseRoom(c *gin.Context) {
+	room, err := h.roomService.Close(c.Param("id"), middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room": room})
+}
+
+// Events 以 Server-Sent Events 推送房間狀態，房間結束後關閉連線
+func (h *RoomHandler) Events(c *gin.Context) {
+	room, events, unsubscribe, err := h.roomService.Subscribe(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// 先送出目前狀態
+	c.SSEvent("state", room)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case view, ok := <-events:
+			if !ok {
+				// 房間已結束或過期，送出最終狀態
+				if final, err := h.roomService.GetRoom(c.Param("id")); err == nil {
+					c.SSEvent("closed", final)
+				} else {
+					c.SSEvent("closed", gin.H{"id": c.Param("id")})
+				}
+				return false
+			}
+			c.SSEvent("state", view)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+	"what2eat-backend/internal/middleware"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleHandler struct {
+	schedulerService *service.SchedulerService
+}
+
+func NewScheduleHandler(schedulerService *service.SchedulerService) *ScheduleHandler {
+	return &ScheduleHandler{schedulerService: schedulerService}
+}
+
+type scheduleRequest struct {
+	Name     string               `json:"name"`
+	Cron     string               `json:"cron"`
+	Timezone string               `json:"timezone"` // 可選，預設使用伺服器設定的時區
+	Lat      float64              `json:"lat"`
+	Lng      float64              `json:"lng"`
+	Type     string               `json:"type"`
+	Budget   *int                 `json:"budget"` // 可選，預設不限
+	Count    int                  `json:"count"`
+	Target   model.ScheduleTarget `json:"target"`
+	Enabled  *bool                `json:"enabled"` // 可選，預設啟用
+}
+
+func (r scheduleRequest) toSchedule() model.Schedule {
+	schedule := model.Schedule{
+		Name:           r.Name,
+		Cron:           r.Cron,
+		Timezone:       r.Timezone,
+		Lat:            r.Lat,
+		Lng:            r.Lng,
+		RestaurantType: r.Type,
+		Budget:         -1,
+		Count:          r.Count,
+		Target:         r.Target,
+		Enabled:        true,
+	}
+	if r.Budget != nil {
+		schedule.Budget = *r.Budget
+	}
+	if r.Enabled != nil {
+		schedule.Enabled = *r.Enabled
+	}
+	return schedule
+}
+
+// CreateSchedule 新增定期推送的排程
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req scheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+
+	schedule, err := h.schedulerService.CreateSchedule(middleware.GetUserID(c), req.toSchedule())
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"schedule": schedule})
+}
+
+// ListSchedules 列出使用者的排程
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.schedulerService.ListSchedules(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "無法取得排程", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules, "count": len(schedules)})
+}
+
+// GetSchedule 取得單一排程
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	schedule, err := h.schedulerService.GetSchedule(middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedule": schedule})
+}
+
+// UpdateSchedule 以新的設定取代排程
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	var req scheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+
+	schedule, err := h.schedulerService.UpdateSchedule(middleware.GetUserID(c), c.Param("id"), req.toSchedule())
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedule": schedule})
+}
+
+// DeleteSchedule 刪除排程
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	if err := h.schedulerService.DeleteSchedule(middleware.GetUserID(c), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已刪除排程"})
+}
+
+// RunSchedule 立即執行一次排程，發送結果可從發送紀錄查詢
+func (h *ScheduleHandler) RunSchedule(c *gin.Context) {
+	delivery, err := h.schedulerService.RunNow(middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"delivery": delivery})
+}
+
+// ListDeliveries 列出排程的發送紀錄
+func (h *ScheduleHandler) ListDeliveries(c *gin.Context) {
+	deliveries, err := h.schedulerService.ListDeliveries(middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries, "count": len(deliveries)})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShareHandler struct {
+	shareService     *service.ShareService
+	shareCardService *service.ShareCardService
+}
+
+func NewShareHandler(shareService *service.ShareService, shareCardService *service.ShareCardService) *ShareHandler {
+	return &ShareHandler{shareService: shareService, shareCardService: shareCardService}
+}
+
+type createShareRequest struct {
+	Restaurants []model.Restaurant `json:"restaurants"`
+	Query       model.ShareQuery   `json:"query"`
+}
+
+// CreateShare 保存推薦結果並返回分享 ID
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	var req createShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容", "details": err.Error()})
+		return
+	}
+
+	share, err := h.shareService.CreateShare(req.Restaurants, req.Query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"share": share})
+}
+
+// GetShare 取得分享的推薦結果
+func (h *ShareHandler) GetShare(c *gin.Context) {
+	share, err := h.shareService.GetShare(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"share": share})
+}
+
+// SharePage 返回含 Open Graph 與 Twitter Card 標籤的頁面，供 LINE、Slack 等產生連結預覽
+func (h *ShareHandler) SharePage(c *gin.Context) {
+	page, err := h.shareCardService.RenderPage(c.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		c.String(status, http.StatusText(status))
+		return
+	}
+
+	// 頁面只需要內嵌樣式與本站的卡片圖片，放寬全域的 default-src 'self'
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src 'self'")
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+// ShareCard 返回分享的 PNG 預覽卡片
+func (h *ShareHandler) ShareCard(c *gin.Context) {
+	card, err := h.shareCardService.GetCard(c.Param("id"))
+	if err != nil {
+		status := errorStatus(err)
+		c.String(status, http.StatusText(status))
+		return
+	}
+
+	c.Header("ETag", card.ETag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", cardMaxAge(card.ExpiresAt)))
+	if c.GetHeader("If-None-Match") == card.ETag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", card.PNG)
+}
+
+// 卡片內容不會變動，快取到分享過期為止（最多一天）
+func cardMaxAge(expiresAt time.Time) int {
+	remaining := time.Until(expiresAt)
+	if remaining > 24*time.Hour {
+		remaining = 24 * time.Hour
+	}
+	return max(int(remaining.Seconds()), 0)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"what2eat-backend/internal/infrastructure"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SlackHandler struct {
+	slackService  *service.SlackService
+	signingSecret string
+}
+
+func NewSlackHandler(slackService *service.SlackService, signingSecret string) *SlackHandler {
+	return &SlackHandler{
+		slackService:  slackService,
+		signingSecret: signingSecret,
+	}
+}
+
+// Command 處理 /what2eat slash command
+func (h *SlackHandler) Command(c *gin.Context) {
+	if !h.verifyRequest(c) {
+		return
+	}
+
+	var cmd model.SlackCommand
+	if err := c.ShouldBind(&cmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的請求內容"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.slackService.HandleCommand(cmd))
+}
+
+// Interaction 處理投票按鈕等互動元件的回呼
+func (h *SlackHandler) Interaction(c *gin.Context) {
+	if !h.verifyRequest(c) {
+		return
+	}
+
+	var interaction model.SlackInteraction
+	if err := json.Unmarshal([]byte(c.PostForm("payload")), &interaction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無效的互動內容"})
+		return
+	}
+
+	if err := h.slackService.HandleInteraction(c, interaction); err != nil {
+		fmt.Printf("處理 Slack 互動失敗: %v\n", err)
+	}
+	c.Status(http.StatusOK)
+}
+
+// 驗證 Slack 請求簽章，驗證後還原請求內容供表單解析使用
+func (h *SlackHandler) verifyRequest(c *gin.Context) bool {
+	if h.signingSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Slack 整合未啟用"})
+		return false
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "無法讀取請求內容"})
+		return false
+	}
+
+	if !infrastructure.ValidateSlackSignature(h.signingSecret, c.GetHeader("X-Slack-Request-Timestamp"), body, c.GetHeader("X-Slack-Signature")) {
+		fmt.Printf("Slack 簽章驗證失敗，來源 IP: %s\n", c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "無效的簽章"})
+		return false
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
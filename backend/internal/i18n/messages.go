@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"strings"
+)
+
+// 支援的語系
+const (
+	LangZhTW = "zh-TW"
+	LangEn   = "en"
+
+	DefaultLang = LangZhTW
+)
+
+// 訊息範本，以 {name} 表示參數
+var catalog = map[string]map[string]string{
+	LangZhTW: {
+		"reason.nearby_search": "附近的餐廳",
+		"reason.type_search":   "符合「{type}」搜尋",
+		"reason.keyword_match": "透過關鍵字「{keyword}」符合{type}",
+		"reason.highly_rated":  "高評價（{rating} 分，{reviews} 則評論）",
+		"reason.well_rated":    "評價不錯（{rating} 分）",
+		"reason.within":        "距離 {distance} 以內",
+		"reason.fits_budget":   "符合預算（{price}）",
+		"reason.random_pick":   "隨機選出",
+		"reason.in_favorites":  "在你的收藏中",
+
+		"reason.recently_eaten_cuisine": "{days} 天內吃過{cuisine}，其他選擇不足時才推薦",
+		"reason.recently_visited":       "{days} 天內來過，其他選擇不足時才推薦",
+		"reason.you_liked":              "你曾按讚",
+		"reason.likes_cuisine":          "你喜歡{cuisine}",
+
+		"travel.walking":           "步行 {minutes} 分鐘",
+		"travel.driving":           "開車 {minutes} 分鐘",
+		"travel.transit":           "大眾運輸 {minutes} 分鐘",
+		"travel.walking_estimated": "步行約 {minutes} 分鐘",
+		"travel.driving_estimated": "開車約 {minutes} 分鐘",
+		"travel.transit_estimated": "大眾運輸約 {minutes} 分鐘",
+
+		"error.invalid_argument":        "參數 {field} 無效",
+		"error.invalid_location":        "無效的位置 {field}",
+		"error.no_results":              "沒有符合條件的結果",
+		"error.timeout":                 "地圖服務回應逾時，請稍後再試",
+		"error.unauthorized":            "需要使用者身分",
+		"error.location_not_found":      "找不到符合的地點",
+		"error.area_too_large":          "範圍太大，請放大地圖後再試",
+		"error.quota_exceeded":          "今日的免費額度已用完，請於 {reset_in} 後再試",
+		"error.upstream_quota_exceeded": "Google Maps API 每日額度已用完，請明天再試",
+		"error.upstream_unavailable":    "地圖服務暫時無法使用，請稍後再試",
+		"error.internal":                "無法獲取餐廳資訊，請稍後再試",
+		"error.forbidden":               "沒有權限執行此操作",
+		"error.not_found":               "找不到指定的資料",
+		"error.conflict":                "與目前的狀態衝突",
+		"error.gone":                    "資料已過期",
+		"error.rate_limited":            "操作過於頻繁，請稍後再試",
+		"error.not_implemented":         "伺服器未啟用此功能",
+	},
+	LangEn: {
+		"reason.nearby_search": "nearby restaurant",
+		"reason.type_search":   "matches \"{type}\" search",
+		"reason.keyword_match": "matches {type} via keyword {keyword}",
+		"reason.highly_rated":  "highly rated ({rating}, {reviews} reviews)",
+		"reason.well_rated":    "well rated ({rating})",
+		"reason.within":        "within {distance}",
+		"reason.fits_budget":   "fits your budget ({price})",
+		"reason.random_pick":   "picked at random",
+		"reason.in_favorites":  "in your favourites",
+
+		"reason.recently_eaten_cuisine": "you had {cuisine} in the last {days} days; shown because options were limited",
+		"reason.recently_visited":       "you ate here in the last {days} days; shown because options were limited",
+		"reason.you_liked":              "you liked this place",
+		"reason.likes_cuisine":          "you like {cuisine}",
+
+		"travel.walking":           "{minutes} min walk",
+		"travel.driving":           "{minutes} min drive",
+		"travel.transit":           "{minutes} min by transit",
+		"travel.walking_estimated": "about {minutes} min walk",
+		"travel.driving_estimated": "about {minutes} min drive",
+		"travel.transit_estimated": "about {minutes} min by transit",
+
+		"error.invalid_argument":        "invalid value for {field}",
+		"error.invalid_location":        "invalid location {field}",
+		"error.no_results":              "no matching results",
+		"error.timeout":                 "the map service timed out; try again later",
+		"error.unauthorized":            "a user identity is required",
+		"error.location_not_found":      "no matching location found",
+		"error.area_too_large":          "the area is too large; zoom in and try again",
+		"error.quota_exceeded":          "today's free quota is used up; try again in {reset_in}",
+		"error.upstream_quota_exceeded": "the Google Maps API quota is used up; try again tomorrow",
+		"error.upstream_unavailable":    "the map service is temporarily unavailable; try again later",
+		"error.internal":                "could not load restaurants; try again later",
+		"error.forbidden":               "you are not allowed to do this",
+		"error.not_found":               "not found",
+		"error.conflict":                "conflicts with the current state",
+		"error.gone":                    "this has expired",
+		"error.rate_limited":            "too many requests; try again later",
+		"error.not_implemented":         "this feature is not enabled on the server",
+	},
+}
+
+// NormalizeLang 將請求語系（如 Accept-Language 標頭或 lang 參數）對應到支援的語系
+func NormalizeLang(lang string) string {
+	for _, part := range strings.Split(lang, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		switch {
+		case tag == "":
+			continue
+		case strings.HasPrefix(tag, "zh"):
+			return LangZhTW
+		case strings.HasPrefix(tag, "en"):
+			return LangEn
+		}
+	}
+	return DefaultLang
+}
+
+// Translate 依語系產生訊息，找不到範本時退回預設語系，再退回訊息代碼
+func Translate(lang, key string, params map[string]string) string {
+	template, ok := catalog[NormalizeLang(lang)][key]
+	if !ok {
+		if template, ok = catalog[DefaultLang][key]; !ok {
+			return key
+		}
+	}
+
+	for name, value := range params {
+		template = strings.ReplaceAll(template, "{"+name+"}", value)
+	}
+	return template
+}
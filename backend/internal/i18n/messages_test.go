@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLang(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"", LangZhTW},
+		{"zh-TW", LangZhTW},
+		{"zh-Hant-TW,zh;q=0.9", LangZhTW},
+		{"en", LangEn},
+		{"en-US,en;q=0.9,zh-TW;q=0.8", LangEn},
+		{"EN-GB", LangEn},
+		{"fr-FR,en;q=0.5", LangEn},
+		{"fr-FR,de;q=0.5", LangZhTW},
+		{" , ;q=0.1", LangZhTW},
+	}
+	for _, tt := range tests {
+		if got := NormalizeLang(tt.lang); got != tt.want {
+			t.Errorf("NormalizeLang(%q) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   string
+		key    string
+		params map[string]string
+		want   string
+	}{
+		{"中文", "zh-TW", "reason.within", map[string]string{"distance": "300m"}, "距離 300m 以內"},
+		{"英文", "en", "reason.within", map[string]string{"distance": "300m"}, "within 300m"},
+		{"Accept-Language 標頭", "en-US,en;q=0.9", "reason.random_pick", nil, "picked at random"},
+		{"不支援的語系退回預設語系", "ja", "reason.random_pick", nil, "隨機選出"},
+		{"多個參數", "en", "reason.highly_rated", map[string]string{"rating": "4.6", "reviews": "1.2k"}, "highly rated (4.6, 1.2k reviews)"},
+		{"未知的訊息代碼返回代碼", "en", "reason.unknown", nil, "reason.unknown"},
+		{"缺少參數時保留範本", "en", "reason.within", nil, "within {distance}"},
+		{"多餘的參數被忽略", "zh-TW", "reason.random_pick", map[string]string{"extra": "x"}, "隨機選出"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Translate(tt.lang, tt.key, tt.params); got != tt.want {
+				t.Errorf("Translate(%q, %q) = %q, want %q", tt.lang, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+// 每個語系都提供與預設語系相同的訊息與參數
+func TestCatalogsMatch(t *testing.T) {
+	for lang, messages := range catalog {
+		if lang == DefaultLang {
+			continue
+		}
+		for key, template := range catalog[DefaultLang] {
+			translated, ok := messages[key]
+			if !ok {
+				t.Errorf("%s: missing %s", lang, key)
+				continue
+			}
+			for _, param := range placeholders(template) {
+				if !strings.Contains(translated, param) {
+					t.Errorf("%s: %s lacks %s", lang, key, param)
+				}
+			}
+		}
+		for key := range messages {
+			if _, ok := catalog[DefaultLang][key]; !ok {
+				t.Errorf("%s: %s is not in the default catalog", lang, key)
+			}
+		}
+	}
+}
+
+// 取出範本中的 {name} 參數
+func placeholders(template string) []string {
+	var params []string
+	for {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			return params
+		}
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			return params
+		}
+		params = append(params, template[start:start+end+1])
+		template = template[start+end+1:]
+	}
+}
@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"what2eat-backend/internal/model"
+
+	"googlemaps.github.io/maps"
+)
+
+// Distance Matrix API 每次請求最多 25 個目的地
+const distanceMatrixMaxDestinations = 25
+
+var distanceMatrixModes = map[string]maps.Mode{
+	model.TravelModeWalking: maps.TravelModeWalking,
+	model.TravelModeDriving: maps.TravelModeDriving,
+	model.TravelModeTransit: maps.TravelModeTransit,
+}
+
+// GoogleDistanceMatrix 以 Google Distance Matrix API 計算實際路線的交通時間
+type GoogleDistanceMatrix struct {
+	client *maps.Client
+}
+
+func NewGoogleDistanceMatrix(client *maps.Client) *GoogleDistanceMatrix {
+	return &GoogleDistanceMatrix{client: client}
+}
+
+func (g *GoogleDistanceMatrix) Name() string {
+	return "google"
+}
+
+// TravelTimes 計算起點到各目的地的交通時間，找不到路線的目的地返回 nil
+func (g *GoogleDistanceMatrix) TravelTimes(ctx context.Context, origin model.Location, destinations []model.Location, mode string) ([]*model.TravelTime, error) {
+	travelMode, ok := distanceMatrixModes[mode]
+	if !ok {
+		return nil, fmt.Errorf("不支援的交通方式: %s", mode)
+	}
+
+	results := make([]*model.TravelTime, 0, len(destinations))
+	for start := 0; start < len(destinations); start += distanceMatrixMaxDestinations {
+		end := min(start+distanceMatrixMaxDestinations, len(destinations))
+
+		request := &maps.DistanceMatrixRequest{
+			Origins:      []string{formatLatLng(origin)},
+			Destinations: make([]string, 0, end-start),
+			Mode:         travelMode,
+			Units:        maps.UnitsMetric,
+		}
+		// 開車與大眾運輸依出發時間考慮路況與班次
+		if mode != model.TravelModeWalking {
+			request.DepartureTime = "now"
+		}
+		for _, destination := range destinations[start:end] {
+			request.Destinations = append(request.Destinations, formatLatLng(destination))
+		}
+
+		response, err := g.client.DistanceMatrix(ctx, request)
+		if err != nil {
+			return nil, model.NewUpstreamError("Google Distance Matrix API", err)
+		}
+		if len(response.Rows) != 1 || len(response.Rows[0].Elements) != end-start {
+			return nil, fmt.Errorf("距離矩陣回應格式不符: %d 列", len(response.Rows))
+		}
+
+		for _, element := range response.Rows[0].Elements {
+			if element.Status != "OK" {
+				results = append(results, nil)
+				continue
+			}
+			duration := element.Duration
+			if element.DurationInTraffic > 0 {
+				duration = element.DurationInTraffic
+			}
+			results = append(results, &model.TravelTime{
+				Mode:            mode,
+				DurationSeconds: int(duration.Seconds()),
+				DistanceMeters:  element.Distance.Meters,
+				Provider:        g.Name(),
+			})
+		}
+	}
+	return results, nil
+}
+
+func formatLatLng(location model.Location) string {
+	return fmt.Sprintf("%.6f,%.6f", location.Lat, location.Lng)
+}
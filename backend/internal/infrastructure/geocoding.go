@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"context"
+	"what2eat-backend/internal/model"
+
+	"googlemaps.github.io/maps"
+)
+
+// GoogleGeocoder 以 Google Geocoding API 解析地址或地標
+type GoogleGeocoder struct {
+	client *maps.Client
+	region string
+}
+
+// NewGoogleGeocoder region 為偏好的國家代碼（如 tw），用於消除同名地點的歧義
+func NewGoogleGeocoder(client *maps.Client, region string) *GoogleGeocoder {
+	return &GoogleGeocoder{client: client, region: region}
+}
+
+func (g *GoogleGeocoder) Name() string {
+	return model.GeocodeSourceGoogle
+}
+
+// Geocode 解析地點，查無結果時返回 nil
+func (g *GoogleGeocoder) Geocode(ctx context.Context, query string) (*model.ResolvedLocation, error) {
+	results, err := g.client.Geocode(ctx, &maps.GeocodingRequest{
+		Address:  query,
+		Region:   g.region,
+		Language: "zh-TW",
+	})
+	if err != nil {
+		return nil, model.NewUpstreamError("Google Geocoding API", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	result := results[0]
+	location := &model.ResolvedLocation{
+		Query:   query,
+		Address: result.FormattedAddress,
+		Lat:     result.Geometry.Location.Lat,
+		Lng:     result.Geometry.Location.Lng,
+		Source:  g.Name(),
+	}
+	if len(result.AddressComponents) > 0 {
+		location.Name = result.AddressComponents[0].LongName
+	}
+	return location, nil
+}
+
+// ReverseGeocode 取得座標所在的行政區，查無結果時返回 nil
+func (g *GoogleGeocoder) ReverseGeocode(ctx context.Context, lat, lng float64) (*model.Area, error) {
+	results, err := g.client.Geocode(ctx, &maps.GeocodingRequest{
+		LatLng:   &maps.LatLng{Lat: lat, Lng: lng},
+		Language: "zh-TW",
+	})
+	if err != nil {
+		return nil, model.NewUpstreamError("Google Geocoding API", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	// 各國行政區層級不同，依序取第一個出現的類型（臺灣為 縣市 → 鄉鎮市區）
+	components := make(map[string]string)
+	for _, component := range results[0].AddressComponents {
+		for _, t := range component.Types {
+			if _, exists := components[t]; !exists {
+				components[t] = component.LongName
+			}
+		}
+	}
+	area := &model.Area{
+		City:    firstComponent(components, "administrative_area_level_1", "locality"),
+		Country: components["country"],
+		Source:  g.Name(),
+	}
+	area.District = firstComponent(components, "administrative_area_level_2", "administrative_area_level_3", "sublocality_level_1", "locality")
+	if area.District == area.City {
+		area.District = ""
+	}
+	return area, nil
+}
+
+func firstComponent(components map[string]string, types ...string) string {
+	for _, t := range types {
+		if name := components[t]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
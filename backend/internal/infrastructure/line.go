@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LineClient 呼叫 LINE Messaging API，baseURL 可指向本地的假伺服器以便測試
+type LineClient struct {
+	baseURL     string
+	accessToken string
+	httpClient  *http.Client
+}
+
+func NewLineClient(baseURL, accessToken string) *LineClient {
+	return &LineClient{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		accessToken: accessToken,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Reply 以 reply token 回覆訊息（一次最多 5 則）
+func (l *LineClient) Reply(ctx context.Context, replyToken string, messages []map[string]any) error {
+	return l.post(ctx, "/v2/bot/message/reply", map[string]any{
+		"replyToken": replyToken,
+		"messages":   messages,
+	})
+}
+
+// Push 主動推送訊息給使用者、群組或多人聊天（一次最多 5 則，計入 LINE 的訊息額度）
+func (l *LineClient) Push(ctx context.Context, to string, messages []map[string]any) error {
+	return l.post(ctx, "/v2/bot/message/push", map[string]any{
+		"to":       to,
+		"messages": messages,
+	})
+}
+
+func (l *LineClient) post(ctx context.Context, path string, payload map[string]any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("無法序列化 LINE 訊息: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+l.accessToken)
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("LINE API 請求失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("LINE API 回應 %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// ValidateLineSignature 驗證 X-Line-Signature 標頭（以 channel secret 計算的 HMAC-SHA256，Base64 編碼）
+func ValidateLineSignature(channelSecret string, body []byte, signature string) bool {
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(channelSecret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
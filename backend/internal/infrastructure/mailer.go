@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"fmt"
+	"mime"
+	"net/smtp"
+)
+
+// LogMailer 將 Email 登入連結輸出到伺服器日誌，用於本地開發與測試
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) SendMagicLink(email, link string) error {
+	fmt.Printf("Email 登入連結 (%s): %s\n", email, link)
+	return nil
+}
+
+// SMTPConfig SMTP 寄件設定
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer 透過 SMTP 寄送 Email 登入連結
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) SendMagicLink(email, link string) error {
+	subject := mime.QEncoding.Encode("utf-8", "What2Eat 登入連結")
+	body := fmt.Sprintf("點擊以下連結完成登入：\r\n\r\n%s\r\n", link)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		m.cfg.From, email, subject, body)
+
+	auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	if err := smtp.SendMail(m.cfg.Host+":"+m.cfg.Port, auth, m.cfg.From, []string{email}, []byte(message)); err != nil {
+		return fmt.Errorf("無法寄送登入連結: %w", err)
+	}
+	return nil
+}
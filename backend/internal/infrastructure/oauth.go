@@ -0,0 +1,153 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"what2eat-backend/internal/model"
+)
+
+// OAuthConfig OAuth 2.0 提供者設定 (Authorization Code 流程)
+type OAuthConfig struct {
+	Name         string // 提供者名稱，如 google、line
+	ClientID     string
+	ClientSecret string
+	AuthURL      string // 授權頁面
+	TokenURL     string // 交換 access token
+	UserInfoURL  string // 取得使用者資訊 (需回傳 sub 或 id，以及 email)
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OAuthProvider 透過標準 OAuth 2.0 流程驗證使用者
+type OAuthProvider struct {
+	cfg        OAuthConfig
+	httpClient *http.Client
+}
+
+func NewOAuthProvider(cfg OAuthConfig) *OAuthProvider {
+	return &OAuthProvider{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *OAuthProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL 產生導向提供者授權頁面的網址
+func (p *OAuthProvider) AuthCodeURL(state string) string {
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.cfg.ClientID},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"scope":         {strings.Join(p.cfg.Scopes, " ")},
+		"state":         {state},
+	}
+
+	separator := "?"
+	if strings.Contains(p.cfg.AuthURL, "?") {
+		separator = "&"
+	}
+	return p.cfg.AuthURL + separator + params.Encode()
+}
+
+// Exchange 以授權碼交換 access token，並取得使用者資訊
+func (p *OAuthProvider) Exchange(ctx context.Context, code string) (*model.ExternalIdentity, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.doJSON(req, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("OAuth token 交換失敗: %w", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("OAuth 提供者未回傳 access token")
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tokenResponse.AccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var userInfo struct {
+		Sub   string `json:"sub"`
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	}
+	if err := p.doJSON(req, &userInfo); err != nil {
+		return nil, fmt.Errorf("無法取得 OAuth 使用者資訊: %w", err)
+	}
+
+	subject := userInfo.Sub
+	if subject == "" {
+		subject = userInfo.ID
+	}
+	if subject == "" {
+		return nil, fmt.Errorf("OAuth 使用者資訊缺少識別碼")
+	}
+
+	return &model.ExternalIdentity{Provider: p.cfg.Name, Subject: subject, Email: userInfo.Email}, nil
+}
+
+func (p *OAuthProvider) doJSON(req *http.Request, v interface{}) error {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// LocalIdentityProvider 本地測試用的身分提供者，不連線到外部服務
+// 授權碼格式為 "<subject>" 或 "<subject>:<email>"，直接視為驗證成功
+type LocalIdentityProvider struct {
+	redirectURL string
+}
+
+func NewLocalIdentityProvider(redirectURL string) *LocalIdentityProvider {
+	return &LocalIdentityProvider{redirectURL: redirectURL}
+}
+
+func (p *LocalIdentityProvider) Name() string {
+	return "local"
+}
+
+// AuthCodeURL 直接導回 callback，並帶入以 state 產生的測試授權碼
+func (p *LocalIdentityProvider) AuthCodeURL(state string) string {
+	params := url.Values{"code": {"local-user"}, "state": {state}}
+	return p.redirectURL + "?" + params.Encode()
+}
+
+func (p *LocalIdentityProvider) Exchange(ctx context.Context, code string) (*model.ExternalIdentity, error) {
+	subject, email, _ := strings.Cut(code, ":")
+	if subject == "" {
+		return nil, fmt.Errorf("無效的授權碼")
+	}
+	return &model.ExternalIdentity{Provider: p.Name(), Subject: subject, Email: email}, nil
+}
@@ -0,0 +1,145 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Slack 請求時間戳記的容許誤差，超過視為重送攻擊
+const slackSignatureMaxAge = 5 * time.Minute
+
+// SlackClient 呼叫 Slack Web API，baseURL 可指向本地的替身伺服器以便測試
+type SlackClient struct {
+	baseURL    string
+	botToken   string
+	httpClient *http.Client
+}
+
+func NewSlackClient(baseURL, botToken string) *SlackClient {
+	return &SlackClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		botToken:   botToken,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// PostMessage 發送訊息到頻道，返回訊息的時間戳記（用於之後更新訊息）
+func (s *SlackClient) PostMessage(ctx context.Context, channel, text string, blocks []map[string]any) (string, error) {
+	var resp struct {
+		TS string `json:"ts"`
+	}
+	err := s.call(ctx, "chat.postMessage", map[string]any{
+		"channel": channel,
+		"text":    text,
+		"blocks":  blocks,
+	}, &resp)
+	return resp.TS, err
+}
+
+// UpdateMessage 更新已發送的訊息
+func (s *SlackClient) UpdateMessage(ctx context.Context, channel, ts, text string, blocks []map[string]any) error {
+	return s.call(ctx, "chat.update", map[string]any{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+		"blocks":  blocks,
+	}, nil)
+}
+
+// Respond 透過 slash command 或互動元件提供的 response_url 回覆訊息（可為僅自己可見）
+func (s *SlackClient) Respond(ctx context.Context, responseURL string, message map[string]any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("無法序列化 Slack 訊息: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Slack 回覆失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack 回覆失敗: 狀態碼 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *SlackClient) call(ctx context.Context, method string, payload map[string]any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("無法序列化 Slack 訊息: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.botToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Slack API 請求失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack API %s 回應 %d", method, resp.StatusCode)
+	}
+
+	// Slack Web API 以 ok 欄位表示成功與否
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("無法解析 Slack API 回應: %w", err)
+	}
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return fmt.Errorf("無法解析 Slack API 回應: %w", err)
+	}
+	if !status.OK {
+		return fmt.Errorf("Slack API %s 失敗: %s", method, status.Error)
+	}
+
+	if out != nil {
+		return json.Unmarshal(raw, out)
+	}
+	return nil
+}
+
+// ValidateSlackSignature 驗證 Slack 請求簽章：v0=hex(HMAC-SHA256("v0:{timestamp}:{body}"))
+func ValidateSlackSignature(signingSecret, timestamp string, body []byte, signature string) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(ts, 0))
+	if age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
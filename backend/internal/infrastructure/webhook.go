@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+	"what2eat-backend/internal/model"
+)
+
+// WebhookClient 將 JSON 內容 POST 到外部 webhook（通用 webhook 與 Slack incoming webhook）
+type WebhookClient struct {
+	httpClient *http.Client
+}
+
+// NewWebhookClient 建立 webhook 客戶端；allowPrivate 為 false 時，在連線時拒絕
+// 迴路、私有、鏈路本地等內部位址，DNS 解析或轉址到內部位址同樣會被擋下
+func NewWebhookClient(allowPrivate bool) *WebhookClient {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", model.ErrBlockedAddress, address)
+			}
+			if IsBlockedWebhookAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", model.ErrBlockedAddress, addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	// 不使用環境變數中的 proxy，確保檢查的是實際連線的位址
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+	}
+	return &WebhookClient{httpClient: &http.Client{Timeout: 10 * time.Second, Transport: transport}}
+}
+
+// IsBlockedWebhookAddr 判斷位址是否為 webhook 不可連線的內部或特殊用途位址
+func IsBlockedWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range blockedWebhookPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// 標準函式庫未涵蓋的特殊用途位址
+var blockedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // 本網路
+	netip.MustParsePrefix("100.64.0.0/10"),  // 電信級 NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF 協定指派
+	netip.MustParsePrefix("198.18.0.0/15"),  // 效能測試
+	netip.MustParsePrefix("240.0.0.0/4"),    // 保留位址與廣播
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64，可能轉譯到內部 IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"), // 本地 NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4，可能內嵌內部 IPv4
+}
+
+// PostJSON 發送 JSON 內容，非 2xx 回應視為失敗；錯誤只記錄狀態碼，不保存回應內容
+func (w *WebhookClient) PostJSON(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("無法序列化 webhook 內容: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "What2Eat-Scheduler")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook 請求失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 回應 %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"what2eat-backend/internal/model"
+)
+
+func TestIsBlockedWebhookAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true}, // 雲端中繼資料服務
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"224.0.0.1", true},
+		{"255.255.255.255", true},
+		{"::1", true},
+		{"fc00::1", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"64:ff9b::a00:1", true},
+		{"8.8.8.8", false},
+		{"203.74.1.1", false},
+		{"2001:4860:4860::8888", false},
+		{"::ffff:8.8.8.8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsBlockedWebhookAddr(netip.MustParseAddr(tt.addr)); got != tt.blocked {
+				t.Errorf("IsBlockedWebhookAddr(%s) = %v, want %v", tt.addr, got, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestWebhookClientBlocksPrivateAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+	defer server.Close()
+
+	// httptest 伺服器位於 127.0.0.1，以 localhost 解析同樣會被擋下
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		err := NewWebhookClient(false).PostJSON(context.Background(), url, map[string]string{"text": "hi"})
+		if !errors.Is(err, model.ErrBlockedAddress) {
+			t.Errorf("PostJSON(%s) err = %v, want ErrBlockedAddress", url, err)
+		}
+	}
+	if called {
+		t.Error("blocked webhook received a request")
+	}
+
+	if err := NewWebhookClient(true).PostJSON(context.Background(), server.URL, map[string]string{"text": "hi"}); err != nil || !called {
+		t.Errorf("allowPrivate: err = %v, called = %v", err, called)
+	}
+}
+
+func TestWebhookErrorOmitsResponseBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal secret: token=abc", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewWebhookClient(true).PostJSON(context.Background(), server.URL, map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "secret") {
+		t.Errorf("err = %v, want status code only", err)
+	}
+}
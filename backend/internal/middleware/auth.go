@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// UserIDKey gin context 中儲存使用者 ID 的鍵
+	UserIDKey = "user_id"
+
+	// AuthTokenHeader 自動發放匿名權杖時使用的回應標頭，前端需保存並在之後的請求帶入
+	AuthTokenHeader = "X-Auth-Token"
+)
+
+// Authenticator 驗證與發放使用者權杖
+type Authenticator interface {
+	Authenticate(token string) (string, error)
+	IssueDeviceToken() (string, string, error)
+}
+
+// RequireUser 驗證 Authorization: Bearer 權杖，並將使用者 ID 放入 gin context
+// 請求沒有帶權杖時自動發放匿名裝置權杖（透過 X-Auth-Token 回應標頭），不強制註冊
+func RequireUser(auth Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c)
+		if token == "" {
+			newToken, userID, err := auth.IssueDeviceToken()
+			if err != nil {
+				fmt.Printf("無法發放匿名權杖: %v\n", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "無法建立使用者身分"})
+				c.Abort()
+				return
+			}
+
+			c.Header(AuthTokenHeader, newToken)
+			c.Set(UserIDKey, userID)
+			c.Next()
+			return
+		}
+
+		userID, err := auth.Authenticate(token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "無效的使用者身分",
+				"code":    "UNAUTHORIZED",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(UserIDKey, userID)
+		c.Next()
+	}
+}
+
+// OptionalUser 帶有有效權杖時將使用者 ID 放入 gin context，否則照常處理請求
+func OptionalUser(auth Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := bearerToken(c); token != "" {
+			if userID, err := auth.Authenticate(token); err == nil {
+				c.Set(UserIDKey, userID)
+			}
+		}
+		c.Next()
+	}
+}
+
+// 從 Authorization 標頭取得 Bearer 權杖
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// GetUserID 取得已驗證的使用者 ID，未驗證時返回空字串
+func GetUserID(c *gin.Context) string {
+	return c.GetString(UserIDKey)
+}
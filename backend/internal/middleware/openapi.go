@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidationErrorResponder 回應參數驗證失敗的請求，field 為出錯的參數名稱（無法判斷時為空字串）
+type ValidationErrorResponder func(c *gin.Context, field string, err error)
+
+// ValidateQuery 依 OpenAPI 文件檢查路徑與查詢參數，不符合時交由 respond 回應並中止請求
+// 只檢查參數，請求內容與使用者身分仍由各 handler 與 RequireUser 處理；文件中沒有的路由直接放行
+func ValidateQuery(doc *openapi3.T, respond ValidationErrorResponder) gin.HandlerFunc {
+	options := &openapi3filter.Options{
+		ExcludeRequestBody: true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		path := openAPIPath(c.FullPath())
+		pathItem := doc.Paths.Find(path)
+		if pathItem == nil || pathItem.GetOperation(c.Request.Method) == nil {
+			c.Next()
+			return
+		}
+
+		pathParams := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			pathParams[param.Key] = param.Value
+		}
+
+		err := openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route: &routers.Route{
+				Spec:      doc,
+				Path:      path,
+				PathItem:  pathItem,
+				Method:    c.Request.Method,
+				Operation: pathItem.GetOperation(c.Request.Method),
+			},
+			Options: options,
+		})
+		if err != nil {
+			field, reason := validationReason(err)
+			respond(c, field, reason)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// 取出出錯的參數名稱與原因，省略 kin-openapi 錯誤訊息中附帶的 schema 內容
+func validationReason(err error) (string, error) {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) || requestErr.Parameter == nil {
+		return "", err
+	}
+
+	field := requestErr.Parameter.Name
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case errors.As(err, &schemaErr):
+		return field, fmt.Errorf("參數 %s: %s", field, schemaErr.Reason)
+	case requestErr.Err != nil:
+		return field, fmt.Errorf("參數 %s: %v", field, requestErr.Err)
+	default:
+		return field, fmt.Errorf("參數 %s: %s", field, requestErr.Reason)
+	}
+}
+
+// RespondValidationError 以 /api 的格式回應參數驗證失敗
+func RespondValidationError(c *gin.Context, field string, err error) {
+	message := "無效的請求參數"
+	if field != "" {
+		message = "無效的 " + field + " 參數"
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+}
+
+// 將 gin 的路由格式 (/rooms/:id) 轉為 OpenAPI 的路徑格式 (/rooms/{id})
+func openAPIPath(fullPath string) string {
+	segments := strings.Split(fullPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
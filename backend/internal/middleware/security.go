@@ -9,32 +9,61 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// 全域速率限制器 - 每秒最多 10 個請求，突發 20 個
-var globalLimiter = rate.NewLimiter(rate.Limit(30), 50)
-
-var (
-	ipLimiters   = make(map[string]*rate.Limiter)
-	limiterMutex = &sync.Mutex{}
+// 速率限制器的額度
+const (
+	globalRate  = 30 // 全域每秒最多 30 個請求
+	globalBurst = 50 // 全域突發 50 個
+	apiRate     = 10 // API 每秒最多 10 個請求
+	apiBurst    = 20 // API 突發 20 個
+	ipRate      = 1  // 每個 IP 每秒補充 1 個請求
+	ipBurst     = 60 // 每個 IP 突發 60 個
 )
 
-// 取得或建立 IP 專用的速率限制器
-func getIPLimiter(ip string) *rate.Limiter {
-	limiter, exists := ipLimiters[ip]
+// RateLimiters 全域、API 與各 IP 的速率限制器
+// 同一組限制器同時交給 REST 中間件與 gRPC 攔截器，不論請求走哪一種協定都計入同一份額度
+type RateLimiters struct {
+	global *rate.Limiter
+	api    *rate.Limiter
+
+	mu  sync.Mutex
+	ips map[string]*rate.Limiter
+}
+
+func NewRateLimiters() *RateLimiters {
+	return &RateLimiters{
+		global: rate.NewLimiter(globalRate, globalBurst),
+		api:    rate.NewLimiter(apiRate, apiBurst),
+		ips:    make(map[string]*rate.Limiter),
+	}
+}
+
+// AllowGlobal 以全域速率限制器檢查是否放行請求
+func (l *RateLimiters) AllowGlobal() bool {
+	return l.global.Allow()
+}
+
+// AllowAPI 以 API 速率限制器檢查是否放行請求
+func (l *RateLimiters) AllowAPI() bool {
+	return l.api.Allow()
+}
+
+// AllowIP 以該 IP 的速率限制器檢查是否放行請求
+func (l *RateLimiters) AllowIP(ip string) bool {
+	l.mu.Lock()
+	limiter, exists := l.ips[ip]
 	if !exists {
-		// 每個 IP 每秒最多 3 個請求，突發 5 個
-		limiter = rate.NewLimiter(rate.Limit(10), 15)
-		ipLimiters[ip] = limiter
+		limiter = rate.NewLimiter(ipRate, ipBurst)
+		l.ips[ip] = limiter
 	}
-	return limiter
+	l.mu.Unlock()
+
+	return limiter.Allow()
 }
 
 // 全域流量限制中間件
-func GlobalRateLimit() gin.HandlerFunc {
-	// 全局限流從每秒 20 個請求改為每秒 30 個請求
-	limiter := rate.NewLimiter(30, 50)
-
+func GlobalRateLimit(limiters *RateLimiters) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if !limiter.Allow() {
+		if !limiters.AllowGlobal() {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "系統繁忙，請稍後再試",
 			})
@@ -46,18 +75,10 @@ func GlobalRateLimit() gin.HandlerFunc {
 }
 
 // IP 流量限制中間件
-func IPRateLimit() gin.HandlerFunc {
+func IPRateLimit(limiters *RateLimiters) gin.HandlerFunc {
 	// IP 速率從每分鐘 30 個請求改為每分鐘 60 個
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiterMutex.Lock()
-		if _, exists := ipLimiters[ip]; !exists {
-			ipLimiters[ip] = rate.NewLimiter(1, 60)
-		}
-		limiter := ipLimiters[ip]
-		limiterMutex.Unlock()
-
-		if !limiter.Allow() {
+		if !limiters.AllowIP(c.ClientIP()) {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "請求過於頻繁，請稍後再試",
 			})
@@ -107,8 +128,19 @@ func RequestSizeLimit(maxSize int64) gin.HandlerFunc {
 }
 
 // 超時中間件
-func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+// streamRoutes 為 Server-Sent Events 等長連線的路由（如 /api/rooms/:id/events），不論 Accept 標頭為何都不套用超時
+func TimeoutMiddleware(timeout time.Duration, streamRoutes ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(streamRoutes))
+	for _, route := range streamRoutes {
+		exempt[route] = true
+	}
+
 	return func(c *gin.Context) {
+		if exempt[c.FullPath()] {
+			c.Next()
+			return
+		}
+
 		// 簡單的超時實現
 		done := make(chan bool, 1)
 
@@ -135,12 +167,9 @@ func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
 }
 
 // API 專用流量限制 (更嚴格)
-func APIRateLimit() gin.HandlerFunc {
-	// 從每秒 5 個請求改為每秒 10 個請求
-	limiter := rate.NewLimiter(10, 20)
-
+func APIRateLimit(limiters *RateLimiters) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if !limiter.Allow() {
+		if !limiters.AllowAPI() {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "請求過於頻繁，請稍後再試",
 			})
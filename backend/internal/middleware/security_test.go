@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 串流路由不論 Accept 標頭為何都不套用超時
+func TestTimeoutMiddlewareExemptsStreamRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	slow := func(c *gin.Context) {
+		time.Sleep(50 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	}
+	r := gin.New()
+	r.Use(TimeoutMiddleware(10*time.Millisecond, "/rooms/:id/events"))
+	r.GET("/rooms/:id/events", slow)
+
+	tests := []struct {
+		name   string
+		accept string
+	}{
+		{"不帶 Accept", ""},
+		{"Accept 為 */*", "*/*"},
+		{"Accept 含多個類型", "text/event-stream, */*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/rooms/abc/events", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+		})
+	}
+}
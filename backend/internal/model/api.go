@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// API v2 的錯誤代碼，客戶端應依代碼判斷錯誤類型，不要比對訊息文字
+// 代碼一經發布不再變更意義，只會新增
+const (
+	ErrorCodeInvalidArgument     = "INVALID_ARGUMENT"        // 請求參數錯誤，Field 為出錯的參數
+	ErrorCodeInvalidLocation     = "INVALID_LOCATION"        // 座標超出範圍或地點格式錯誤
+	ErrorCodeUnauthorized        = "UNAUTHORIZED"            // 需要使用者身分
+	ErrorCodeLocationNotFound    = "LOCATION_NOT_FOUND"      // near 參數找不到符合的地點
+	ErrorCodeAreaTooLarge        = "AREA_TOO_LARGE"          // 地圖範圍超過格子上限
+	ErrorCodeNoResults           = "NO_RESULTS"              // 沒有符合條件的結果
+	ErrorCodeQuotaExceeded       = "QUOTA_EXCEEDED"          // 本服務的每日額度已用完
+	ErrorCodeUpstreamQuota       = "UPSTREAM_QUOTA_EXCEEDED" // Google API 回報額度用完 (OVER_QUERY_LIMIT)
+	ErrorCodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"    // 外部服務錯誤或無法連線
+	ErrorCodeTimeout             = "TIMEOUT"                 // 外部服務逾時
+	ErrorCodeInternal            = "INTERNAL"                // 其他伺服器錯誤
+	ErrorCodeForbidden           = "FORBIDDEN"               // 身分有效但沒有權限
+	ErrorCodeNotFound            = "NOT_FOUND"               // 找不到指定的資源
+	ErrorCodeConflict            = "CONFLICT"                // 與資源目前的狀態衝突
+	ErrorCodeGone                = "GONE"                    // 資源已過期
+	ErrorCodeRateLimited         = "RATE_LIMITED"            // 操作過於頻繁
+	ErrorCodeNotImplemented      = "NOT_IMPLEMENTED"         // 伺服器未啟用此功能
+)
+
+// 推薦結果的緩存狀態
+const (
+	CacheStatusHit     = "hit"     // 使用緩存，未呼叫 Google API
+	CacheStatusMiss    = "miss"    // 呼叫 Google API
+	CacheStatusPartial = "partial" // 部分使用緩存（範圍搜尋的格子）
+)
+
+// 推薦結果的資料來源
+const (
+	ProviderGooglePlaces = "google_places"
+	ProviderFavorites    = "favorites"
+)
+
+// APIResponse API v2 的回應格式：成功時有 Data，失敗時有 Errors，兩者皆附上 Meta
+type APIResponse struct {
+	Data   any        `json:"data"`
+	Meta   APIMeta    `json:"meta"`
+	Errors []APIError `json:"errors,omitempty"`
+}
+
+// APIMeta 與回應資料無關的額度、緩存與來源資訊
+type APIMeta struct {
+	Usage          APIUsage  `json:"usage"`
+	ResetAt        time.Time `json:"reset_at"`         // 每日額度重置時間 (UTC)
+	ResetInSeconds int       `json:"reset_in_seconds"` // 距離重置的秒數
+	Cache          string    `json:"cache,omitempty"`
+	Providers      []string  `json:"providers,omitempty"`
+	Language       string    `json:"language"`
+}
+
+// APIUsage 每日額度使用量
+type APIUsage struct {
+	Used      int `json:"used"`
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+}
+
+// APIError 錯誤代碼與依請求語系產生的訊息
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
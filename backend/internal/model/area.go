@@ -0,0 +1,49 @@
+package model
+
+// Area 查詢位置所在的行政區
+type Area struct {
+	District string `json:"district,omitempty"`
+	City     string `json:"city,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Source   string `json:"source"` // gazetteer（本地行政區資料）或 google
+}
+
+// AreaSearchQuery 地圖範圍搜尋的請求參數
+type AreaSearchQuery struct {
+	South, West, North, East float64 // 範圍的西南角與東北角
+	RestaurantType           string
+	Language                 string
+	Page                     int // 從 1 開始
+	PageSize                 int
+}
+
+// AreaSearchResult 範圍搜尋結果，Restaurants 為目前頁面的餐廳
+type AreaSearchResult struct {
+	Restaurants []Restaurant `json:"restaurants"`
+	Total       int          `json:"total"`
+	Page        int          `json:"page"`
+	PageSize    int          `json:"page_size"`
+	TotalPages  int          `json:"total_pages"`
+	Tiles       AreaTiles    `json:"tiles"`
+	Complete    bool         `json:"complete"` // 所有格子皆已搜尋，false 表示部分格子因上限略過
+}
+
+// AreaTiles 範圍搜尋的格子統計
+type AreaTiles struct {
+	Total    int `json:"total"`
+	Cached   int `json:"cached"`   // 使用緩存，不呼叫 Google API
+	Searched int `json:"searched"` // 本次呼叫 Google API
+	Skipped  int `json:"skipped"`  // 超過單次請求上限或額度用盡而略過
+}
+
+// CacheStatus 依格子的緩存比例返回緩存狀態
+func (t AreaTiles) CacheStatus() string {
+	switch {
+	case t.Cached > 0 && t.Searched == 0 && t.Skipped == 0:
+		return CacheStatusHit
+	case t.Cached > 0:
+		return CacheStatusPartial
+	default:
+		return CacheStatusMiss
+	}
+}
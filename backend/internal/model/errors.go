@@ -0,0 +1,97 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// 跨層共用的錯誤類別，repository、service 與 handler 皆以 errors.Is 判斷，不比對錯誤訊息
+var (
+	ErrQuotaExceeded       = errors.New("每日額度已用完")    // 本服務自己的每日額度
+	ErrUpstreamQuota       = errors.New("外部服務的額度已用完") // Google API 回報 OVER_QUERY_LIMIT
+	ErrUpstreamUnavailable = errors.New("外部服務暫時無法使用") // 外部服務錯誤或無法連線
+	ErrInvalidLocation     = errors.New("無效的位置")      // 座標超出範圍或無法解析的地點
+	ErrNoResults           = errors.New("沒有符合條件的結果")
+	ErrTimeout             = errors.New("請求逾時")
+	ErrBlockedAddress      = errors.New("不允許連線到內部網路位址") // webhook 目標解析到迴路、私有等內部位址
+
+	// 一般的請求錯誤，各 service 的 sentinel 錯誤以 Error 歸入其中一類，handler 只依類別決定狀態碼
+	ErrInvalidArgument = errors.New("無效的請求內容") // 參數或請求內容不符合規則
+	ErrUnauthorized    = errors.New("需要驗證身分")  // 缺少、無效或過期的身分
+	ErrForbidden       = errors.New("沒有權限")    // 身分有效但不能執行此操作
+	ErrNotFound        = errors.New("找不到資源")
+	ErrConflict        = errors.New("與目前狀態衝突") // 重複建立、已結束或尚未完成的流程
+	ErrGone            = errors.New("資源已失效")   // 曾經存在但已過期
+	ErrRateLimited     = errors.New("操作過於頻繁")
+	ErrNotEnabled      = errors.New("伺服器未啟用此功能")
+)
+
+// Error 屬於某個錯誤類別的錯誤
+// errors.Is 可比對錯誤本身（作為 sentinel 時）與所屬類別，Err 為原始錯誤
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// UpstreamError 外部服務（Google Maps 等）的錯誤，依原始錯誤歸類為額度用完、逾時或無法使用
+type UpstreamError struct {
+	Service string
+	Err     error
+}
+
+// NewUpstreamError 包裝外部服務的錯誤，err 為 nil 時返回 nil
+func NewUpstreamError(service string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &UpstreamError{Service: service, Err: err}
+}
+
+func (e *UpstreamError) Error() string {
+	// 請求網址含 API 金鑰，只保留錯誤原因，避免金鑰出現在日誌或回應中
+	var urlErr *url.Error
+	if errors.As(e.Err, &urlErr) {
+		return e.Service + " 錯誤: " + urlErr.Err.Error()
+	}
+	return e.Service + " 錯誤: " + e.Err.Error()
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UpstreamError) Is(target error) bool {
+	return target == e.kind()
+}
+
+func (e *UpstreamError) kind() error {
+	// Google Maps 客戶端只以訊息文字回報狀態，只在這裡比對一次
+	if strings.Contains(e.Err.Error(), "OVER_QUERY_LIMIT") {
+		return ErrUpstreamQuota
+	}
+
+	var netErr net.Error
+	if errors.Is(e.Err, context.DeadlineExceeded) || (errors.As(e.Err, &netErr) && netErr.Timeout()) {
+		return ErrTimeout
+	}
+	return ErrUpstreamUnavailable
+}
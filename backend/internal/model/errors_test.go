@@ -0,0 +1,77 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestUpstreamErrorKind(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"額度用完", errors.New("maps: OVER_QUERY_LIMIT - You have exceeded your daily request quota"), ErrUpstreamQuota},
+		{"context 逾時", fmt.Errorf("request failed: %w", context.DeadlineExceeded), ErrTimeout},
+		{"網路逾時", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, ErrTimeout},
+		{"拒絕請求", errors.New("maps: REQUEST_DENIED - The provided API key is invalid."), ErrUpstreamUnavailable},
+		{"無法連線", &net.DNSError{Err: "no such host", Name: "maps.googleapis.com"}, ErrUpstreamUnavailable},
+	}
+
+	kinds := []error{ErrUpstreamQuota, ErrTimeout, ErrUpstreamUnavailable, ErrQuotaExceeded, ErrInvalidLocation, ErrNoResults}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("搜尋餐廳失敗: %w", NewUpstreamError("Google Places API", tt.err))
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, kind, got)
+				}
+			}
+
+			// 原始錯誤仍可取出
+			if !errors.Is(err, tt.err) {
+				t.Errorf("errors.Is(%v, 原始錯誤) = false", err)
+			}
+			var upstream *UpstreamError
+			if !errors.As(err, &upstream) || upstream.Service != "Google Places API" {
+				t.Errorf("errors.As(%v, *UpstreamError) failed", err)
+			}
+		})
+	}
+
+	// 請求網址中的 API 金鑰不可出現在錯誤訊息中
+	urlErr := &url.Error{Op: "Get", URL: "https://maps.googleapis.com/maps/api/place/nearbysearch/json?key=secret", Err: &net.DNSError{Err: "no such host", Name: "maps.googleapis.com"}}
+	if message := NewUpstreamError("Google Places API", urlErr).Error(); strings.Contains(message, "secret") {
+		t.Errorf("error message leaks the API key: %s", message)
+	}
+
+	if NewUpstreamError("Google Places API", nil) != nil {
+		t.Error("NewUpstreamError(nil) should return nil")
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	sentinel := &Error{Kind: ErrQuotaExceeded, Message: "免費額度已到"}
+	wrapped := fmt.Errorf("API 每日請求數已達上限 600/600: %w", sentinel)
+
+	if !errors.Is(wrapped, sentinel) {
+		t.Error("wrapped error should match its sentinel")
+	}
+	if !errors.Is(wrapped, ErrQuotaExceeded) {
+		t.Error("wrapped error should match its kind")
+	}
+	if errors.Is(wrapped, ErrUpstreamQuota) {
+		t.Error("wrapped error should not match other kinds")
+	}
+
+	cause := errors.New("disk full")
+	withCause := &Error{Kind: ErrUpstreamUnavailable, Message: "無法連線", Err: cause}
+	if !errors.Is(withCause, cause) || withCause.Error() != "無法連線: disk full" {
+		t.Errorf("unexpected error chain: %v", withCause)
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Favorite 使用者收藏的餐廳
+type Favorite struct {
+	UserID     string     `json:"-"`
+	PlaceID    string     `json:"place_id"`
+	Restaurant Restaurant `json:"restaurant"` // 收藏當下的餐廳資料快照
+	Location   *Location  `json:"location,omitempty"`
+	Category   string     `json:"category"` // 餐廳類型，用於篩選
+	Note       string     `json:"note,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// 依距離排序時填入，不儲存
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+}
+
+// FavoriteFilter 查詢收藏的條件
+type FavoriteFilter struct {
+	Category string    // 依餐廳類型篩選
+	Tag      string    // 依使用者標籤篩選
+	Origin   *Location // 有提供時依距離由近到遠排序
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// 使用者回饋類型
+const (
+	FeedbackLike       = "like"
+	FeedbackDislike    = "dislike"
+	FeedbackNeverAgain = "never_again" // 不再推薦此餐廳
+)
+
+// Feedback 使用者對單一餐廳的回饋，同一家餐廳只保留最新一筆
+type Feedback struct {
+	UserID     string     `json:"-"`
+	PlaceID    string     `json:"place_id"`
+	Kind       string     `json:"kind"`
+	Restaurant Restaurant `json:"restaurant"` // 回饋當下的餐廳資料快照
+	Cuisine    string     `json:"cuisine"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// PreferenceProfile 由回饋累積出的使用者偏好，每次回饋時增量更新
+// 分數為各回饋權重的總和，正值表示喜歡、負值表示不喜歡
+type PreferenceProfile struct {
+	Cuisines    map[string]float64 `json:"cuisines"`     // 料理種類偏好
+	PriceLevels map[string]float64 `json:"price_levels"` // 價格等級偏好（"1"-"4"）
+	Likes       int                `json:"likes"`
+	Dislikes    int                `json:"dislikes"`
+	NeverAgain  int                `json:"never_again"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
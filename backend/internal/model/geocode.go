@@ -0,0 +1,18 @@
+package model
+
+// 地點解析的來源
+const (
+	GeocodeSourceGazetteer = "gazetteer" // 本地地名資料
+	GeocodeSourceGoogle    = "google"    // Google Geocoding API
+)
+
+// ResolvedLocation 由地址或地標文字解析出的位置
+type ResolvedLocation struct {
+	Query   string  `json:"query"`
+	Name    string  `json:"name,omitempty"`
+	Address string  `json:"address,omitempty"`
+	Lat     float64 `json:"lat"`
+	Lng     float64 `json:"lng"`
+	Source  string  `json:"source"`
+	Cached  bool    `json:"cached"`
+}
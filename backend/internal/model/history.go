@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// 最近吃過的候選餐廳處理方式
+const (
+	HistoryModeDownrank = "downrank"
+	HistoryModeExclude  = "exclude"
+)
+
+// MealRecord 使用者的用餐紀錄
+type MealRecord struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"-"`
+	PlaceID    string     `json:"place_id"`
+	Restaurant Restaurant `json:"restaurant"` // 用餐當下的餐廳資料快照
+	Cuisine    string     `json:"cuisine"`    // 推測的料理種類，如「拉麵」、「日式料理」
+	EatenAt    time.Time  `json:"eaten_at"`
+}
+
+// CuisineCount 料理種類的用餐次數
+type CuisineCount struct {
+	Cuisine string `json:"cuisine"`
+	Count   int    `json:"count"`
+}
+
+// WeeklySummary 每週用餐摘要
+type WeeklySummary struct {
+	WeekStart    string         `json:"week_start"` // 該週星期一，格式 2006-01-02
+	Meals        int            `json:"meals"`
+	UniquePlaces int            `json:"unique_places"`
+	Cuisines     []CuisineCount `json:"cuisines"`
+}
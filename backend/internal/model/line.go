@@ -0,0 +1,34 @@
+package model
+
+// LineWebhook LINE Messaging API 的 webhook 請求內容
+type LineWebhook struct {
+	Destination string      `json:"destination"`
+	Events      []LineEvent `json:"events"`
+}
+
+// LineEvent webhook 事件（只解析機器人需要的欄位）
+type LineEvent struct {
+	Type       string       `json:"type"` // message / follow / postback ...
+	ReplyToken string       `json:"replyToken"`
+	Source     LineSource   `json:"source"`
+	Message    *LineMessage `json:"message,omitempty"`
+}
+
+// LineSource 事件來源（使用者、群組或聊天室）
+type LineSource struct {
+	Type    string `json:"type"`
+	UserID  string `json:"userId"`
+	GroupID string `json:"groupId,omitempty"`
+	RoomID  string `json:"roomId,omitempty"`
+}
+
+// LineMessage 使用者傳送的訊息
+type LineMessage struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"` // text / location ...
+	Text      string  `json:"text,omitempty"`
+	Title     string  `json:"title,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
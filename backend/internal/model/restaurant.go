@@ -1,20 +1,173 @@
 package model
 
 type Location struct {
-	Lat float64 `json:"lat" binding:"required"`
-	Lng float64 `json:"lng" binding:"required"`
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
 }
 
 type Restaurant struct {
+	Name             string   `json:"name"`
+	Rating           float32  `json:"rating"`
+	UserRatingsTotal int      `json:"user_ratings_total"` // 評論總數
+	Distance         string   `json:"distance"`
+	DistanceMeters   float64  `json:"distance_meters"` // 直線距離（公尺），供排序與計分使用
+	Lat              float64  `json:"lat"`
+	Lng              float64  `json:"lng"`
+	PlaceID          string   `json:"place_id"`
+	Address          string   `json:"address"`
+	PhotoURL         string   `json:"photo_url,omitempty"`
+	PriceLevel       int      `json:"price_level"`   // Google Places API 價格等級 (0-4)
+	AveragePrice     string   `json:"average_price"` // 估計的平均消費金額
+	RestaurantType   string   `json:"restaurant_type,omitempty"`
+	Score            float64  `json:"score,omitempty"` // 加權推薦分數（僅 weighted 策略）
+	Reasons          []Reason `json:"reasons"`         // 推薦理由
+
+	// 實際路線的交通時間，只在請求指定 travel_mode 時提供
+	Travel *TravelTime `json:"travel,omitempty"`
+
+	// 團體推薦時各參與者到餐廳的距離
+	ParticipantDistances []ParticipantDistance `json:"participant_distances,omitempty"`
+}
+
+// ParticipantDistance 參與者到餐廳的距離
+type ParticipantDistance struct {
 	Name           string  `json:"name"`
-	Rating         float32 `json:"rating"`
 	Distance       string  `json:"distance"`
-	PlaceID        string  `json:"place_id"`
-	Address        string  `json:"address"`
-	PhotoURL       string  `json:"photo_url,omitempty"`
-	PriceLevel     int     `json:"price_level"`   // Google Places API 價格等級 (0-4)
-	AveragePrice   string  `json:"average_price"` // 估計的平均消費金額
-	RestaurantType string  `json:"restaurant_type,omitempty"`
+	DistanceMeters float64 `json:"distance_meters"`
+}
+
+// 推薦理由的產生階段
+const (
+	StagePrimarySearch = "primary_search" // 主要關鍵字 / 類型搜尋
+	StageNameSearch    = "name_search"    // 補充的名稱關鍵字搜尋
+	StageScoring       = "scoring"        // 加權計分
+	StageSelection     = "selection"      // 最終挑選
+	StageFilter        = "filter"         // 篩選條件
+	StageFavorites     = "favorites"      // 來自使用者收藏
+	StageHistory       = "history"        // 用餐紀錄
+	StagePreference    = "preference"     // 使用者回饋與偏好
+)
+
+// Reason 結構化的推薦理由，Message 依請求語系產生
+type Reason struct {
+	Code    string            `json:"code"`
+	Stage   string            `json:"stage"`
+	Params  map[string]string `json:"params,omitempty"`
+	Message string            `json:"message"`
+}
+
+// WithReason 附加推薦理由（複製切片，避免修改到緩存中共用的底層陣列）
+func (r Restaurant) WithReason(reason Reason) Restaurant {
+	reasons := make([]Reason, len(r.Reasons), len(r.Reasons)+1)
+	copy(reasons, r.Reasons)
+	r.Reasons = append(reasons, reason)
+	return r
+}
+
+// 推薦候選來源
+const (
+	SourceNearby    = "nearby"    // Google Places 附近搜尋
+	SourceFavorites = "favorites" // 只從使用者收藏挑選
+	SourceMixed     = "mixed"     // 依比例混合收藏與附近搜尋
+)
+
+// 推薦策略
+const (
+	StrategyRandom   = "random"   // 均勻隨機
+	StrategyWeighted = "weighted" // 依評價、距離、價格加權隨機
+)
+
+// RecommendQuery 推薦請求參數
+type RecommendQuery struct {
+	Lat            float64
+	Lng            float64
+	RestaurantType string
+	Strategy       string // random 或 weighted，空字串使用預設策略
+	Budget         int    // 期望價格等級 (0-4)，-1 表示不限
+	Count          int    // 推薦數量，0 使用預設值
+	Language       string // 推薦理由的語系 (zh-TW / en)，空字串使用預設語系
+
+	Source            string  // 候選來源 (nearby / favorites / mixed)，空字串為 nearby
+	UserID            string  // 使用者 ID，從收藏推薦時必填
+	MaxDistanceMeters float64 // 收藏餐廳的最大距離，0 使用預設值
+	TravelMode        string  // 交通時間的計算方式 (walking / driving / transit)，空字串不計算
+
+	ExcludePlaceIDs []string // 不列入候選的餐廳（重新推薦時排除上一批結果）
+}
+
+// 推薦流程的進度事件（串流推薦使用）
+const (
+	RecommendEventStarted    = "started"    // 開始搜尋候選餐廳
+	RecommendEventCandidates = "candidates" // 候選餐廳數量（已排除不再推薦的餐廳）
+	RecommendEventPick       = "pick"       // 一家推薦餐廳已完成
+)
+
+// RecommendEvent 推薦流程的進度，依 Type 使用對應的欄位
+type RecommendEvent struct {
+	Type        string
+	Source      string      // started：候選來源
+	CacheStatus string      // started：附近搜尋是否命中緩存，只從收藏推薦時為空字串
+	Candidates  int         // candidates：候選餐廳數量
+	Index       int         // pick：在推薦結果中的位置
+	Restaurant  *Restaurant // pick：完成的推薦餐廳
+}
+
+// RecommendResult 推薦結果
+type RecommendResult struct {
+	Restaurants []Restaurant  `json:"restaurants"`
+	Diversity   DiversityInfo `json:"diversity"`
+	Area        *Area         `json:"area,omitempty"` // 查詢位置所在的行政區
+
+	// 附近搜尋的緩存狀態與資料來源，放在 API v2 的 meta 中
+	CacheStatus string   `json:"-"`
+	Providers   []string `json:"-"`
+}
+
+// Merge 合併兩次挑選的多樣性資訊
+func (d DiversityInfo) Merge(other DiversityInfo) DiversityInfo {
+	merged := DiversityInfo{Relaxed: d.Relaxed || other.Relaxed}
+	seen := make(map[string]bool)
+	for _, constraint := range append(d.RelaxedConstraints, other.RelaxedConstraints...) {
+		if !seen[constraint] {
+			seen[constraint] = true
+			merged.RelaxedConstraints = append(merged.RelaxedConstraints, constraint)
+		}
+	}
+	return merged
+}
+
+// DiversityInfo 多樣性限制的套用情況
+type DiversityInfo struct {
+	Relaxed            bool     `json:"relaxed"`                       // 是否因候選餐廳不足而放寬限制
+	RelaxedConstraints []string `json:"relaxed_constraints,omitempty"` // 被放寬的限制 (cuisine / spacing / brand)
+}
+
+// 會面地點計算方式
+const (
+	MeetingMethodMidpoint = "midpoint" // 地理中點
+	MeetingMethodMinimax  = "minimax"  // 最小化最遠參與者的距離
+)
+
+// GroupParticipant 團體推薦的參與者位置
+type GroupParticipant struct {
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lng  float64 `json:"lng"`
+}
+
+// GroupRecommendQuery 多人會面地點推薦的請求參數
+type GroupRecommendQuery struct {
+	Participants []GroupParticipant
+	Method       string // midpoint 或 minimax
+	RecommendQuery
+}
+
+// GroupRecommendResult 多人會面地點推薦結果
+type GroupRecommendResult struct {
+	MeetingPoint      Location `json:"meeting_point"`
+	Method            string   `json:"method"`
+	MaxDistanceMeters float64  `json:"max_distance_meters"` // 會面地點到最遠參與者的距離
+	RecommendResult
 }
 
 type RecommendResponse struct {
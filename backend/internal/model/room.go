@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// 投票房間狀態
+const (
+	RoomStatusOpen   = "open"
+	RoomStatusClosed = "closed"
+)
+
+// Room 團體午餐投票房間
+type Room struct {
+	ID           string                  `json:"id"`
+	Query        RoomQuery               `json:"query"`
+	Candidates   []Restaurant            `json:"candidates"`
+	Participants map[string]*Participant `json:"participants"` // 以參與者 ID 為鍵
+	Quorum       int                     `json:"quorum"`       // 投票人數達此值即結束，0 表示只看截止時間
+	Status       string                  `json:"status"`
+	Winner       *Restaurant             `json:"winner,omitempty"`
+	CreatedBy    string                  `json:"created_by,omitempty"` // 建立者的使用者 ID，不出現在 RoomView
+	CreatedAt    time.Time               `json:"created_at"`
+	Deadline     time.Time               `json:"deadline"`
+	ClosedAt     time.Time               `json:"closed_at,omitempty"`
+	ExpiresAt    time.Time               `json:"expires_at"`
+}
+
+// RoomQuery 建立房間時使用的推薦條件
+type RoomQuery struct {
+	Lat            float64 `json:"lat"`
+	Lng            float64 `json:"lng"`
+	RestaurantType string  `json:"type,omitempty"`
+	Strategy       string  `json:"strategy,omitempty"`
+	Budget         int     `json:"budget"`
+}
+
+// Participant 房間參與者
+type Participant struct {
+	Name     string    `json:"name"`
+	Vote     string    `json:"vote,omitempty"`   // 投票的 place_id
+	Vetoes   []string  `json:"vetoes,omitempty"` // 否決的 place_id
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// RoomView 對外公開的房間狀態（不包含參與者 ID）
+type RoomView struct {
+	ID           string            `json:"id"`
+	Status       string            `json:"status"`
+	Candidates   []CandidateTally  `json:"candidates"`
+	Participants []ParticipantView `json:"participants"`
+	Quorum       int               `json:"quorum"`
+	Winner       *Restaurant       `json:"winner,omitempty"`
+	Deadline     time.Time         `json:"deadline"`
+	ExpiresAt    time.Time         `json:"expires_at"`
+}
+
+// CandidateTally 候選餐廳與目前票數
+type CandidateTally struct {
+	Restaurant Restaurant `json:"restaurant"`
+	Votes      int        `json:"votes"`
+	Vetoes     int        `json:"vetoes"`
+	Vetoed     bool       `json:"vetoed"`
+}
+
+// ParticipantView 對外公開的參與者資訊
+type ParticipantView struct {
+	Name  string `json:"name"`
+	Voted bool   `json:"voted"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// 排程推薦的發送目標類型
+const (
+	ScheduleTargetWebhook = "webhook" // 通用 JSON webhook
+	ScheduleTargetLine    = "line"    // LINE push message
+	ScheduleTargetSlack   = "slack"   // Slack incoming webhook
+)
+
+// 排程發送狀態
+const (
+	DeliveryStatusPending   = "pending"   // 發送中（含重試等待）
+	DeliveryStatusDelivered = "delivered" // 已送達
+	DeliveryStatusFailed    = "failed"    // 重試用盡仍失敗
+	DeliveryStatusSkipped   = "skipped"   // 假日、額度用盡或附近沒有餐廳
+)
+
+// Schedule 定期推送午餐推薦的排程
+type Schedule struct {
+	ID             string         `json:"id"`
+	UserID         string         `json:"-"`
+	Name           string         `json:"name"`
+	Cron           string         `json:"cron"`     // 五欄位 cron 表示式，如 "30 11 * * 1-5"
+	Timezone       string         `json:"timezone"` // IANA 時區，如 Asia/Taipei
+	Lat            float64        `json:"lat"`
+	Lng            float64        `json:"lng"`
+	RestaurantType string         `json:"type,omitempty"`
+	Budget         int            `json:"budget"` // 期望價格等級 (0-4)，-1 表示不限
+	Count          int            `json:"count"`
+	Target         ScheduleTarget `json:"target"`
+	Enabled        bool           `json:"enabled"`
+	CreatedAt      time.Time      `json:"created_at"`
+	NextRunAt      time.Time      `json:"next_run_at"`
+	LastRunAt      *time.Time     `json:"last_run_at,omitempty"`
+}
+
+// ScheduleTarget 推薦結果的發送目標
+type ScheduleTarget struct {
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"` // webhook 與 slack 使用
+	To   string `json:"to,omitempty"`  // LINE 使用者、群組或多人聊天 ID
+}
+
+// Delivery 一次排程執行的發送紀錄
+type Delivery struct {
+	ID           string     `json:"id"`
+	ScheduleID   string     `json:"schedule_id"`
+	ScheduledFor time.Time  `json:"scheduled_for"`
+	Status       string     `json:"status"`
+	Attempts     int        `json:"attempts"`
+	Error        string     `json:"error,omitempty"`
+	Restaurants  []string   `json:"restaurants,omitempty"` // 推薦的餐廳名稱
+	CreatedAt    time.Time  `json:"created_at"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty"`
+}
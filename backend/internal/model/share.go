@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Share 分享的推薦結果快照
+type Share struct {
+	ID          string       `json:"id"`
+	Restaurants []Restaurant `json:"restaurants"`
+	Query       ShareQuery   `json:"query"`
+	CreatedAt   time.Time    `json:"created_at"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+}
+
+// ShareQuery 產生推薦時的條件，座標已降低精度以保護分享者的位置
+type ShareQuery struct {
+	Lat            float64 `json:"lat"`
+	Lng            float64 `json:"lng"`
+	RestaurantType string  `json:"type,omitempty"`
+	Strategy       string  `json:"strategy,omitempty"`
+	Budget         int     `json:"budget"`
+	Source         string  `json:"source,omitempty"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// SlackCommand Slack slash command 的請求內容（application/x-www-form-urlencoded）
+type SlackCommand struct {
+	TeamID      string `form:"team_id"`
+	ChannelID   string `form:"channel_id"`
+	UserID      string `form:"user_id"`
+	UserName    string `form:"user_name"`
+	Command     string `form:"command"`
+	Text        string `form:"text"`
+	ResponseURL string `form:"response_url"`
+}
+
+// SlackInteraction Slack 互動元件（按鈕）的回呼內容，只解析需要的欄位
+type SlackInteraction struct {
+	Type string `json:"type"` // block_actions
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		TS string `json:"ts"`
+	} `json:"message"`
+	Actions     []SlackAction `json:"actions"`
+	ResponseURL string        `json:"response_url"`
+}
+
+// SlackAction 被點擊的按鈕
+type SlackAction struct {
+	ActionID string `json:"action_id"`
+	Value    string `json:"value"`
+}
+
+// SlackPoll Slack 頻道中的午餐投票，投票邏輯沿用投票房間
+type SlackPoll struct {
+	RoomID         string            `json:"room_id"`
+	ChannelID      string            `json:"channel_id"`
+	MessageTS      string            `json:"message_ts"`
+	CreatedBy      string            `json:"created_by"`
+	RestaurantType string            `json:"type,omitempty"`
+	Participants   map[string]string `json:"participants"` // Slack 使用者 ID → 房間參與者 ID
+	CreatedAt      time.Time         `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// 交通方式
+const (
+	TravelModeWalking = "walking" // 步行
+	TravelModeDriving = "driving" // 開車
+	TravelModeTransit = "transit" // 大眾運輸
+)
+
+// TravelTime 從查詢位置到餐廳的交通時間與路線距離
+type TravelTime struct {
+	Mode            string `json:"mode"`
+	DurationSeconds int    `json:"duration_seconds"`
+	DistanceMeters  int    `json:"distance_meters"`
+	Provider        string `json:"provider"`  // google 或 estimate
+	Estimated       bool   `json:"estimated"` // 是否為離線估算（未考慮實際路線）
+	Message         string `json:"message"`   // 依請求語系產生，如「步行 12 分鐘」
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// 權杖類型
+const (
+	TokenTypeDevice    = "device"     // 匿名裝置權杖
+	TokenTypeAccount   = "account"    // 已綁定帳號的權杖
+	TokenTypeMagicLink = "magic_link" // Email 登入連結
+	TokenTypeOAuth     = "oauth"      // OAuth state 參數
+)
+
+// TokenClaims 簽章權杖的內容
+type TokenClaims struct {
+	Subject   string `json:"sub"`             // 使用者 ID
+	Type      string `json:"typ"`             // 權杖類型
+	Email     string `json:"email,omitempty"` // Email 登入連結的目標信箱
+	Nonce     string `json:"nonce,omitempty"` // 帳號綁定請求 ID，連結與 state 只能使用一次
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"` // 0 表示不會過期
+}
+
+// Account 已綁定的使用者帳號
+type Account struct {
+	ID            string    `json:"id"`
+	Email         string    `json:"email,omitempty"`
+	Provider      string    `json:"provider"` // email 或 OAuth 提供者名稱
+	Subject       string    `json:"subject"`  // 提供者中的使用者識別碼
+	LinkedDevices []string  `json:"linked_devices,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ExternalIdentity 外部身分提供者驗證後的使用者資訊
+type ExternalIdentity struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	Email    string `json:"email,omitempty"`
+}
+
+// LinkRequest 帳號綁定請求
+// Email 連結或 OAuth 回呼只會完成身分驗證，實際綁定需由發起請求的裝置確認
+type LinkRequest struct {
+	ID        string            `json:"id"`
+	UserID    string            `json:"user_id"`            // 發起綁定的使用者
+	Identity  *ExternalIdentity `json:"identity,omitempty"` // 驗證完成後的外部身分，nil 表示尚未驗證
+	ExpiresAt time.Time         `json:"expires_at"`
+}
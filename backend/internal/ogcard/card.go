@@ -0,0 +1,268 @@
+package ogcard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"what2eat-backend/internal/model"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Open Graph 建議的預覽圖尺寸（1.91:1）
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	// 卡片上最多列出的餐廳數量，其餘以「還有 N 家」表示
+	maxCardRows = 4
+	// 頁首高度
+	headerHeight = 120
+	// 餐廳列的位置與大小
+	rowTop    = 150
+	rowHeight = 88
+	rowGap    = 14
+	rowLeft   = 60
+	rowRight  = Width - 60
+)
+
+// 與前端主題一致的配色
+var (
+	colorPrimary    = color.RGBA{0xff, 0x6b, 0x35, 0xff}
+	colorText       = color.RGBA{0x1a, 0x1a, 0x1a, 0xff}
+	colorMuted      = color.RGBA{0x75, 0x75, 0x75, 0xff}
+	colorFaint      = color.RGBA{0xcc, 0xcc, 0xcc, 0xff}
+	colorBackground = color.RGBA{0xf8, 0xf9, 0xfa, 0xff}
+	colorCard       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorBorder     = color.RGBA{0xe8, 0xe8, 0xe8, 0xff}
+)
+
+// Renderer 以內嵌字型繪製分享預覽卡片
+type Renderer struct {
+	font *opentype.Font
+}
+
+func NewRenderer() (*Renderer, error) {
+	f, err := loadFont()
+	if err != nil {
+		return nil, err
+	}
+	return &Renderer{font: f}, nil
+}
+
+// 單次繪製使用的字型
+type faces struct {
+	brand, title, name, rating, small font.Face
+}
+
+func (r *Renderer) newFaces() (*faces, error) {
+	sizes := []float64{44, 34, 34, 30, 22}
+	created := make([]font.Face, 0, len(sizes))
+	for _, size := range sizes {
+		face, err := newFace(r.font, size)
+		if err != nil {
+			for _, f := range created {
+				f.Close()
+			}
+			return nil, fmt.Errorf("無法建立字型: %w", err)
+		}
+		created = append(created, face)
+	}
+	return &faces{brand: created[0], title: created[1], name: created[2], rating: created[3], small: created[4]}, nil
+}
+
+func (f *faces) Close() {
+	for _, face := range []font.Face{f.brand, f.title, f.name, f.rating, f.small} {
+		face.Close()
+	}
+}
+
+// Render 將分享的推薦結果繪製成 PNG
+func (r *Renderer) Render(share model.Share) ([]byte, error) {
+	fs, err := r.newFaces()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colorBackground), image.Point{}, draw.Src)
+
+	r.drawHeader(img, fs, share)
+
+	rows := share.Restaurants
+	if len(rows) > maxCardRows {
+		rows = rows[:maxCardRows]
+	}
+	for i, restaurant := range rows {
+		top := rowTop + i*(rowHeight+rowGap)
+		drawRow(img, fs, i+1, restaurant, top)
+	}
+
+	// 頁尾：其餘餐廳數量與分享日期
+	footerBaseline := Height - 28
+	if more := len(share.Restaurants) - len(rows); more > 0 {
+		drawText(img, fs.small, fmt.Sprintf("還有 %d 家推薦，點開連結查看", more), rowLeft, footerBaseline, colorMuted)
+	}
+	stamp := "what2eat · " + share.CreatedAt.Format("2006/01/02")
+	drawText(img, fs.small, stamp, rowRight-measure(fs.small, stamp), footerBaseline, colorMuted)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("無法輸出 PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// 頁首：主色色帶、標誌與標題
+func (r *Renderer) drawHeader(img *image.RGBA, fs *faces, share model.Share) {
+	fillRect(img, image.Rect(0, 0, Width, headerHeight), colorPrimary)
+	drawLogo(img, 80, headerHeight/2)
+	drawText(img, fs.brand, "What2Eat", 134, headerHeight/2+16, colorCard)
+
+	title := "今天吃什麼？"
+	if share.Query.RestaurantType != "" {
+		title = "今天吃" + share.Query.RestaurantType + "？"
+	}
+	title = truncate(fs.title, title, 600)
+	drawText(img, fs.title, title, rowRight-measure(fs.title, title), headerHeight/2+12, colorCard)
+}
+
+// 標誌：白色圓形底上的餐盤與刀叉，與前端的 RestaurantMenu 圖示相呼應
+func drawLogo(img *image.RGBA, cx, cy int) {
+	fillCircle(img, float32(cx), float32(cy), 38, colorCard)
+	fillCircle(img, float32(cx), float32(cy), 21, colorPrimary)
+	fillCircle(img, float32(cx), float32(cy), 15, colorCard)
+
+	// 叉子：三根叉齒與握柄
+	for _, dx := range []int{-31, -27, -23} {
+		fillRoundRect(img, image.Rect(cx+dx-1, cy-22, cx+dx+1, cy-8), 1, colorPrimary)
+	}
+	fillRoundRect(img, image.Rect(cx-32, cy-10, cx-22, cy-5), 2, colorPrimary)
+	fillRoundRect(img, image.Rect(cx-29, cy-8, cx-25, cy+24), 2, colorPrimary)
+
+	// 刀子：刀刃較寬、握柄較窄
+	fillRoundRect(img, image.Rect(cx+23, cy-22, cx+31, cy+2), 3, colorPrimary)
+	fillRoundRect(img, image.Rect(cx+25, cy, cx+29, cy+24), 2, colorPrimary)
+}
+
+// 餐廳列：序號、名稱、評分與價格
+func drawRow(img *image.RGBA, fs *faces, index int, restaurant model.Restaurant, top int) {
+	bounds := image.Rect(rowLeft, top, rowRight, top+rowHeight)
+	fillRoundRect(img, bounds.Inset(-1), 19, colorBorder)
+	fillRoundRect(img, bounds, 18, colorCard)
+
+	centerY := top + rowHeight/2
+	baseline := centerY + 12
+
+	fillCircle(img, float32(rowLeft+48), float32(centerY), 24, colorPrimary)
+	number := strconv.Itoa(index)
+	drawText(img, fs.rating, number, rowLeft+48-measure(fs.rating, number)/2, centerY+11, colorCard)
+
+	name := truncate(fs.name, restaurant.Name, 600)
+	drawText(img, fs.name, name, rowLeft+96, baseline, colorText)
+
+	// 評分與評論數
+	x := 780
+	if restaurant.Rating > 0 {
+		rating := fmt.Sprintf("★ %.1f", restaurant.Rating)
+		drawText(img, fs.rating, rating, x, baseline, colorPrimary)
+		x += measure(fs.rating, rating) + 10
+		if restaurant.UserRatingsTotal > 0 {
+			drawText(img, fs.small, "("+formatThousands(restaurant.UserRatingsTotal)+")", x, baseline, colorMuted)
+		}
+	} else {
+		drawText(img, fs.small, "尚無評分", x, baseline, colorMuted)
+	}
+
+	// 價格等級：以 4 個 $ 表示，未達的等級以淡色顯示
+	if restaurant.PriceLevel > 0 {
+		level := min(restaurant.PriceLevel, 4)
+		x = rowRight - 28 - measure(fs.rating, "$$$$")
+		drawText(img, fs.rating, strings.Repeat("$", level), x, baseline, colorText)
+		if level < 4 {
+			drawText(img, fs.rating, strings.Repeat("$", 4-level), x+measure(fs.rating, strings.Repeat("$", level)), baseline, colorFaint)
+		}
+	}
+}
+
+func drawText(img *image.RGBA, face font.Face, text string, x, baseline int, c color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, baseline),
+	}
+	d.DrawString(text)
+}
+
+func measure(face font.Face, text string) int {
+	return font.MeasureString(face, text).Ceil()
+}
+
+// 超過寬度時截斷並加上省略號
+func truncate(face font.Face, text string, maxWidth int) string {
+	if measure(face, text) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if measure(face, candidate) <= maxWidth {
+			return candidate
+		}
+	}
+	return "…"
+}
+
+// 加上千分位，如 1234 → 1,234
+func formatThousands(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+// 以向量光柵化繪製圓角矩形，邊緣有抗鋸齒
+func fillRoundRect(img *image.RGBA, rect image.Rectangle, radius float32, c color.Color) {
+	x0, y0 := float32(rect.Min.X), float32(rect.Min.Y)
+	x1, y1 := float32(rect.Max.X), float32(rect.Max.Y)
+	radius = min(radius, (x1-x0)/2, (y1-y0)/2)
+	// 以三次貝茲曲線近似四分之一圓的控制點比例
+	k := radius * 0.5523
+
+	z := vector.NewRasterizer(Width, Height)
+	z.MoveTo(x0+radius, y0)
+	z.LineTo(x1-radius, y0)
+	z.CubeTo(x1-radius+k, y0, x1, y0+radius-k, x1, y0+radius)
+	z.LineTo(x1, y1-radius)
+	z.CubeTo(x1, y1-radius+k, x1-radius+k, y1, x1-radius, y1)
+	z.LineTo(x0+radius, y1)
+	z.CubeTo(x0+radius-k, y1, x0, y1-radius+k, x0, y1-radius)
+	z.LineTo(x0, y0+radius)
+	z.CubeTo(x0, y0+radius-k, x0+radius-k, y0, x0+radius, y0)
+	z.ClosePath()
+	z.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{})
+}
+
+func fillCircle(img *image.RGBA, cx, cy, radius float32, c color.Color) {
+	d := int(math.Round(float64(radius)))
+	fillRoundRect(img, image.Rect(int(cx)-d, int(cy)-d, int(cx)+d, int(cy)+d), radius, c)
+}
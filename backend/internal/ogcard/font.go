@@ -0,0 +1,42 @@
+package ogcard
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// 內嵌 Noto Sans CJK TC Bold 的子集（常用繁體中文、日文假名與拉丁字母），
+// 來源版本與產生方式見 fonts/README.md，授權見 fonts/LICENSE
+//
+//go:embed fonts/NotoSansTC-Bold-Subset.ttf
+var fontData []byte
+
+var (
+	parsedFont *opentype.Font
+	parseErr   error
+	parseOnce  sync.Once
+)
+
+// 字型只解析一次，所有 Renderer 共用
+func loadFont() (*opentype.Font, error) {
+	parseOnce.Do(func() {
+		parsedFont, parseErr = opentype.Parse(fontData)
+		if parseErr != nil {
+			parseErr = fmt.Errorf("無法解析內嵌字型: %w", parseErr)
+		}
+	})
+	return parsedFont, parseErr
+}
+
+// 建立指定大小的字型，font.Face 不可並行使用，每次繪製都需建立新的
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
@@ -0,0 +1,121 @@
+package ogcard
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// Page 分享頁面所需的網址，皆為絕對網址（預覽爬蟲不會解析相對路徑）
+type Page struct {
+	Share    model.Share
+	PageURL  string // 分享頁面本身
+	ImageURL string // 預覽卡片圖片
+	AppURL   string // 在前端開啟分享的網址
+}
+
+type pageRestaurant struct {
+	Name    string
+	Rating  string
+	Price   string
+	MapsURL string
+}
+
+// 爬蟲只讀取 <head> 的 meta 標籤，<body> 提供給直接開啟連結的使用者
+var pageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="zh-Hant">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<meta name="description" content="{{.Description}}">
+<meta property="og:type" content="website">
+<meta property="og:site_name" content="What2Eat">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.PageURL}}">
+<meta property="og:image" content="{{.ImageURL}}">
+<meta property="og:image:type" content="image/png">
+<meta property="og:image:width" content="{{.Width}}">
+<meta property="og:image:height" content="{{.Height}}">
+<meta property="og:image:alt" content="{{.Description}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:title" content="{{.Title}}">
+<meta name="twitter:description" content="{{.Description}}">
+<meta name="twitter:image" content="{{.ImageURL}}">
+<style>
+body{margin:0;font-family:-apple-system,"Noto Sans TC","PingFang TC",sans-serif;background:#f8f9fa;color:#1a1a1a}
+main{max-width:640px;margin:0 auto;padding:24px 16px}
+h1{color:#ff6b35;font-size:24px}
+img{width:100%;border-radius:12px}
+ol{padding-left:24px}
+li{margin:12px 0}
+a{color:#e85a2a}
+.meta{color:#757575;font-size:14px}
+.open{display:inline-block;margin-top:16px;padding:12px 24px;border-radius:24px;background:#ff6b35;color:#fff;text-decoration:none}
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<img src="{{.ImageURL}}" alt="{{.Description}}" width="{{.Width}}" height="{{.Height}}">
+<ol>
+{{- range .Restaurants}}
+<li><a href="{{.MapsURL}}" rel="noopener">{{.Name}}</a>{{if .Rating}} <span class="meta">★ {{.Rating}}</span>{{end}}{{if .Price}} <span class="meta">{{.Price}}</span>{{end}}</li>
+{{- end}}
+</ol>
+<a class="open" href="{{.AppURL}}">在 What2Eat 開啟</a>
+</main>
+</body>
+</html>
+`))
+
+// RenderPage 產生含 Open Graph 與 Twitter Card 標籤的分享頁面
+func RenderPage(page Page) ([]byte, error) {
+	restaurants := make([]pageRestaurant, 0, len(page.Share.Restaurants))
+	names := make([]string, 0, maxCardRows)
+	for i, r := range page.Share.Restaurants {
+		item := pageRestaurant{
+			Name:    r.Name,
+			MapsURL: repository.GoogleMapsURL(r.Name, r.PlaceID),
+		}
+		if r.Rating > 0 {
+			item.Rating = fmt.Sprintf("%.1f", r.Rating)
+		}
+		if r.PriceLevel > 0 {
+			item.Price = strings.Repeat("$", min(r.PriceLevel, 4))
+		}
+		restaurants = append(restaurants, item)
+		if i < maxCardRows {
+			names = append(names, r.Name)
+		}
+	}
+
+	title := "What2Eat｜今天吃什麼？"
+	if page.Share.Query.RestaurantType != "" {
+		title = "What2Eat｜今天吃" + page.Share.Query.RestaurantType + "？"
+	}
+	description := fmt.Sprintf("推薦 %d 家餐廳：%s", len(page.Share.Restaurants), strings.Join(names, "、"))
+	if len(page.Share.Restaurants) > len(names) {
+		description += "…"
+	}
+
+	var buf bytes.Buffer
+	err := pageTemplate.Execute(&buf, map[string]any{
+		"Title":       title,
+		"Description": description,
+		"PageURL":     page.PageURL,
+		"ImageURL":    page.ImageURL,
+		"AppURL":      page.AppURL,
+		"Width":       Width,
+		"Height":      Height,
+		"Restaurants": restaurants,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("無法產生分享頁面: %w", err)
+	}
+	return buf.Bytes(), nil
+}
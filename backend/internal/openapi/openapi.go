@@ -0,0 +1,26 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// API 規格文件，新增或修改路由與回應時需同步更新
+//
+//go:embed openapi.yaml
+var specYAML []byte
+
+// Load 載入並驗證內建的 OpenAPI 文件
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("無法解析 OpenAPI 文件: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("OpenAPI 文件格式錯誤: %w", err)
+	}
+	return doc, nil
+}
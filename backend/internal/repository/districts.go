@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"what2eat-backend/internal/model"
+)
+
+// 內建的行政區邊界：只涵蓋臺北市 12 區，為手繪的近似邊界而非官方資料，僅適合作為顯示用的區域標籤，
+// 其他縣市查無區域；需要精確或全臺的邊界時，以 REVERSE_GEOCODE_DATASET 指定內政部鄉鎮市區界線轉成的 GeoJSON
+//
+//go:embed districts.geojson
+var defaultDistricts []byte
+
+// 經緯度座標 [lng, lat]，與 GeoJSON 相同
+type point [2]float64
+
+// 多邊形：第一個環為外圈，其餘為內圈（挖空）
+type polygon []([]point)
+
+type district struct {
+	area     model.Area
+	polygons []polygon
+	// 外框，快速排除不可能的行政區
+	minLng, minLat, maxLng, maxLat float64
+}
+
+// DistrictIndex 以行政區多邊形離線判斷座標所在的區域，不需要網路也不計入 API 額度
+type DistrictIndex struct {
+	districts []district
+}
+
+type geoJSONCollection struct {
+	Features []struct {
+		Properties struct {
+			District string `json:"district"`
+			City     string `json:"city"`
+			Country  string `json:"country"`
+		} `json:"properties"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// NewDistrictIndex 載入行政區邊界，path 為空時使用內建資料
+// 檔案需為 GeoJSON FeatureCollection，properties 含 district、city、country，幾何為 Polygon 或 MultiPolygon
+func NewDistrictIndex(path string) (*DistrictIndex, error) {
+	data := defaultDistricts
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("無法讀取行政區資料 %s: %w", path, err)
+		}
+	}
+
+	var collection geoJSONCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("無法解析行政區資料: %w", err)
+	}
+
+	index := &DistrictIndex{districts: make([]district, 0, len(collection.Features))}
+	for _, feature := range collection.Features {
+		var polygons []polygon
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var p polygon
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &p); err != nil {
+				return nil, fmt.Errorf("無效的行政區邊界 %s: %w", feature.Properties.District, err)
+			}
+			polygons = []polygon{p}
+		case "MultiPolygon":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygons); err != nil {
+				return nil, fmt.Errorf("無效的行政區邊界 %s: %w", feature.Properties.District, err)
+			}
+		default:
+			continue
+		}
+
+		d := district{
+			area: model.Area{
+				District: feature.Properties.District,
+				City:     feature.Properties.City,
+				Country:  feature.Properties.Country,
+				Source:   model.GeocodeSourceGazetteer,
+			},
+			polygons: polygons,
+		}
+		d.computeBounds()
+		index.districts = append(index.districts, d)
+	}
+
+	fmt.Printf("已載入 %d 個行政區邊界\n", len(index.districts))
+	return index, nil
+}
+
+func (d *district) computeBounds() {
+	first := true
+	for _, p := range d.polygons {
+		if len(p) == 0 {
+			continue
+		}
+		for _, pt := range p[0] {
+			if first {
+				d.minLng, d.maxLng, d.minLat, d.maxLat = pt[0], pt[0], pt[1], pt[1]
+				first = false
+				continue
+			}
+			d.minLng, d.maxLng = min(d.minLng, pt[0]), max(d.maxLng, pt[0])
+			d.minLat, d.maxLat = min(d.minLat, pt[1]), max(d.maxLat, pt[1])
+		}
+	}
+}
+
+func (d *district) contains(lat, lng float64) bool {
+	if lng < d.minLng || lng > d.maxLng || lat < d.minLat || lat > d.maxLat {
+		return false
+	}
+	for _, p := range d.polygons {
+		if len(p) == 0 || !ringContains(p[0], lat, lng) {
+			continue
+		}
+		inHole := false
+		for _, hole := range p[1:] {
+			if ringContains(hole, lat, lng) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// 射線法判斷點是否在環內
+func ringContains(ring []point, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (i *DistrictIndex) Name() string {
+	return model.GeocodeSourceGazetteer
+}
+
+// ReverseGeocode 返回座標所在的行政區，不在任何行政區內時返回 nil
+func (i *DistrictIndex) ReverseGeocode(ctx context.Context, lat, lng float64) (*model.Area, error) {
+	for _, d := range i.districts {
+		if d.contains(lat, lng) {
+			area := d.area
+			return &area, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultDistrictLookup(t *testing.T) {
+	index, err := NewDistrictIndex("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 內建邊界為手繪近似，只檢查離區界有一段距離的地標
+	tests := []struct {
+		name     string
+		lat, lng float64
+		district string
+	}{
+		{"臺北車站", 25.0478, 121.5170, "中正區"},
+		{"中正紀念堂", 25.0347, 121.5218, "中正區"},
+		{"迪化街", 25.0558, 121.5100, "大同區"},
+		{"行天宮", 25.0630, 121.5336, "中山區"},
+		{"龍山寺", 25.0372, 121.4999, "萬華區"},
+		{"大安森林公園", 25.0300, 121.5358, "大安區"},
+		{"台北 101", 25.0340, 121.5645, "信義區"},
+		{"松山機場", 25.0697, 121.5522, "松山區"},
+		{"內湖科學園區", 25.0800, 121.5750, "內湖區"},
+		{"南港展覽館", 25.0560, 121.6171, "南港區"},
+		{"政治大學", 24.9868, 121.5760, "文山區"},
+		{"士林官邸", 25.0930, 121.5310, "士林區"},
+		{"北投溫泉博物館", 25.1366, 121.5069, "北投區"},
+		{"板橋車站（新北市）", 25.0143, 121.4633, ""},
+		{"臺中車站", 24.1372, 120.6867, ""},
+		{"原點", 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			area, err := index.ReverseGeocode(context.Background(), tt.lat, tt.lng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if area != nil {
+				got = area.District
+				if area.City != "臺北市" || area.Country != "臺灣" {
+					t.Errorf("area = %+v, want 臺北市 臺灣", area)
+				}
+			}
+			if got != tt.district {
+				t.Errorf("ReverseGeocode(%v, %v) = %q, want %q", tt.lat, tt.lng, got, tt.district)
+			}
+		})
+	}
+}
+
+func TestCustomDistrictDataset(t *testing.T) {
+	// 外圈 0-10 的正方形挖去 4-6 的中心，另一個多邊形為 20-30 的正方形
+	dataset := `{"type": "FeatureCollection", "features": [
+		{"properties": {"district": "甲區", "city": "測試市", "country": "測試國"},
+		 "geometry": {"type": "MultiPolygon", "coordinates": [
+			[[[0,0],[10,0],[10,10],[0,10],[0,0]], [[4,4],[6,4],[6,6],[4,6],[4,4]]],
+			[[[20,20],[30,20],[30,30],[20,30],[20,20]]]
+		 ]}},
+		{"properties": {"district": "乙區", "city": "測試市", "country": "測試國"},
+		 "geometry": {"type": "Polygon", "coordinates": [[[4,4],[6,4],[6,6],[4,6],[4,4]]]}},
+		{"properties": {"district": "略過"}, "geometry": {"type": "Point", "coordinates": [1, 1]}}
+	]}`
+	path := filepath.Join(t.TempDir(), "districts.geojson")
+	if err := os.WriteFile(path, []byte(dataset), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := NewDistrictIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		lat, lng float64
+		district string
+	}{
+		{"外圈內", 2, 2, "甲區"},
+		{"挖空處屬於另一區", 5, 5, "乙區"},
+		{"第二個多邊形", 25, 25, "甲區"},
+		{"多邊形之間", 15, 15, ""},
+		{"外框外", -1, 5, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			area, err := index.ReverseGeocode(context.Background(), tt.lat, tt.lng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if area != nil {
+				got = area.District
+			}
+			if got != tt.district {
+				t.Errorf("ReverseGeocode(%v, %v) = %q, want %q", tt.lat, tt.lng, got, tt.district)
+			}
+		})
+	}
+
+	if _, err := NewDistrictIndex(filepath.Join(t.TempDir(), "missing.geojson")); err == nil {
+		t.Error("missing dataset: err = nil")
+	}
+}
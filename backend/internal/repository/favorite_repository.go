@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"what2eat-backend/internal/model"
+)
+
+const favoriteKeyPrefix = "favorite:"
+
+// FavoriteRepository 存取使用者收藏，底層儲存可替換（記憶體 / 本地檔案）
+type FavoriteRepository struct {
+	store Store
+}
+
+func NewFavoriteRepository(store Store) *FavoriteRepository {
+	return &FavoriteRepository{store: store}
+}
+
+func favoriteKey(userID, placeID string) string {
+	return fmt.Sprintf("%s%s:%s", favoriteKeyPrefix, userID, placeID)
+}
+
+// List 取得使用者的所有收藏
+func (r *FavoriteRepository) List(userID string) ([]model.Favorite, error) {
+	keys, err := r.store.Keys(favoriteKeyPrefix + userID + ":")
+	if err != nil {
+		return nil, err
+	}
+
+	favorites := make([]model.Favorite, 0, len(keys))
+	for _, key := range keys {
+		var favorite model.Favorite
+		found, err := r.store.Get(key, &favorite)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			favorite.UserID = userID
+			favorites = append(favorites, favorite)
+		}
+	}
+	return favorites, nil
+}
+
+// Get 取得單一收藏，不存在時返回 nil
+func (r *FavoriteRepository) Get(userID, placeID string) (*model.Favorite, error) {
+	var favorite model.Favorite
+	found, err := r.store.Get(favoriteKey(userID, placeID), &favorite)
+	if err != nil || !found {
+		return nil, err
+	}
+	favorite.UserID = userID
+	return &favorite, nil
+}
+
+// Save 新增或更新收藏
+func (r *FavoriteRepository) Save(favorite model.Favorite) error {
+	favorite.DistanceMeters = nil
+	return r.store.Put(favoriteKey(favorite.UserID, favorite.PlaceID), favorite)
+}
+
+// Delete 刪除收藏
+func (r *FavoriteRepository) Delete(userID, placeID string) error {
+	return r.store.Delete(favoriteKey(userID, placeID))
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"fmt"
+	"what2eat-backend/internal/model"
+)
+
+const (
+	feedbackKeyPrefix = "feedback:"
+	profileKeyPrefix  = "profile:"
+)
+
+// FeedbackRepository 存取使用者回饋與偏好檔案，底層儲存可替換（記憶體 / 本地檔案）
+type FeedbackRepository struct {
+	store Store
+}
+
+func NewFeedbackRepository(store Store) *FeedbackRepository {
+	return &FeedbackRepository{store: store}
+}
+
+func feedbackKey(userID, placeID string) string {
+	return fmt.Sprintf("%s%s:%s", feedbackKeyPrefix, userID, placeID)
+}
+
+// List 取得使用者的所有回饋
+func (r *FeedbackRepository) List(userID string) ([]model.Feedback, error) {
+	keys, err := r.store.Keys(feedbackKeyPrefix + userID + ":")
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := make([]model.Feedback, 0, len(keys))
+	for _, key := range keys {
+		var item model.Feedback
+		found, err := r.store.Get(key, &item)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			item.UserID = userID
+			feedback = append(feedback, item)
+		}
+	}
+	return feedback, nil
+}
+
+// Get 取得使用者對單一餐廳的回饋，不存在時返回 nil
+func (r *FeedbackRepository) Get(userID, placeID string) (*model.Feedback, error) {
+	var item model.Feedback
+	found, err := r.store.Get(feedbackKey(userID, placeID), &item)
+	if err != nil || !found {
+		return nil, err
+	}
+	item.UserID = userID
+	return &item, nil
+}
+
+// Save 新增或更新回饋
+func (r *FeedbackRepository) Save(item model.Feedback) error {
+	return r.store.Put(feedbackKey(item.UserID, item.PlaceID), item)
+}
+
+// Delete 刪除回饋
+func (r *FeedbackRepository) Delete(userID, placeID string) error {
+	return r.store.Delete(feedbackKey(userID, placeID))
+}
+
+// GetProfile 取得偏好檔案，不存在時返回 nil
+func (r *FeedbackRepository) GetProfile(userID string) (*model.PreferenceProfile, error) {
+	var profile model.PreferenceProfile
+	found, err := r.store.Get(profileKeyPrefix+userID, &profile)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveProfile 保存偏好檔案
+func (r *FeedbackRepository) SaveProfile(userID string, profile model.PreferenceProfile) error {
+	return r.store.Put(profileKeyPrefix+userID, profile)
+}
+
+// DeleteProfile 刪除偏好檔案
+func (r *FeedbackRepository) DeleteProfile(userID string) error {
+	return r.store.Delete(profileKeyPrefix + userID)
+}
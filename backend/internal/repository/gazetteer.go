@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"what2eat-backend/internal/model"
+)
+
+// 內建的常用地標，可再以 GEOCODE_GAZETTEER_FILE 指定的檔案補充或覆寫
+//
+//go:embed gazetteer.json
+var defaultGazetteer []byte
+
+// GazetteerPlace 地名資料的一筆地點
+type GazetteerPlace struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Address string   `json:"address,omitempty"`
+	Lat     float64  `json:"lat"`
+	Lng     float64  `json:"lng"`
+}
+
+// Gazetteer 以本地地名資料解析地標，不需要網路也不計入 API 額度
+type Gazetteer struct {
+	places map[string]GazetteerPlace // 正規化後的名稱或別名 → 地點
+}
+
+// NewGazetteer 載入內建地名資料，path 不為空時再載入該檔案（同名地點以檔案為準）
+func NewGazetteer(path string) (*Gazetteer, error) {
+	g := &Gazetteer{places: make(map[string]GazetteerPlace)}
+	if err := g.load(defaultGazetteer); err != nil {
+		return nil, fmt.Errorf("無法解析內建地名資料: %w", err)
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("無法讀取地名資料 %s: %w", path, err)
+		}
+		if err := g.load(data); err != nil {
+			return nil, fmt.Errorf("無法解析地名資料 %s: %w", path, err)
+		}
+	}
+	return g, nil
+}
+
+func (g *Gazetteer) load(data []byte) error {
+	var places []GazetteerPlace
+	if err := json.Unmarshal(data, &places); err != nil {
+		return err
+	}
+	for _, place := range places {
+		if place.Name == "" || place.Lat < -90 || place.Lat > 90 || place.Lng < -180 || place.Lng > 180 {
+			return fmt.Errorf("無效的地點 %q", place.Name)
+		}
+		for _, name := range append([]string{place.Name}, place.Aliases...) {
+			g.places[NormalizePlaceName(name)] = place
+		}
+	}
+	return nil
+}
+
+func (g *Gazetteer) Name() string {
+	return model.GeocodeSourceGazetteer
+}
+
+// Geocode 以名稱或別名完全比對，查無結果時返回 nil
+func (g *Gazetteer) Geocode(ctx context.Context, query string) (*model.ResolvedLocation, error) {
+	place, found := g.places[NormalizePlaceName(query)]
+	if !found {
+		return nil, nil
+	}
+	return &model.ResolvedLocation{
+		Query:   query,
+		Name:    place.Name,
+		Address: place.Address,
+		Lat:     place.Lat,
+		Lng:     place.Lng,
+		Source:  g.Name(),
+	}, nil
+}
+
+// NormalizePlaceName 統一大小寫、空白與「臺／台」，讓「臺北 101」與「台北101」視為相同
+func NormalizePlaceName(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), ""))
+	return strings.ReplaceAll(name, "臺", "台")
+}
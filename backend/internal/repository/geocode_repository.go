@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+	"what2eat-backend/internal/model"
+)
+
+const (
+	geocodeKeyPrefix = "geocode:"
+	areaKeyPrefix    = "area:"
+)
+
+// 快取的反向解析結果，Area 為 nil 表示不在任何已知的行政區內
+type areaEntry struct {
+	Area      *model.Area `json:"area"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// 快取的解析結果，Location 為 nil 表示查無此地點
+type geocodeEntry struct {
+	Location  *model.ResolvedLocation `json:"location"`
+	ExpiresAt time.Time               `json:"expires_at"`
+}
+
+// GeocodeRepository 快取地點解析與反向解析結果，底層儲存可替換（記憶體 / 本地檔案）
+type GeocodeRepository struct {
+	store Store
+}
+
+func NewGeocodeRepository(store Store) *GeocodeRepository {
+	return &GeocodeRepository{store: store}
+}
+
+// Get 取得快取的解析結果，found 為 false 表示沒有快取或已過期；查無地點的快取返回 nil 與 true
+func (r *GeocodeRepository) Get(query string) (*model.ResolvedLocation, bool, error) {
+	var entry geocodeEntry
+	found, err := r.store.Get(geocodeKeyPrefix+query, &entry)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		return nil, false, r.store.Delete(geocodeKeyPrefix + query)
+	}
+	return entry.Location, true, nil
+}
+
+// Save 保存解析結果，location 為 nil 表示查無此地點
+func (r *GeocodeRepository) Save(query string, location *model.ResolvedLocation, ttl time.Duration) error {
+	return r.store.Put(geocodeKeyPrefix+query, geocodeEntry{Location: location, ExpiresAt: time.Now().Add(ttl)})
+}
+
+// GetArea 取得 geohash 格子快取的行政區，found 為 false 表示沒有快取或已過期
+func (r *GeocodeRepository) GetArea(cell string) (*model.Area, bool, error) {
+	var entry areaEntry
+	found, err := r.store.Get(areaKeyPrefix+cell, &entry)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		return nil, false, r.store.Delete(areaKeyPrefix + cell)
+	}
+	return entry.Area, true, nil
+}
+
+// SaveArea 保存 geohash 格子的行政區，area 為 nil 表示不在任何已知的行政區內
+func (r *GeocodeRepository) SaveArea(cell string, area *model.Area, ttl time.Duration) error {
+	return r.store.Put(areaKeyPrefix+cell, areaEntry{Area: area, ExpiresAt: time.Now().Add(ttl)})
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+	"what2eat-backend/internal/model"
+)
+
+const historyKeyPrefix = "history:"
+
+// HistoryRepository 存取使用者的用餐紀錄，底層儲存可替換（記憶體 / 本地檔案）
+type HistoryRepository struct {
+	store Store
+}
+
+func NewHistoryRepository(store Store) *HistoryRepository {
+	return &HistoryRepository{store: store}
+}
+
+func historyKey(userID, recordID string) string {
+	return fmt.Sprintf("%s%s:%s", historyKeyPrefix, userID, recordID)
+}
+
+// List 取得使用者的所有用餐紀錄（未排序）
+func (r *HistoryRepository) List(userID string) ([]model.MealRecord, error) {
+	keys, err := r.store.Keys(historyKeyPrefix + userID + ":")
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]model.MealRecord, 0, len(keys))
+	for _, key := range keys {
+		var record model.MealRecord
+		found, err := r.store.Get(key, &record)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			record.UserID = userID
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// Get 取得單筆用餐紀錄，不存在時返回 nil
+func (r *HistoryRepository) Get(userID, recordID string) (*model.MealRecord, error) {
+	var record model.MealRecord
+	found, err := r.store.Get(historyKey(userID, recordID), &record)
+	if err != nil || !found {
+		return nil, err
+	}
+	record.UserID = userID
+	return &record, nil
+}
+
+// Save 新增或更新用餐紀錄
+func (r *HistoryRepository) Save(record model.MealRecord) error {
+	return r.store.Put(historyKey(record.UserID, record.ID), record)
+}
+
+// Delete 刪除用餐紀錄
+func (r *HistoryRepository) Delete(userID, recordID string) error {
+	return r.store.Delete(historyKey(userID, recordID))
+}
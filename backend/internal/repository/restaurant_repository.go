@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net/url"
 	"os"
+	"strings"
 	"sync"
 	"time"
 	"what2eat-backend/internal/model"
@@ -34,19 +36,36 @@ func NewRestaurantRepository(mapsClient *maps.Client) *RestaurantRepository {
 	}
 }
 
+// 附近搜尋結果的緩存時間
+const nearbyCacheTTL = time.Hour
+
+// 生成緩存的鍵值
+// 將經緯度值取至小數點後3位，代表約100公尺的範圍
+func nearbyCacheKey(lat, lng float64, restaurantType string) string {
+	return fmt.Sprintf("%.3f:%.3f:%s", lat, lng, restaurantType)
+}
+
+// IsCached 檢查該位置的附近搜尋是否有有效的緩存，有緩存時 SearchNearby 不會呼叫 Google API
+func (r *RestaurantRepository) IsCached(lat, lng float64, restaurantType string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, found := r.cache[nearbyCacheKey(lat, lng, restaurantType)]
+	return found && time.Since(entry.timestamp) < nearbyCacheTTL
+}
+
 // SearchNearby 搜尋附近餐廳
 // fetchPhotos 參數控制是否獲取照片URL，如果為false則只儲存照片引用
-func (r *RestaurantRepository) SearchNearby(lat, lng float64, restaurantType string, fetchPhotos ...bool) ([]model.Restaurant, error) {
-	// 生成緩存的鍵值
-	// 將經緯度值取至小數點後3位，代表約100公尺的範圍
-	cacheKey := fmt.Sprintf("%.3f:%.3f:%s", lat, lng, restaurantType)
+// ctx 取消時停止之後的名稱關鍵字搜尋，未完成的結果不寫入緩存
+func (r *RestaurantRepository) SearchNearby(ctx context.Context, lat, lng float64, restaurantType string, fetchPhotos ...bool) ([]model.Restaurant, error) {
+	cacheKey := nearbyCacheKey(lat, lng, restaurantType)
 
 	// 讀取緩存需要加讀鎖
 	r.mu.RLock()
 	// 檢查緩存中是否有有效的資料
 	if entry, found := r.cache[cacheKey]; found {
 		// 檢查緩存是否在有效期內（1小時）
-		if time.Since(entry.timestamp) < time.Hour {
+		if time.Since(entry.timestamp) < nearbyCacheTTL {
 			r.mu.RUnlock()
 
 			// 如果需要照片URL，檢查並處理
@@ -70,8 +89,6 @@ func (r *RestaurantRepository) SearchNearby(lat, lng float64, restaurantType str
 	}
 	r.mu.RUnlock()
 
-	ctx := context.Background()
-
 	// 設定搜尋參數
 	request := &maps.NearbySearchRequest{
 		Location: &maps.LatLng{
@@ -97,7 +114,7 @@ func (r *RestaurantRepository) SearchNearby(lat, lng float64, restaurantType str
 	// 執行搜尋
 	response, err := r.mapsClient.NearbySearch(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("Google Places API 錯誤: %v", err)
+		return nil, model.NewUpstreamError("Google Places API", err)
 	}
 
 	// 記錄搜尋結果
@@ -116,19 +133,34 @@ func (r *RestaurantRepository) SearchNearby(lat, lng float64, restaurantType str
 		// 降低評分要求到 3.5
 		if place.Rating >= 3.5 {
 			restaurant := model.Restaurant{
-				Name:           place.Name,
-				Rating:         place.Rating,
-				PlaceID:        place.PlaceID,
-				Address:        place.Vicinity,
-				PriceLevel:     int(place.PriceLevel),
-				RestaurantType: restaurantType, // 添加餐廳類型
+				Name:             place.Name,
+				Rating:           place.Rating,
+				UserRatingsTotal: place.UserRatingsTotal,
+				PlaceID:          place.PlaceID,
+				Address:          place.Vicinity,
+				PriceLevel:       int(place.PriceLevel),
+				RestaurantType:   restaurantType, // 添加餐廳類型
 			}
 
 			// 設置平均消費金額 (根據價格等級估算)
 			restaurant.AveragePrice = r.estimateAveragePrice(int(place.PriceLevel))
 
-			// 計算距離
-			restaurant.Distance = r.calculateDistance(lat, lng, place.Geometry.Location.Lat, place.Geometry.Location.Lng)
+			// 記錄由主要搜尋產生的推薦理由
+			if restaurantType != "" {
+				restaurant.Reasons = []model.Reason{{
+					Code:   "type_search",
+					Stage:  model.StagePrimarySearch,
+					Params: map[string]string{"type": restaurantType},
+				}}
+			} else {
+				restaurant.Reasons = []model.Reason{{Code: "nearby_search", Stage: model.StagePrimarySearch}}
+			}
+
+			// 記錄座標並計算距離
+			restaurant.Lat = place.Geometry.Location.Lat
+			restaurant.Lng = place.Geometry.Location.Lng
+			restaurant.DistanceMeters = HaversineMeters(lat, lng, place.Geometry.Location.Lat, place.Geometry.Location.Lng)
+			restaurant.Distance = FormatDistance(restaurant.DistanceMeters)
 
 			// 處理照片
 			if len(place.Photos) > 0 {
@@ -151,6 +183,10 @@ func (r *RestaurantRepository) SearchNearby(lat, lng float64, restaurantType str
 		nameKeywords := getNameKeywords(restaurantType)
 
 		for _, nameKeyword := range nameKeywords {
+			// 請求已取消（如客戶端中斷連線）時不再繼續搜尋
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 			additionalResults, err := r.searchRestaurantsByName(ctx, lat, lng, nameKeyword, restaurants, restaurantType, shouldFetchPhotos)
 			if err != nil {
 				fmt.Printf("名稱搜尋 '%s' 出錯: %v\n", nameKeyword, err)
@@ -194,7 +230,11 @@ func (r *RestaurantRepository) SearchNearby(lat, lng float64, restaurantType str
 }
 
 func (r *RestaurantRepository) calculateDistance(lat1, lng1, lat2, lng2 float64) string {
-	// Haversine 公式計算距離
+	return FormatDistance(HaversineMeters(lat1, lng1, lat2, lng2))
+}
+
+// HaversineMeters 以 Haversine 公式計算兩點間的直線距離（公尺）
+func HaversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
 	const earthRadius = 6371 // 地球半徑（公里）
 
 	dLat := (lat2 - lat1) * math.Pi / 180
@@ -205,8 +245,11 @@ func (r *RestaurantRepository) calculateDistance(lat1, lng1, lat2, lng2 float64)
 			math.Sin(dLng/2)*math.Sin(dLng/2)
 
 	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-	distance := earthRadius * c * 1000 // 轉換為公尺
+	return earthRadius * c * 1000 // 轉換為公尺
+}
 
+// FormatDistance 將公尺轉為顯示用字串 (如: "350m"、"1.2km")
+func FormatDistance(distance float64) string {
 	if distance < 1000 {
 		return fmt.Sprintf("%.0fm", distance)
 	}
@@ -274,7 +317,7 @@ func (r *RestaurantRepository) searchRestaurantsByName(ctx context.Context, lat,
 	// 執行搜尋
 	response, err := r.mapsClient.NearbySearch(ctx, request)
 	if err != nil {
-		return existingResults, err // 返回已有的結果，不因這個錯誤而中斷
+		return existingResults, model.NewUpstreamError("Google Places API", err) // 返回已有的結果，不因這個錯誤而中斷
 	}
 
 	fmt.Printf("名稱搜尋 '%s' 返回了 %d 個結果\n", nameKeyword, len(response.Results))
@@ -295,19 +338,30 @@ func (r *RestaurantRepository) searchRestaurantsByName(ctx context.Context, lat,
 		// 只選擇評分 3.5 以上的餐廳
 		if place.Rating >= 3.5 {
 			restaurant := model.Restaurant{
-				Name:           place.Name,
-				Rating:         place.Rating,
-				PlaceID:        place.PlaceID,
-				Address:        place.Vicinity,
-				PriceLevel:     int(place.PriceLevel),
-				RestaurantType: restaurantType, // 硬編碼為餐廳類型
+				Name:             place.Name,
+				Rating:           place.Rating,
+				UserRatingsTotal: place.UserRatingsTotal,
+				PlaceID:          place.PlaceID,
+				Address:          place.Vicinity,
+				PriceLevel:       int(place.PriceLevel),
+				RestaurantType:   restaurantType, // 硬編碼為餐廳類型
 			}
 
 			// 設置平均消費金額
 			restaurant.AveragePrice = r.estimateAveragePrice(int(place.PriceLevel))
 
-			// 計算距離
-			restaurant.Distance = r.calculateDistance(lat, lng, place.Geometry.Location.Lat, place.Geometry.Location.Lng)
+			// 記錄由名稱搜尋產生的推薦理由
+			restaurant.Reasons = []model.Reason{{
+				Code:   "keyword_match",
+				Stage:  model.StageNameSearch,
+				Params: map[string]string{"type": restaurantType, "keyword": nameKeyword},
+			}}
+
+			// 記錄座標並計算距離
+			restaurant.Lat = place.Geometry.Location.Lat
+			restaurant.Lng = place.Geometry.Location.Lng
+			restaurant.DistanceMeters = HaversineMeters(lat, lng, place.Geometry.Location.Lat, place.Geometry.Location.Lng)
+			restaurant.Distance = FormatDistance(restaurant.DistanceMeters)
 
 			// 處理照片
 			if len(place.Photos) > 0 {
@@ -380,10 +434,36 @@ func getNameKeywords(restaurantType string) []string {
 	return []string{}
 }
 
+// RestaurantTypes 支援的餐廳類型（固定順序）
+var RestaurantTypes = []string{
+	"中式料理", "日式料理", "義式料理", "韓式料理", "美式料理", "泰式料理", "早午餐",
+	"海鮮料理", "牛排", "火鍋", "甜點", "咖啡廳", "自助餐廳",
+}
+
+// InferCuisine 根據餐廳名稱推測料理類型，返回類型與命中的關鍵字
+// 多個關鍵字命中時取最長者，皆未命中時返回空字串
+func InferCuisine(name string) (string, string) {
+	lowerName := strings.ToLower(name)
+	bestType, bestKeyword := "", ""
+
+	for _, restaurantType := range RestaurantTypes {
+		for _, keyword := range getNameKeywords(restaurantType) {
+			if len(keyword) <= len(bestKeyword) {
+				continue
+			}
+			if strings.Contains(lowerName, strings.ToLower(keyword)) {
+				bestType, bestKeyword = restaurantType, keyword
+			}
+		}
+	}
+
+	return bestType, bestKeyword
+}
+
 // GetRandomRestaurants 獲取指定數量的隨機餐廳，並為它們填充照片URL
-func (r *RestaurantRepository) GetRandomRestaurants(lat, lng float64, restaurantType string, count int) ([]model.Restaurant, error) {
+func (r *RestaurantRepository) GetRandomRestaurants(ctx context.Context, lat, lng float64, restaurantType string, count int) ([]model.Restaurant, error) {
 	// 首先獲取所有符合條件的餐廳，不立即獲取照片URL
-	allRestaurants, err := r.SearchNearby(lat, lng, restaurantType, false)
+	allRestaurants, err := r.SearchNearby(ctx, lat, lng, restaurantType, false)
 	if err != nil {
 		return nil, err
 	}
@@ -406,20 +486,69 @@ func (r *RestaurantRepository) GetRandomRestaurants(lat, lng float64, restaurant
 	randomRestaurants := allRestaurants[:resultCount]
 
 	// 只為這些最終結果獲取照片URL
-	for i := range randomRestaurants {
+	if err := r.ResolvePhotoURLs(ctx, randomRestaurants, nil); err != nil {
+		return nil, err
+	}
+
+	return randomRestaurants, nil
+}
+
+// 每張照片之間的間隔，避免超過API限制
+const photoRequestInterval = 300 * time.Millisecond
+
+// ResolvePhotoURLs 將最終結果中的照片引用（"photoref:" 開頭）轉換為實際的照片URL
+// 每家餐廳處理完成後呼叫 resolved（可為 nil），ctx 取消時停止並返回錯誤
+func (r *RestaurantRepository) ResolvePhotoURLs(ctx context.Context, restaurants []model.Restaurant, resolved func(i int)) error {
+	for i := range restaurants {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		// 檢查是否有照片引用（以"photoref:"開頭）
-		if len(randomRestaurants[i].PhotoURL) > 9 && randomRestaurants[i].PhotoURL[:9] == "photoref:" {
+		hasPhotoReference := len(restaurants[i].PhotoURL) > 9 && restaurants[i].PhotoURL[:9] == "photoref:"
+		if hasPhotoReference {
 			// 提取照片引用
-			photoReference := randomRestaurants[i].PhotoURL[9:]
+			photoReference := restaurants[i].PhotoURL[9:]
 			// 獲取實際的照片URL
-			randomRestaurants[i].PhotoURL = r.getPhotoURL(photoReference)
+			restaurants[i].PhotoURL = r.getPhotoURL(photoReference)
+		}
+		if resolved != nil {
+			resolved(i)
+		}
 
-			// 添加延遲以避免超過API限制
-			if i < resultCount-1 {
-				time.Sleep(300 * time.Millisecond)
+		// 添加延遲以避免超過API限制
+		if hasPhotoReference && i < len(restaurants)-1 {
+			select {
+			case <-time.After(photoRequestInterval):
+			case <-ctx.Done():
+				return ctx.Err()
 			}
 		}
 	}
+	return nil
+}
 
-	return randomRestaurants, nil
+// FindCachedPlace 從搜尋緩存中找出指定 place_id 的餐廳資料，不呼叫 Google API
+func (r *RestaurantRepository) FindCachedPlace(placeID string) (*model.Restaurant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entry := range r.cache {
+		for _, restaurant := range entry.restaurants {
+			if restaurant.PlaceID == placeID {
+				found := restaurant
+				return &found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// GoogleMapsURL 產生在 Google Maps 開啟餐廳的網址
+func GoogleMapsURL(name, placeID string) string {
+	params := url.Values{"api": {"1"}, "query": {name}}
+	if placeID != "" {
+		params.Set("query_place_id", placeID)
+	}
+	return "https://www.google.com/maps/search/?" + params.Encode()
 }
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"what2eat-backend/internal/model"
+)
+
+const (
+	scheduleKeyPrefix = "schedule:"
+	deliveryKeyPrefix = "delivery:"
+)
+
+// ScheduleRepository 存取推送排程與發送紀錄，底層儲存可替換（記憶體 / 本地檔案）
+type ScheduleRepository struct {
+	store Store
+}
+
+func NewScheduleRepository(store Store) *ScheduleRepository {
+	return &ScheduleRepository{store: store}
+}
+
+func scheduleKey(userID, scheduleID string) string {
+	return fmt.Sprintf("%s%s:%s", scheduleKeyPrefix, userID, scheduleID)
+}
+
+func deliveryKey(scheduleID, deliveryID string) string {
+	return fmt.Sprintf("%s%s:%s", deliveryKeyPrefix, scheduleID, deliveryID)
+}
+
+// List 取得使用者的所有排程（未排序）
+func (r *ScheduleRepository) List(userID string) ([]model.Schedule, error) {
+	return r.list(scheduleKeyPrefix + userID + ":")
+}
+
+// ListAll 取得所有使用者的排程，供排程器載入
+func (r *ScheduleRepository) ListAll() ([]model.Schedule, error) {
+	return r.list(scheduleKeyPrefix)
+}
+
+func (r *ScheduleRepository) list(prefix string) ([]model.Schedule, error) {
+	keys, err := r.store.Keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]model.Schedule, 0, len(keys))
+	for _, key := range keys {
+		var schedule model.Schedule
+		found, err := r.store.Get(key, &schedule)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			// 使用者 ID 不序列化，從鍵還原
+			schedule.UserID, _, _ = strings.Cut(strings.TrimPrefix(key, scheduleKeyPrefix), ":")
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+// Get 取得單一排程，不存在時返回 nil
+func (r *ScheduleRepository) Get(userID, scheduleID string) (*model.Schedule, error) {
+	var schedule model.Schedule
+	found, err := r.store.Get(scheduleKey(userID, scheduleID), &schedule)
+	if err != nil || !found {
+		return nil, err
+	}
+	schedule.UserID = userID
+	return &schedule, nil
+}
+
+// Save 新增或更新排程
+func (r *ScheduleRepository) Save(schedule model.Schedule) error {
+	return r.store.Put(scheduleKey(schedule.UserID, schedule.ID), schedule)
+}
+
+// Delete 刪除排程
+func (r *ScheduleRepository) Delete(userID, scheduleID string) error {
+	return r.store.Delete(scheduleKey(userID, scheduleID))
+}
+
+// ListDeliveries 取得排程的所有發送紀錄（未排序）
+func (r *ScheduleRepository) ListDeliveries(scheduleID string) ([]model.Delivery, error) {
+	keys, err := r.store.Keys(deliveryKeyPrefix + scheduleID + ":")
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]model.Delivery, 0, len(keys))
+	for _, key := range keys {
+		var delivery model.Delivery
+		found, err := r.store.Get(key, &delivery)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+// SaveDelivery 新增或更新發送紀錄
+func (r *ScheduleRepository) SaveDelivery(delivery model.Delivery) error {
+	return r.store.Put(deliveryKey(delivery.ScheduleID, delivery.ID), delivery)
+}
+
+// DeleteDelivery 刪除發送紀錄
+func (r *ScheduleRepository) DeleteDelivery(scheduleID, deliveryID string) error {
+	return r.store.Delete(deliveryKey(scheduleID, deliveryID))
+}
@@ -0,0 +1,61 @@
+package repository
+
+import "what2eat-backend/internal/model"
+
+const shareKeyPrefix = "share:"
+
+// ShareRepository 存取分享的推薦結果，底層儲存可替換（記憶體 / 本地檔案）
+type ShareRepository struct {
+	store Store
+}
+
+func NewShareRepository(store Store) *ShareRepository {
+	return &ShareRepository{store: store}
+}
+
+// Get 取得分享，不存在時返回 nil
+func (r *ShareRepository) Get(id string) (*model.Share, error) {
+	var share model.Share
+	found, err := r.store.Get(shareKeyPrefix+id, &share)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// Exists 檢查分享 ID 是否已被使用
+func (r *ShareRepository) Exists(id string) (bool, error) {
+	var share model.Share
+	return r.store.Get(shareKeyPrefix+id, &share)
+}
+
+// Save 新增或更新分享
+func (r *ShareRepository) Save(share model.Share) error {
+	return r.store.Put(shareKeyPrefix+share.ID, share)
+}
+
+// Delete 刪除分享
+func (r *ShareRepository) Delete(id string) error {
+	return r.store.Delete(shareKeyPrefix + id)
+}
+
+// List 取得所有分享，供清除過期分享使用
+func (r *ShareRepository) List() ([]model.Share, error) {
+	keys, err := r.store.Keys(shareKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]model.Share, 0, len(keys))
+	for _, key := range keys {
+		var share model.Share
+		found, err := r.store.Get(key, &share)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Store 以字串鍵存取 JSON 資料的簡易儲存介面
+// 實作可以是純記憶體或本地檔案，未來也可以替換為 Firebase 等外部服務
+type Store interface {
+	// Get 讀取資料到 v，資料不存在時返回 false
+	Get(key string, v interface{}) (bool, error)
+	// Put 寫入（覆蓋）資料
+	Put(key string, v interface{}) error
+	// Delete 刪除資料，資料不存在時不視為錯誤
+	Delete(key string) error
+	// Keys 依字典序列出指定前綴的所有鍵
+	Keys(prefix string) ([]string, error)
+}
+
+// MemoryStore 純記憶體的 Store 實作，重啟後資料會消失
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Get(key string, v interface{}) (bool, error) {
+	s.mu.RLock()
+	raw, found := s.data[key]
+	s.mu.RUnlock()
+
+	if !found {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("無法解析儲存資料 %s: %w", key, err)
+	}
+	return true, nil
+}
+
+func (s *MemoryStore) Put(key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("無法序列化儲存資料 %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	s.data[key] = raw
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.data, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Keys(prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0)
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// FileStore 以單一 JSON 檔案持久化的 Store 實作
+// 資料量小（收藏、房間等），每次寫入時整份覆寫檔案
+type FileStore struct {
+	*MemoryStore
+	path      string
+	fileMutex sync.Mutex
+}
+
+// NewFileStore 建立檔案儲存，若檔案已存在則載入既有資料
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("無法建立儲存目錄: %w", err)
+	}
+
+	fs := &FileStore{MemoryStore: NewMemoryStore(), path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// 檔案不存在是正常的，第一次運行
+			return fs, nil
+		}
+		return nil, fmt.Errorf("無法讀取儲存檔案: %w", err)
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("無法解析儲存檔案 %s: %w", path, err)
+	}
+	for key, raw := range entries {
+		fs.data[key] = raw
+	}
+
+	return fs, nil
+}
+
+func (s *FileStore) Put(key string, v interface{}) error {
+	if err := s.MemoryStore.Put(key, v); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+func (s *FileStore) Delete(key string) error {
+	if err := s.MemoryStore.Delete(key); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// 將記憶體中的資料整份寫回檔案（先寫暫存檔再改名，避免寫到一半損毀）
+func (s *FileStore) flush() error {
+	s.fileMutex.Lock()
+	defer s.fileMutex.Unlock()
+
+	s.mu.RLock()
+	entries := make(map[string]json.RawMessage, len(s.data))
+	for key, raw := range s.data {
+		entries[key] = raw
+	}
+	s.mu.RUnlock()
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("無法序列化儲存檔案: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("無法寫入儲存檔案: %w", err)
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+// OpenStore 依檔案路徑建立 Store，路徑為空時使用純記憶體儲存
+func OpenStore(path string) (Store, error) {
+	if path == "" {
+		return NewMemoryStore(), nil
+	}
+	return NewFileStore(path)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"strings"
+	"what2eat-backend/internal/model"
+)
+
+const (
+	accountKeyPrefix  = "account:"
+	identityKeyPrefix = "identity:"
+	deviceKeyPrefix   = "device:"
+	linkKeyPrefix     = "link:"
+)
+
+// UserRepository 存取帳號、外部身分與裝置綁定資料
+type UserRepository struct {
+	store Store
+}
+
+func NewUserRepository(store Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// GetAccount 取得帳號，不存在時返回 nil
+func (r *UserRepository) GetAccount(accountID string) (*model.Account, error) {
+	var account model.Account
+	found, err := r.store.Get(accountKeyPrefix+accountID, &account)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// SaveAccount 新增或更新帳號
+func (r *UserRepository) SaveAccount(account model.Account) error {
+	return r.store.Put(accountKeyPrefix+account.ID, account)
+}
+
+// FindAccountByIdentity 依外部身分（提供者 + 識別碼）尋找帳號 ID
+func (r *UserRepository) FindAccountByIdentity(provider, subject string) (string, error) {
+	var accountID string
+	_, err := r.store.Get(identityKeyPrefix+provider+":"+subject, &accountID)
+	return accountID, err
+}
+
+// SaveIdentity 記錄外部身分對應的帳號 ID
+func (r *UserRepository) SaveIdentity(provider, subject, accountID string) error {
+	return r.store.Put(identityKeyPrefix+provider+":"+subject, accountID)
+}
+
+// IdentitySubjects 列出帳號在指定提供者綁定的使用者識別碼
+func (r *UserRepository) IdentitySubjects(provider, accountID string) ([]string, error) {
+	prefix := identityKeyPrefix + provider + ":"
+	keys, err := r.store.Keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var subjects []string
+	for _, key := range keys {
+		var linked string
+		found, err := r.store.Get(key, &linked)
+		if err != nil {
+			return nil, err
+		}
+		if found && linked == accountID {
+			subjects = append(subjects, strings.TrimPrefix(key, prefix))
+		}
+	}
+	return subjects, nil
+}
+
+// LinkedAccount 取得匿名裝置已綁定的帳號 ID，尚未綁定時返回空字串
+func (r *UserRepository) LinkedAccount(deviceID string) (string, error) {
+	var accountID string
+	_, err := r.store.Get(deviceKeyPrefix+deviceID, &accountID)
+	return accountID, err
+}
+
+// LinkDevice 記錄匿名裝置綁定到帳號
+func (r *UserRepository) LinkDevice(deviceID, accountID string) error {
+	return r.store.Put(deviceKeyPrefix+deviceID, accountID)
+}
+
+// GetLinkRequest 取得帳號綁定請求，不存在時返回 nil
+func (r *UserRepository) GetLinkRequest(requestID string) (*model.LinkRequest, error) {
+	var request model.LinkRequest
+	found, err := r.store.Get(linkKeyPrefix+requestID, &request)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// SaveLinkRequest 新增或更新帳號綁定請求
+func (r *UserRepository) SaveLinkRequest(request model.LinkRequest) error {
+	return r.store.Put(linkKeyPrefix+request.ID, request)
+}
+
+// DeleteLinkRequest 刪除帳號綁定請求
+func (r *UserRepository) DeleteLinkRequest(requestID string) error {
+	return r.store.Delete(linkKeyPrefix + requestID)
+}
+
+// LinkRequestIDs 列出所有帳號綁定請求的 ID
+func (r *UserRepository) LinkRequestIDs() ([]string, error) {
+	keys, err := r.store.Keys(linkKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = strings.TrimPrefix(key, linkKeyPrefix)
+	}
+	return ids, nil
+}
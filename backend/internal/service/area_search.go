@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrInvalidArea  = &model.Error{Kind: model.ErrInvalidLocation, Message: "無效的範圍，需提供西南角 sw 與東北角 ne 的緯度,經度"}
+	ErrAreaTooLarge = &model.Error{Kind: model.ErrInvalidLocation, Message: "範圍太大，請放大地圖後再試"}
+)
+
+const (
+	defaultAreaPageSize = 50
+	maxAreaPageSize     = 100
+	// MaxAreaPage 頁碼上限，確保 (page-1)*pageSize 不會溢位
+	MaxAreaPage = math.MaxInt / maxAreaPageSize
+	// 同時呼叫 Google API 的格子數
+	areaSearchConcurrency = 3
+	// 每度緯度的長度（公尺）
+	metersPerDegreeLat = 111320
+)
+
+// 搜尋格子的中心
+type areaTile struct {
+	lat, lng float64
+}
+
+// SearchArea 將地圖範圍切成格子搜尋，合併去重後分頁返回
+// 格子中心對齊附近搜尋的緩存格點，與一般推薦共用緩存；未緩存的格子每次請求最多搜尋 MaxUpstreamCalls 個
+func (s *RestaurantService) SearchArea(ctx context.Context, query model.AreaSearchQuery) (*model.AreaSearchResult, error) {
+	if !finite(query.South, query.West, query.North, query.East) ||
+		query.South < -90 || query.North > 90 || query.West < -180 || query.East > 180 ||
+		query.South >= query.North || query.West >= query.East {
+		return nil, ErrInvalidArea
+	}
+
+	rows, cols := s.tileGrid(query)
+	if rows*cols > s.areaSearch.MaxTiles {
+		return nil, fmt.Errorf("%w（需要 %d 個格子，上限 %d）", ErrAreaTooLarge, rows*cols, s.areaSearch.MaxTiles)
+	}
+	tiles := areaTiles(query, rows, cols)
+
+	// 已緩存的格子不計入上限，其餘依離中心的距離排序，優先搜尋畫面中央
+	var cached, uncached []areaTile
+	for _, tile := range tiles {
+		if s.repo.IsCached(tile.lat, tile.lng, query.RestaurantType) {
+			cached = append(cached, tile)
+		} else {
+			uncached = append(uncached, tile)
+		}
+	}
+	centerLat, centerLng := (query.South+query.North)/2, (query.West+query.East)/2
+	sort.SliceStable(uncached, func(i, j int) bool {
+		return repository.HaversineMeters(centerLat, centerLng, uncached[i].lat, uncached[i].lng) <
+			repository.HaversineMeters(centerLat, centerLng, uncached[j].lat, uncached[j].lng)
+	})
+
+	stats := model.AreaTiles{Total: len(tiles), Cached: len(cached)}
+	toSearch := uncached
+	if len(toSearch) > s.areaSearch.MaxUpstreamCalls {
+		toSearch = toSearch[:s.areaSearch.MaxUpstreamCalls]
+	}
+	stats.Skipped = len(uncached) - len(toSearch)
+
+	// 先取出緩存的格子，再並行搜尋未緩存的格子
+	results := make([][]model.Restaurant, 0, len(cached)+len(toSearch))
+	for _, tile := range cached {
+		restaurants, err := s.repo.SearchNearby(ctx, tile.lat, tile.lng, query.RestaurantType, false)
+		if err == nil {
+			results = append(results, restaurants)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var searchErr, quotaErr error
+	semaphore := make(chan struct{}, areaSearchConcurrency)
+	for _, tile := range toSearch {
+		// 每個未緩存的格子計入每日額度，額度用盡時其餘格子略過
+		if ctx.Err() != nil {
+			mu.Lock()
+			stats.Skipped++
+			mu.Unlock()
+			continue
+		}
+		if _, _, err := s.counterService.IncrementAndGetUsage(); err != nil {
+			mu.Lock()
+			stats.Skipped++
+			quotaErr = err
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(tile areaTile) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			restaurants, err := s.repo.SearchNearby(ctx, tile.lat, tile.lng, query.RestaurantType, false)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Printf("範圍搜尋格子 [%.3f, %.3f] 失敗: %v\n", tile.lat, tile.lng, err)
+				stats.Skipped++
+				searchErr = err
+				return
+			}
+			stats.Searched++
+			results = append(results, restaurants)
+		}(tile)
+	}
+	wg.Wait()
+
+	// 所有格子都失敗或因額度略過時返回錯誤，部分失敗時返回已取得的結果
+	if len(results) == 0 && searchErr != nil {
+		return nil, fmt.Errorf("搜尋餐廳失敗: %w", searchErr)
+	}
+	if len(results) == 0 && quotaErr != nil {
+		return nil, quotaErr
+	}
+
+	restaurants := mergeAreaResults(results, query, centerLat, centerLng)
+	fmt.Printf("範圍搜尋: %d 個格子 (緩存 %d、搜尋 %d、略過 %d)，範圍內共 %d 家餐廳\n",
+		stats.Total, stats.Cached, stats.Searched, stats.Skipped, len(restaurants))
+
+	return paginateArea(restaurants, query, stats), nil
+}
+
+// NaN 與 Inf 的比較結果都是 false，範圍檢查前需先排除
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// 依格子邊長計算範圍需要的列數與欄數
+func (s *RestaurantService) tileGrid(query model.AreaSearchQuery) (int, int) {
+	centerLat := (query.South + query.North) / 2
+	latStep := s.areaSearch.TileMeters / metersPerDegreeLat
+	lngStep := s.areaSearch.TileMeters / (metersPerDegreeLat * math.Cos(centerLat*math.Pi/180))
+
+	rows := max(int(math.Ceil((query.North-query.South)/latStep)), 1)
+	cols := max(int(math.Ceil((query.East-query.West)/lngStep)), 1)
+	return rows, cols
+}
+
+// 格子平均分布在範圍內，中心取至小數點後 3 位，與 SearchNearby 的緩存鍵一致
+func areaTiles(query model.AreaSearchQuery, rows, cols int) []areaTile {
+	latSize := (query.North - query.South) / float64(rows)
+	lngSize := (query.East - query.West) / float64(cols)
+	seen := make(map[string]bool, rows*cols)
+	tiles := make([]areaTile, 0, rows*cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			tile := areaTile{
+				lat: roundCoordinate(query.South+(float64(row)+0.5)*latSize, 3),
+				lng: roundCoordinate(query.West+(float64(col)+0.5)*lngSize, 3),
+			}
+			key := fmt.Sprintf("%.3f:%.3f", tile.lat, tile.lng)
+			if !seen[key] {
+				seen[key] = true
+				tiles = append(tiles, tile)
+			}
+		}
+	}
+	return tiles
+}
+
+// 合併各格子的結果：去除重複與範圍外的餐廳，依離範圍中心的距離排序
+func mergeAreaResults(results [][]model.Restaurant, query model.AreaSearchQuery, centerLat, centerLng float64) []model.Restaurant {
+	seen := make(map[string]bool)
+	var merged []model.Restaurant
+	for _, restaurants := range results {
+		for _, r := range restaurants {
+			if seen[r.PlaceID] || r.Lat < query.South || r.Lat > query.North || r.Lng < query.West || r.Lng > query.East {
+				continue
+			}
+			seen[r.PlaceID] = true
+
+			// 距離改為相對於範圍中心
+			r.DistanceMeters = repository.HaversineMeters(centerLat, centerLng, r.Lat, r.Lng)
+			r.Distance = repository.FormatDistance(r.DistanceMeters)
+			merged = append(merged, r)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].DistanceMeters != merged[j].DistanceMeters {
+			return merged[i].DistanceMeters < merged[j].DistanceMeters
+		}
+		return merged[i].PlaceID < merged[j].PlaceID
+	})
+	return merged
+}
+
+func paginateArea(restaurants []model.Restaurant, query model.AreaSearchQuery, stats model.AreaTiles) *model.AreaSearchResult {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultAreaPageSize
+	}
+	pageSize = min(pageSize, maxAreaPageSize)
+	page := min(max(query.Page, 1), MaxAreaPage)
+
+	start := min((page-1)*pageSize, len(restaurants))
+	end := min(start+pageSize, len(restaurants))
+	items := make([]model.Restaurant, end-start)
+	copy(items, restaurants[start:end])
+
+	// 推薦理由依請求語系產生
+	localizeReasons(items, query.Language)
+
+	return &model.AreaSearchResult{
+		Restaurants: items,
+		Total:       len(restaurants),
+		Page:        page,
+		PageSize:    pageSize,
+		TotalPages:  (len(restaurants) + pageSize - 1) / pageSize,
+		Tiles:       stats,
+		Complete:    stats.Skipped == 0,
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"what2eat-backend/internal/model"
+)
+
+func TestAreaTiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      model.AreaSearchQuery
+		rows, cols int
+		want       int
+	}{
+		{"單一格子", model.AreaSearchQuery{South: 25.03, West: 121.56, North: 25.034, East: 121.564}, 1, 1, 1},
+		{"3x4 格子", model.AreaSearchQuery{South: 25.0, West: 121.5, North: 25.03, East: 121.54}, 3, 4, 12},
+		// 格子比緩存格點（0.001 度）還小時，四捨五入到同一格點的格子只保留一個
+		{"重複的格點合併", model.AreaSearchQuery{South: 25.0331, West: 121.5651, North: 25.0334, East: 121.5654}, 4, 4, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiles := areaTiles(tt.query, tt.rows, tt.cols)
+			if len(tiles) != tt.want {
+				t.Fatalf("len(tiles) = %d, want %d", len(tiles), tt.want)
+			}
+
+			seen := make(map[areaTile]bool)
+			for _, tile := range tiles {
+				if seen[tile] {
+					t.Errorf("duplicate tile %+v", tile)
+				}
+				seen[tile] = true
+				// 中心取至小數點後 3 位，且在範圍內（容許四捨五入的誤差）
+				if tile.lat != roundCoordinate(tile.lat, 3) || tile.lng != roundCoordinate(tile.lng, 3) {
+					t.Errorf("tile %+v is not aligned to 3 decimals", tile)
+				}
+				if tile.lat < tt.query.South-0.0005 || tile.lat > tt.query.North+0.0005 ||
+					tile.lng < tt.query.West-0.0005 || tile.lng > tt.query.East+0.0005 {
+					t.Errorf("tile %+v outside the area", tile)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginateArea(t *testing.T) {
+	restaurants := make([]model.Restaurant, 120)
+	for i := range restaurants {
+		restaurants[i] = model.Restaurant{PlaceID: fmt.Sprintf("place-%d", i)}
+	}
+
+	tests := []struct {
+		name         string
+		page         int
+		pageSize     int
+		wantPage     int
+		wantPageSize int
+		wantFirst    string
+		wantCount    int
+		wantPages    int
+	}{
+		{name: "預設每頁數量", page: 1, wantPage: 1, wantPageSize: defaultAreaPageSize, wantFirst: "place-0", wantCount: 50, wantPages: 3},
+		{name: "最後一頁不足一頁", page: 3, pageSize: 50, wantPage: 3, wantPageSize: 50, wantFirst: "place-100", wantCount: 20, wantPages: 3},
+		{name: "超過最後一頁", page: 4, pageSize: 50, wantPage: 4, wantPageSize: 50, wantCount: 0, wantPages: 3},
+		{name: "每頁數量上限", page: 2, pageSize: 1000, wantPage: 2, wantPageSize: maxAreaPageSize, wantFirst: "place-100", wantCount: 20, wantPages: 2},
+		{name: "頁碼小於 1 視為第 1 頁", page: 0, pageSize: 10, wantPage: 1, wantPageSize: 10, wantFirst: "place-0", wantCount: 10, wantPages: 12},
+		{name: "極大頁碼不溢位", page: math.MaxInt, pageSize: maxAreaPageSize, wantPage: MaxAreaPage, wantPageSize: maxAreaPageSize, wantCount: 0, wantPages: 2},
+		{name: "頁碼上限", page: MaxAreaPage, pageSize: maxAreaPageSize, wantPage: MaxAreaPage, wantPageSize: maxAreaPageSize, wantCount: 0, wantPages: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := model.AreaTiles{Total: 4, Cached: 4}
+			result := paginateArea(restaurants, model.AreaSearchQuery{Page: tt.page, PageSize: tt.pageSize}, stats)
+
+			if result.Page != tt.wantPage || result.PageSize != tt.wantPageSize || result.TotalPages != tt.wantPages || result.Total != len(restaurants) {
+				t.Errorf("page %d/%d size %d total %d, want page %d/%d size %d total %d",
+					result.Page, result.TotalPages, result.PageSize, result.Total, tt.wantPage, tt.wantPages, tt.wantPageSize, len(restaurants))
+			}
+			if len(result.Restaurants) != tt.wantCount {
+				t.Fatalf("len(restaurants) = %d, want %d", len(result.Restaurants), tt.wantCount)
+			}
+			if tt.wantCount > 0 && result.Restaurants[0].PlaceID != tt.wantFirst {
+				t.Errorf("first = %s, want %s", result.Restaurants[0].PlaceID, tt.wantFirst)
+			}
+			if !result.Complete {
+				t.Error("complete = false with no skipped tiles")
+			}
+		})
+	}
+}
+
+func TestSearchAreaRejectsInvalidBounds(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name  string
+		query model.AreaSearchQuery
+	}{
+		{"NaN", model.AreaSearchQuery{South: nan, West: 121.5, North: 25.1, East: 121.6}},
+		{"Inf", model.AreaSearchQuery{South: 25.0, West: 121.5, North: inf, East: 121.6}},
+		{"負 Inf", model.AreaSearchQuery{South: 25.0, West: -inf, North: 25.1, East: 121.6}},
+		{"緯度超出範圍", model.AreaSearchQuery{South: 25.0, West: 121.5, North: 91, East: 121.6}},
+		{"西南角在東北角之北", model.AreaSearchQuery{South: 25.1, West: 121.5, North: 25.0, East: 121.6}},
+		{"寬度為 0", model.AreaSearchQuery{South: 25.0, West: 121.5, North: 25.1, East: 121.5}},
+	}
+
+	// 範圍檢查在使用任何依賴之前
+	s := &RestaurantService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.SearchArea(context.Background(), tt.query); !errors.Is(err, ErrInvalidArea) {
+				t.Errorf("err = %v, want ErrInvalidArea", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// ReverseGeocoder 取得座標所在的行政區，不在任何已知行政區內時返回 nil
+type ReverseGeocoder interface {
+	Name() string
+	ReverseGeocode(ctx context.Context, lat, lng float64) (*model.Area, error)
+}
+
+// AreaService 為查詢位置標示所在的行政區
+// 先以本地行政區邊界判斷，查無時才呼叫外部服務；結果以 geohash 格子快取，同一格子內共用
+type AreaService struct {
+	offline ReverseGeocoder
+	remote  ReverseGeocoder // nil 表示只使用本地行政區資料
+	repo    *repository.GeocodeRepository
+	counter *CounterService
+	cfg     config.GeocodeConfig
+}
+
+func NewAreaService(offline, remote ReverseGeocoder, repo *repository.GeocodeRepository, counter *CounterService, cfg config.GeocodeConfig) *AreaService {
+	return &AreaService{
+		offline: offline,
+		remote:  remote,
+		repo:    repo,
+		counter: counter,
+		cfg:     cfg,
+	}
+}
+
+// Resolve 取得座標所在的行政區，無法判斷時返回 nil（區域標籤只是輔助資訊，不影響推薦）
+func (s *AreaService) Resolve(ctx context.Context, lat, lng float64) *model.Area {
+	cell := encodeGeohash(lat, lng, s.cfg.AreaPrecision)
+	area, found, err := s.repo.GetArea(cell)
+	if err != nil {
+		fmt.Printf("警告: 無法讀取區域快取: %v\n", err)
+	}
+	if found {
+		return area
+	}
+
+	area, err = s.lookup(ctx, lat, lng)
+	if err != nil {
+		// 暫時性錯誤不快取，下次再試
+		fmt.Printf("警告: 無法判斷所在區域 [%.4f, %.4f]: %v\n", lat, lng, err)
+		return nil
+	}
+	if err := s.repo.SaveArea(cell, area, s.cfg.CacheTTL); err != nil {
+		fmt.Printf("警告: 無法保存區域快取: %v\n", err)
+	}
+	return area
+}
+
+func (s *AreaService) lookup(ctx context.Context, lat, lng float64) (*model.Area, error) {
+	area, err := s.offline.ReverseGeocode(ctx, lat, lng)
+	if err != nil || area != nil || s.remote == nil {
+		return area, err
+	}
+
+	if _, _, err := s.counter.IncrementAndGetUsage(); err != nil {
+		return nil, ErrGeocodeLimitExceeded
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, geocodeTimeout)
+	defer cancel()
+	area, err = s.remote.ReverseGeocode(ctx, lat, lng)
+	if err != nil {
+		s.counter.LogAPIRequest("reverse_geocode", lat, lng, "", false, err.Error())
+		return nil, err
+	}
+	s.counter.LogAPIRequest("reverse_geocode", lat, lng, "", true, "")
+	return area, nil
+}
@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrInvalidEmail         = &model.Error{Kind: model.ErrInvalidArgument, Message: "無效的 Email 地址"}
+	ErrAlreadyLinked        = &model.Error{Kind: model.ErrConflict, Message: "此裝置已綁定帳號"}
+	ErrOAuthNotConfigured   = &model.Error{Kind: model.ErrNotEnabled, Message: "未設定 OAuth 身分提供者"}
+	ErrMagicLinkNotEnabled  = &model.Error{Kind: model.ErrNotEnabled, Message: "未啟用 Email 登入"}
+	ErrLinkRequestNotFound  = &model.Error{Kind: model.ErrNotFound, Message: "找不到帳號綁定請求或已失效"}
+	ErrLinkRequestUsed      = &model.Error{Kind: model.ErrConflict, Message: "此連結已使用過"}
+	ErrLinkRequestPending   = &model.Error{Kind: model.ErrConflict, Message: "尚未完成身分驗證，請先點擊 Email 連結或完成 OAuth 授權"}
+	ErrLinkRequestForbidden = &model.Error{Kind: model.ErrForbidden, Message: "只有發起綁定的裝置可以完成綁定"}
+)
+
+const (
+	anonymousUserPrefix = "anon_"
+	accountUserPrefix   = "acct_"
+)
+
+// IdentityProvider 外部身分提供者（OAuth 或本地測試替身）
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*model.ExternalIdentity, error)
+}
+
+// MagicLinkSender 寄送 Email 登入連結
+type MagicLinkSender interface {
+	SendMagicLink(email, link string) error
+}
+
+// UserDataMerger 由持有使用者資料的服務實作，帳號綁定時將匿名資料合併到帳號
+type UserDataMerger interface {
+	MergeUser(fromUserID, toUserID string) error
+}
+
+// AuthService 發放與驗證使用者權杖，並處理匿名裝置綁定帳號
+type AuthService struct {
+	signer   *TokenSigner
+	users    *repository.UserRepository
+	provider IdentityProvider
+	mailer   MagicLinkSender
+	cfg      config.AuthConfig
+
+	mergersMu sync.RWMutex
+	mergers   []UserDataMerger
+
+	// 保護帳號綁定請求的讀取與更新，確保連結只能使用一次
+	linkMu sync.Mutex
+}
+
+func NewAuthService(users *repository.UserRepository, provider IdentityProvider, mailer MagicLinkSender, cfg config.AuthConfig) *AuthService {
+	return &AuthService{
+		signer:   NewTokenSigner(cfg.TokenSecret),
+		users:    users,
+		provider: provider,
+		mailer:   mailer,
+		cfg:      cfg,
+	}
+}
+
+// RegisterMerger 註冊帳號綁定時需要合併資料的服務
+func (s *AuthService) RegisterMerger(merger UserDataMerger) {
+	s.mergersMu.Lock()
+	defer s.mergersMu.Unlock()
+	s.mergers = append(s.mergers, merger)
+}
+
+// IssueDeviceToken 發放新的匿名裝置權杖
+func (s *AuthService) IssueDeviceToken() (string, string, error) {
+	id, err := randomHex(12)
+	if err != nil {
+		return "", "", err
+	}
+	userID := anonymousUserPrefix + id
+	token, err := s.signer.Sign(model.TokenClaims{
+		Subject:  userID,
+		Type:     model.TokenTypeDevice,
+		IssuedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	fmt.Printf("發放匿名裝置權杖: %s\n", userID)
+	return token, userID, nil
+}
+
+// Authenticate 驗證權杖並返回使用者 ID
+// 已綁定帳號的匿名裝置權杖會解析為帳號 ID，舊權杖在綁定後仍可繼續使用
+func (s *AuthService) Authenticate(token string) (string, error) {
+	claims, err := s.signer.Verify(token, model.TokenTypeDevice, model.TokenTypeAccount)
+	if err != nil {
+		return "", err
+	}
+
+	if claims.Type == model.TokenTypeDevice {
+		accountID, err := s.users.LinkedAccount(claims.Subject)
+		if err != nil {
+			return "", err
+		}
+		if accountID != "" {
+			return accountID, nil
+		}
+	}
+
+	return claims.Subject, nil
+}
+
+// RequestMagicLink 寄送 Email 登入連結並返回綁定請求 ID
+// 點擊連結只會驗證 Email，之後需由目前裝置以 CompleteLink 完成綁定
+func (s *AuthService) RequestMagicLink(userID, email string) (string, error) {
+	if s.mailer == nil {
+		return "", ErrMagicLinkNotEnabled
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", ErrInvalidEmail
+	}
+	email = strings.ToLower(address.Address)
+
+	request, err := s.newLinkRequest(userID)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.signer.Sign(model.TokenClaims{
+		Subject:   userID,
+		Type:      model.TokenTypeMagicLink,
+		Email:     email,
+		Nonce:     request.ID,
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: request.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	link := s.cfg.MagicLinkURL + "?" + url.Values{"token": {token}}.Encode()
+	if err := s.mailer.SendMagicLink(email, link); err != nil {
+		return "", model.NewUpstreamError("郵件伺服器", err)
+	}
+	return request.ID, nil
+}
+
+// VerifyMagicLink 驗證 Email 登入連結，返回已驗證的綁定請求 ID
+// 連結只能使用一次，且不會直接綁定帳號，避免他人以轉寄的連結取得帳號
+func (s *AuthService) VerifyMagicLink(token string) (string, error) {
+	claims, err := s.signer.Verify(token, model.TokenTypeMagicLink)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.verifyLinkRequest(claims, model.ExternalIdentity{
+		Provider: "email",
+		Subject:  claims.Email,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return "", err
+	}
+	return claims.Nonce, nil
+}
+
+// OAuthURL 產生 OAuth 授權網址與綁定請求 ID，state 參數帶有目前使用者 ID 與請求 ID 的簽章
+func (s *AuthService) OAuthURL(userID string) (string, string, error) {
+	if s.provider == nil {
+		return "", "", ErrOAuthNotConfigured
+	}
+
+	request, err := s.newLinkRequest(userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := s.signer.Sign(model.TokenClaims{
+		Subject:   userID,
+		Type:      model.TokenTypeOAuth,
+		Nonce:     request.ID,
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: request.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return s.provider.AuthCodeURL(state), request.ID, nil
+}
+
+// CompleteOAuth 驗證 state 並以授權碼向提供者取得身分，返回已驗證的綁定請求 ID
+// 與 Email 連結相同，實際綁定需由發起請求的裝置以 CompleteLink 完成
+func (s *AuthService) CompleteOAuth(ctx context.Context, state, code string) (string, error) {
+	if s.provider == nil {
+		return "", ErrOAuthNotConfigured
+	}
+
+	claims, err := s.signer.Verify(state, model.TokenTypeOAuth)
+	if err != nil {
+		return "", err
+	}
+
+	// 先確認 state 尚未使用，避免重放時仍向提供者交換授權碼
+	if _, err := s.pendingLinkRequest(claims); err != nil {
+		return "", err
+	}
+
+	identity, err := s.provider.Exchange(ctx, code)
+	if err != nil {
+		return "", model.NewUpstreamError("OAuth 身分提供者", err)
+	}
+
+	if err := s.verifyLinkRequest(claims, *identity); err != nil {
+		return "", err
+	}
+	return claims.Nonce, nil
+}
+
+// CompleteLink 由發起綁定的使用者確認已驗證的綁定請求，完成帳號綁定並返回帳號權杖
+// 綁定請求在完成後即刪除，無法重複使用
+func (s *AuthService) CompleteLink(userID, requestID string) (string, *model.Account, error) {
+	s.linkMu.Lock()
+	defer s.linkMu.Unlock()
+
+	request, err := s.users.GetLinkRequest(requestID)
+	if err != nil {
+		return "", nil, err
+	}
+	if request == nil || time.Now().After(request.ExpiresAt) {
+		return "", nil, ErrLinkRequestNotFound
+	}
+	if request.UserID != userID {
+		return "", nil, ErrLinkRequestForbidden
+	}
+	if request.Identity == nil {
+		return "", nil, ErrLinkRequestPending
+	}
+
+	if err := s.users.DeleteLinkRequest(request.ID); err != nil {
+		return "", nil, err
+	}
+	return s.linkAccount(request.UserID, *request.Identity)
+}
+
+// 建立帳號綁定請求，並順便清除已過期的請求
+func (s *AuthService) newLinkRequest(userID string) (*model.LinkRequest, error) {
+	s.linkMu.Lock()
+	defer s.linkMu.Unlock()
+
+	s.pruneLinkRequests()
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	request := model.LinkRequest{
+		ID:        id,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(s.cfg.MagicLinkTTL),
+	}
+	if err := s.users.SaveLinkRequest(request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// 記錄 Email 連結或 OAuth 回呼驗證完成的外部身分，每個請求只能驗證一次
+func (s *AuthService) verifyLinkRequest(claims *model.TokenClaims, identity model.ExternalIdentity) error {
+	s.linkMu.Lock()
+	defer s.linkMu.Unlock()
+
+	request, err := s.pendingLinkRequest(claims)
+	if err != nil {
+		return err
+	}
+
+	request.Identity = &identity
+	if err := s.users.SaveLinkRequest(*request); err != nil {
+		return err
+	}
+	fmt.Printf("帳號綁定請求 %s 已通過 %s 驗證，等待原裝置確認\n", request.ID, identity.Provider)
+	return nil
+}
+
+// 取得權杖對應且尚未驗證的綁定請求
+func (s *AuthService) pendingLinkRequest(claims *model.TokenClaims) (*model.LinkRequest, error) {
+	if claims.Nonce == "" {
+		return nil, ErrInvalidToken
+	}
+
+	request, err := s.users.GetLinkRequest(claims.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.UserID != claims.Subject || time.Now().After(request.ExpiresAt) {
+		return nil, ErrLinkRequestNotFound
+	}
+	if request.Identity != nil {
+		return nil, ErrLinkRequestUsed
+	}
+	return request, nil
+}
+
+// 刪除已過期的綁定請求（需持有 s.linkMu）
+func (s *AuthService) pruneLinkRequests() {
+	ids, err := s.users.LinkRequestIDs()
+	if err != nil {
+		fmt.Printf("警告: 無法列出帳號綁定請求: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		request, err := s.users.GetLinkRequest(id)
+		if err != nil || request == nil || !now.After(request.ExpiresAt) {
+			continue
+		}
+		if err := s.users.DeleteLinkRequest(id); err != nil {
+			fmt.Printf("警告: 無法刪除過期的帳號綁定請求 %s: %v\n", id, err)
+		}
+	}
+}
+
+// GetAccount 取得帳號資訊，匿名使用者返回 nil
+func (s *AuthService) GetAccount(userID string) (*model.Account, error) {
+	if !IsAccountUser(userID) {
+		return nil, nil
+	}
+	return s.users.GetAccount(userID)
+}
+
+// LinkedSubjects 列出使用者帳號在指定身分提供者綁定的識別碼，匿名使用者返回空清單
+func (s *AuthService) LinkedSubjects(userID, provider string) ([]string, error) {
+	if !IsAccountUser(userID) {
+		return nil, nil
+	}
+	return s.users.IdentitySubjects(provider, userID)
+}
+
+// IsAccountUser 判斷使用者 ID 是否為已綁定的帳號
+func IsAccountUser(userID string) bool {
+	return strings.HasPrefix(userID, accountUserPrefix)
+}
+
+// 將使用者綁定到外部身分對應的帳號（不存在時建立），並合併匿名資料
+func (s *AuthService) linkAccount(userID string, identity model.ExternalIdentity) (string, *model.Account, error) {
+	accountID, err := s.users.FindAccountByIdentity(identity.Provider, identity.Subject)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 已登入帳號綁定新的外部身分時，直接附加到目前帳號
+	if accountID == "" && IsAccountUser(userID) {
+		if err := s.users.SaveIdentity(identity.Provider, identity.Subject, userID); err != nil {
+			return "", nil, err
+		}
+		accountID = userID
+	}
+
+	var account *model.Account
+	if accountID != "" {
+		if account, err = s.users.GetAccount(accountID); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if account == nil {
+		id, err := randomHex(12)
+		if err != nil {
+			return "", nil, err
+		}
+		account = &model.Account{
+			ID:        accountUserPrefix + id,
+			Email:     identity.Email,
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			CreatedAt: time.Now(),
+		}
+		if err := s.users.SaveIdentity(identity.Provider, identity.Subject, account.ID); err != nil {
+			return "", nil, err
+		}
+		fmt.Printf("建立帳號 %s (%s)\n", account.ID, identity.Provider)
+	}
+
+	// 匿名使用者：合併資料並記錄裝置綁定
+	if !IsAccountUser(userID) {
+		linked, err := s.users.LinkedAccount(userID)
+		if err != nil {
+			return "", nil, err
+		}
+		if linked != "" && linked != account.ID {
+			return "", nil, ErrAlreadyLinked
+		}
+
+		if err := s.mergeUserData(userID, account.ID); err != nil {
+			return "", nil, err
+		}
+		if err := s.users.LinkDevice(userID, account.ID); err != nil {
+			return "", nil, err
+		}
+		if !slices.Contains(account.LinkedDevices, userID) {
+			account.LinkedDevices = append(account.LinkedDevices, userID)
+		}
+	}
+
+	if err := s.users.SaveAccount(*account); err != nil {
+		return "", nil, err
+	}
+
+	token, err := s.signer.Sign(model.TokenClaims{
+		Subject:  account.ID,
+		Type:     model.TokenTypeAccount,
+		IssuedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, account, nil
+}
+
+func (s *AuthService) mergeUserData(fromUserID, toUserID string) error {
+	s.mergersMu.RLock()
+	defer s.mergersMu.RUnlock()
+
+	for _, merger := range s.mergers {
+		if err := merger.MergeUser(fromUserID, toUserID); err != nil {
+			return fmt.Errorf("無法合併使用者資料: %w", err)
+		}
+	}
+	fmt.Printf("已將匿名使用者 %s 的資料合併到帳號 %s\n", fromUserID, toUserID)
+	return nil
+}
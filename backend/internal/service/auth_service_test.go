@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/infrastructure"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// 記錄寄出的登入連結，取代實際寄信
+type recordingMailer struct {
+	links []string
+}
+
+func (m *recordingMailer) SendMagicLink(email, link string) error {
+	m.links = append(m.links, link)
+	return nil
+}
+
+func newTestAuthService(t *testing.T) (*AuthService, *recordingMailer) {
+	t.Helper()
+	mailer := &recordingMailer{}
+	s := NewAuthService(
+		repository.NewUserRepository(repository.NewMemoryStore()),
+		infrastructure.NewLocalIdentityProvider("http://localhost/api/auth/oauth/callback"),
+		mailer,
+		config.AuthConfig{
+			TokenSecret:  "test-secret",
+			MagicLinkURL: "http://localhost/api/auth/email/verify",
+			MagicLinkTTL: time.Minute,
+		},
+	)
+	return s, mailer
+}
+
+func newDeviceUser(t *testing.T, s *AuthService) (string, string) {
+	t.Helper()
+	token, userID, err := s.IssueDeviceToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token, userID
+}
+
+func queryParam(t *testing.T, rawURL, name string) string {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Query().Get(name)
+}
+
+func TestMagicLinkCompletesOnlyOnOriginatingDevice(t *testing.T) {
+	s, mailer := newTestAuthService(t)
+	aliceToken, alice := newDeviceUser(t, s)
+	_, mallory := newDeviceUser(t, s)
+
+	requestID, err := s.RequestMagicLink(alice, "Alice@Example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	linkToken := queryParam(t, mailer.links[0], "token")
+
+	// 點擊連結的人（可能是收到轉寄連結的他人）只會完成 Email 驗證
+	verified, err := s.VerifyMagicLink(linkToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified != requestID {
+		t.Fatalf("verified request = %s, want %s", verified, requestID)
+	}
+	if linked, _ := s.Authenticate(aliceToken); linked != alice {
+		t.Fatalf("device was linked before confirmation: %s", linked)
+	}
+
+	if _, _, err := s.CompleteLink(mallory, requestID); !errors.Is(err, ErrLinkRequestForbidden) {
+		t.Fatalf("other user completing link: err = %v, want ErrLinkRequestForbidden", err)
+	}
+
+	token, account, err := s.CompleteLink(alice, requestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Email != "alice@example.com" || account.Provider != "email" {
+		t.Errorf("account = %+v", account)
+	}
+	if userID, err := s.Authenticate(token); err != nil || userID != account.ID {
+		t.Errorf("account token resolves to %s (%v), want %s", userID, err, account.ID)
+	}
+	if userID, _ := s.Authenticate(aliceToken); userID != account.ID {
+		t.Errorf("device token resolves to %s, want %s", userID, account.ID)
+	}
+}
+
+func TestMagicLinkIsSingleUse(t *testing.T) {
+	s, mailer := newTestAuthService(t)
+	_, alice := newDeviceUser(t, s)
+
+	requestID, err := s.RequestMagicLink(alice, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	linkToken := queryParam(t, mailer.links[0], "token")
+
+	if _, _, err := s.CompleteLink(alice, requestID); !errors.Is(err, ErrLinkRequestPending) {
+		t.Fatalf("complete before verification: err = %v, want ErrLinkRequestPending", err)
+	}
+	if _, err := s.VerifyMagicLink(linkToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.VerifyMagicLink(linkToken); !errors.Is(err, ErrLinkRequestUsed) {
+		t.Fatalf("second verification: err = %v, want ErrLinkRequestUsed", err)
+	}
+	if _, _, err := s.CompleteLink(alice, requestID); err != nil {
+		t.Fatal(err)
+	}
+
+	// 完成後綁定請求即刪除，連結與請求 ID 都無法再使用
+	if _, _, err := s.CompleteLink(alice, requestID); !errors.Is(err, ErrLinkRequestNotFound) {
+		t.Errorf("second completion: err = %v, want ErrLinkRequestNotFound", err)
+	}
+	if _, err := s.VerifyMagicLink(linkToken); !errors.Is(err, ErrLinkRequestNotFound) {
+		t.Errorf("replayed link: err = %v, want ErrLinkRequestNotFound", err)
+	}
+}
+
+func TestMagicLinkRejectsForeignTokens(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	_, alice := newDeviceUser(t, s)
+
+	// 簽章正確但沒有對應綁定請求的權杖（例如修改前版本發出的連結）
+	orphan, err := s.signer.Sign(tokenClaims(alice, model.TokenTypeMagicLink, time.Minute, "unknown"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.VerifyMagicLink(orphan); !errors.Is(err, ErrLinkRequestNotFound) {
+		t.Errorf("orphan token: err = %v, want ErrLinkRequestNotFound", err)
+	}
+
+	withoutNonce, err := s.signer.Sign(tokenClaims(alice, model.TokenTypeMagicLink, time.Minute, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.VerifyMagicLink(withoutNonce); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token without nonce: err = %v, want ErrInvalidToken", err)
+	}
+
+	if _, err := s.VerifyMagicLink("invalid"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("malformed token: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestOAuthCompletesOnlyOnOriginatingDevice(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	_, alice := newDeviceUser(t, s)
+	_, mallory := newDeviceUser(t, s)
+
+	authURL, requestID, err := s.OAuthURL(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := queryParam(t, authURL, "state")
+	code := queryParam(t, authURL, "code")
+
+	verified, err := s.CompleteOAuth(context.Background(), state, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified != requestID {
+		t.Fatalf("verified request = %s, want %s", verified, requestID)
+	}
+
+	// state 重放無法覆蓋已驗證的身分
+	if _, err := s.CompleteOAuth(context.Background(), state, "mallory:mallory@example.com"); !errors.Is(err, ErrLinkRequestUsed) {
+		t.Fatalf("replayed state: err = %v, want ErrLinkRequestUsed", err)
+	}
+
+	if _, _, err := s.CompleteLink(mallory, requestID); !errors.Is(err, ErrLinkRequestForbidden) {
+		t.Fatalf("other user completing link: err = %v, want ErrLinkRequestForbidden", err)
+	}
+
+	_, account, err := s.CompleteLink(alice, requestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Provider != "local" || account.Subject != "local-user" {
+		t.Errorf("account = %+v, want local-user", account)
+	}
+	if _, _, err := s.CompleteLink(alice, requestID); !errors.Is(err, ErrLinkRequestNotFound) {
+		t.Errorf("second completion: err = %v, want ErrLinkRequestNotFound", err)
+	}
+}
+
+func TestLinkRequestExpires(t *testing.T) {
+	s, mailer := newTestAuthService(t)
+	s.cfg.MagicLinkTTL = -time.Second
+	_, alice := newDeviceUser(t, s)
+
+	requestID, err := s.RequestMagicLink(alice, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.VerifyMagicLink(queryParam(t, mailer.links[0], "token")); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired link: err = %v, want ErrTokenExpired", err)
+	}
+	if _, _, err := s.CompleteLink(alice, requestID); !errors.Is(err, ErrLinkRequestNotFound) {
+		t.Errorf("expired request: err = %v, want ErrLinkRequestNotFound", err)
+	}
+
+	// 建立新請求時會清除已過期的請求
+	s.cfg.MagicLinkTTL = time.Minute
+	if _, err := s.RequestMagicLink(alice, "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if request, _ := s.users.GetLinkRequest(requestID); request != nil {
+		t.Errorf("expired request %s was not pruned", requestID)
+	}
+}
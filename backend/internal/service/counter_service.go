@@ -7,8 +7,12 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+	"what2eat-backend/internal/model"
 )
 
+// ErrDailyLimitExceeded 每日額度已用完
+var ErrDailyLimitExceeded = &model.Error{Kind: model.ErrQuotaExceeded, Message: "免費額度已到，暫停相關推薦功能。"}
+
 type CounterData struct {
 	Count     int       `json:"count"`
 	LastReset time.Time `json:"last_reset"`
@@ -36,8 +40,13 @@ type CounterService struct {
 }
 
 func NewCounterService(dailyLimit int) *CounterService {
+	return NewNamedCounterService("counter", dailyLimit)
+}
+
+// NewNamedCounterService 建立獨立額度的計數器，計數保存在 data/{name}.json，請求日誌與主計數器共用
+func NewNamedCounterService(name string, dailyLimit int) *CounterService {
 	dataDir := "data"
-	dataFile := filepath.Join(dataDir, "counter.json")
+	dataFile := filepath.Join(dataDir, name+".json")
 	apiLogsFile := filepath.Join(dataDir, "api_logs.json")
 
 	// 確保 data 目錄存在
@@ -191,7 +200,7 @@ func (c *CounterService) CheckDailyLimit() error {
 
 	if c.limitExceeded || c.count >= c.dailyLimit {
 		c.limitExceeded = true
-		return fmt.Errorf("免費額度已到，暫停相關推薦功能。")
+		return ErrDailyLimitExceeded
 	}
 
 	return nil
@@ -199,19 +208,26 @@ func (c *CounterService) CheckDailyLimit() error {
 
 // 增加計數並返回當前使用量
 func (c *CounterService) IncrementAndGetUsage() (int, int, error) {
+	return c.IncrementBy(1)
+}
+
+// IncrementBy 一次增加多個計數（如距離矩陣依目的地數量計費），剩餘額度不足時不增加
+func (c *CounterService) IncrementBy(n int) (int, int, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.checkAndReset()
 
 	// 檢查是否超過限制
-	if c.limitExceeded || c.count >= c.dailyLimit {
-		c.limitExceeded = true
-		return c.count, c.dailyLimit, fmt.Errorf("免費額度已到，暫停相關推薦功能。")
+	if c.limitExceeded || c.count+n > c.dailyLimit {
+		if c.count >= c.dailyLimit {
+			c.limitExceeded = true
+		}
+		return c.count, c.dailyLimit, ErrDailyLimitExceeded
 	}
 
 	// 增加計數
-	c.count++
+	c.count += n
 
 	// 檢查增加後是否達到限制
 	if c.count >= c.dailyLimit {
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule 解析後的五欄位 cron 表示式（分 時 日 月 星期）
+type cronSchedule struct {
+	minutes  [60]bool
+	hours    [24]bool
+	days     [32]bool
+	months   [13]bool
+	weekdays [7]bool
+
+	// 日與星期皆有限制時，符合其一即可（與標準 cron 相同）
+	daysRestricted     bool
+	weekdaysRestricted bool
+}
+
+// 各欄位的範圍
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"分鐘", 0, 59},
+	{"小時", 0, 23},
+	{"日期", 1, 31},
+	{"月份", 1, 12},
+	{"星期", 0, 7}, // 0 與 7 皆為星期日
+}
+
+// 往後尋找下一次執行時間的上限，超過表示表示式不可能成立（如 2 月 30 日）
+const cronSearchLimit = 4 * 366 * 24 * time.Hour
+
+// parseCron 解析 cron 表示式，支援 *、數字、範圍 (1-5)、間隔 (*/15、9-17/2) 與清單 (1,3,5)
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron 表示式需為 5 個欄位（分 時 日 月 星期），收到 %d 個", len(fields))
+	}
+
+	schedule := &cronSchedule{}
+	targets := []func(int){
+		func(v int) { schedule.minutes[v] = true },
+		func(v int) { schedule.hours[v] = true },
+		func(v int) { schedule.days[v] = true },
+		func(v int) { schedule.months[v] = true },
+		func(v int) { schedule.weekdays[v%7] = true },
+	}
+
+	for i, field := range fields {
+		if err := parseCronField(field, cronFields[i], targets[i]); err != nil {
+			return nil, err
+		}
+	}
+	schedule.daysRestricted = fields[2] != "*"
+	schedule.weekdaysRestricted = fields[4] != "*"
+	return schedule, nil
+}
+
+func parseCronField(value string, field cronField, set func(int)) error {
+	for _, part := range strings.Split(value, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepText)
+			if err != nil || step <= 0 {
+				return fmt.Errorf("無效的%s間隔 %q", field.name, part)
+			}
+		}
+
+		start, end := field.min, field.max
+		if rangeText != "*" {
+			startText, endText, isRange := strings.Cut(rangeText, "-")
+			var err error
+			if start, err = strconv.Atoi(startText); err != nil {
+				return fmt.Errorf("無效的%s %q", field.name, part)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(endText); err != nil {
+					return fmt.Errorf("無效的%s %q", field.name, part)
+				}
+			} else if hasStep {
+				// "5/15" 表示從 5 開始每 15 個單位
+				end = field.max
+			}
+		}
+
+		if start < field.min || end > field.max || start > end {
+			return fmt.Errorf("%s %q 超出範圍 %d-%d", field.name, part, field.min, field.max)
+		}
+		for v := start; v <= end; v += step {
+			set(v)
+		}
+	}
+	return nil
+}
+
+// Next 返回 after 之後（不含）第一個符合的時間，以 location 的當地時間計算
+func (c *cronSchedule) Next(after time.Time, location *time.Location) (time.Time, bool) {
+	t := after.In(location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if !c.months[t.Month()] {
+			t = forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location))
+			continue
+		}
+		if !c.matchDay(t) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location))
+			continue
+		}
+		if !c.hours[t.Hour()] {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location))
+			continue
+		}
+		if !c.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// 夏令時間開始時部分當地時間不存在，time.Date 正規化後可能不晚於 t（如 02:00 變回 01:00），
+// 此時改為前進到下一個整點，避免停在原地
+func forward(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return t.Add(time.Duration(60-t.Minute()) * time.Minute)
+}
+
+func (c *cronSchedule) matchDay(t time.Time) bool {
+	dayMatch := c.days[t.Day()]
+	weekdayMatch := c.weekdays[t.Weekday()]
+	if c.daysRestricted && c.weekdaysRestricted {
+		return dayMatch || weekdayMatch
+	}
+	return dayMatch && weekdayMatch
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "平日中午", expr: "30 11 * * 1-5"},
+		{name: "間隔與清單", expr: "*/15 9-17/2 1,15 * 0,6"},
+		{name: "起點加間隔", expr: "5/20 * * * *"},
+		{name: "星期日可寫 7", expr: "0 12 * * 7"},
+		{name: "欄位不足", expr: "30 11 * *", wantErr: true},
+		{name: "欄位過多", expr: "30 11 * * 1 2026", wantErr: true},
+		{name: "分鐘超出範圍", expr: "60 11 * * *", wantErr: true},
+		{name: "日期為 0", expr: "0 12 0 * *", wantErr: true},
+		{name: "月份超出範圍", expr: "0 12 * 13 *", wantErr: true},
+		{name: "星期超出範圍", expr: "0 12 * * 8", wantErr: true},
+		{name: "反向範圍", expr: "0 17-9 * * *", wantErr: true},
+		{name: "間隔為 0", expr: "*/0 * * * *", wantErr: true},
+		{name: "非數字", expr: "a 12 * * *", wantErr: true},
+		{name: "空字串", expr: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCron(%q) err = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("時區資料不可用:", err)
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		after    time.Time
+		location *time.Location
+		want     time.Time
+	}{
+		{
+			name: "當天稍後", expr: "30 11 * * 1-5", location: taipei,
+			after: time.Date(2026, 10, 19, 9, 0, 0, 0, taipei), // 星期一
+			want:  time.Date(2026, 10, 19, 11, 30, 0, 0, taipei),
+		},
+		{
+			name: "不含起始時間本身", expr: "30 11 * * 1-5", location: taipei,
+			after: time.Date(2026, 10, 19, 11, 30, 0, 0, taipei),
+			want:  time.Date(2026, 10, 20, 11, 30, 0, 0, taipei),
+		},
+		{
+			name: "週五之後跳到週一", expr: "30 11 * * 1-5", location: taipei,
+			after: time.Date(2026, 10, 23, 12, 0, 0, 0, taipei),
+			want:  time.Date(2026, 10, 26, 11, 30, 0, 0, taipei),
+		},
+		{
+			name: "以 location 的當地時間計算", expr: "0 12 * * *", location: taipei,
+			after: time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC), // 台北 13:00
+			want:  time.Date(2026, 10, 20, 12, 0, 0, 0, taipei),
+		},
+		{
+			name: "跨年", expr: "0 9 1 1 *", location: taipei,
+			after: time.Date(2026, 3, 1, 0, 0, 0, 0, taipei),
+			want:  time.Date(2027, 1, 1, 9, 0, 0, 0, taipei),
+		},
+		{
+			name: "日期與星期符合其一即可", expr: "0 12 1 * 5", location: taipei,
+			after: time.Date(2026, 10, 19, 0, 0, 0, 0, taipei),
+			want:  time.Date(2026, 10, 23, 12, 0, 0, 0, taipei), // 星期五早於 11/1
+		},
+		{
+			name: "2 月 29 日", expr: "0 12 29 2 *", location: taipei,
+			after: time.Date(2026, 3, 1, 0, 0, 0, 0, taipei),
+			want:  time.Date(2028, 2, 29, 12, 0, 0, 0, taipei),
+		},
+		{
+			name: "夏令時間開始當天", expr: "30 11 * * *", location: newYork,
+			after: time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			want:  time.Date(2026, 3, 8, 11, 30, 0, 0, newYork),
+		},
+		{
+			name: "夏令時間跳過的時刻順延到隔天", expr: "30 2 * * *", location: newYork,
+			after: time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			want:  time.Date(2026, 3, 9, 2, 30, 0, 0, newYork),
+		},
+		{
+			name: "間隔", expr: "*/20 12 * * *", location: taipei,
+			after: time.Date(2026, 10, 19, 12, 21, 30, 0, taipei),
+			want:  time.Date(2026, 10, 19, 12, 40, 0, 0, taipei),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := cron.Next(tt.after, tt.location)
+			if !ok || !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, %v, want %s", tt.after.Format(time.RFC3339), got.Format(time.RFC3339), ok, tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+
+	// 不可能成立的日期
+	cron, err := parseCron("0 12 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := cron.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, taipei), taipei); ok {
+		t.Errorf("Next for Feb 30 = %s, want no match", got)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// 多樣性限制名稱，依放寬順序排列（越前面越先被放寬）
+const (
+	ConstraintCuisine = "cuisine" // 料理不重複（指定類型時比較命中的關鍵字）
+	ConstraintSpacing = "spacing" // 推薦餐廳之間的最小距離
+	ConstraintBrand   = "brand"   // 同品牌（連鎖店）不重複
+)
+
+var relaxOrder = []string{ConstraintCuisine, ConstraintSpacing, ConstraintBrand}
+
+var (
+	// 括號內的分店名稱，如「鼎泰豐 (信義店)」
+	bracketPattern = regexp.MustCompile(`[（(【\[].*?[）)】\]]`)
+	// 結尾的分店名稱，如「八方雲集 台北車站店」、「摩斯漢堡-民生店」
+	branchPattern = regexp.MustCompile(`[\s\-－—|｜·・]+[^\s\-－—|｜·・]*(店|館|分店|門市)$`)
+)
+
+// DiversitySelector 從已排序的候選清單中挑出彼此不重複的餐廳
+type DiversitySelector struct {
+	minSpacingMeters float64
+}
+
+func NewDiversitySelector(minSpacingMeters float64) *DiversitySelector {
+	return &DiversitySelector{minSpacingMeters: minSpacingMeters}
+}
+
+// Select 依序挑選符合所有限制的餐廳，候選不足時逐步放寬限制
+// ordered 應已依策略排好優先順序（隨機打亂或加權抽籤的順序）
+func (d *DiversitySelector) Select(ordered []model.Restaurant, count int) ([]model.Restaurant, model.DiversityInfo) {
+	return d.Extend(nil, ordered, count)
+}
+
+// Extend 在已選餐廳之後繼續挑選，直到總數達到 count
+func (d *DiversitySelector) Extend(selected, ordered []model.Restaurant, count int) ([]model.Restaurant, model.DiversityInfo) {
+	info := model.DiversityInfo{}
+	if count > len(selected)+len(ordered) {
+		count = len(selected) + len(ordered)
+	}
+
+	active := map[string]bool{
+		ConstraintCuisine: true,
+		ConstraintSpacing: d.minSpacingMeters > 0,
+		ConstraintBrand:   true,
+	}
+
+	picks := make([]model.Restaurant, 0, count)
+	used := make(map[string]bool, count)
+	for _, r := range selected {
+		picks = append(picks, r)
+		used[r.PlaceID] = true
+	}
+
+	for level := 0; ; level++ {
+		for _, candidate := range ordered {
+			if len(picks) >= count {
+				break
+			}
+			if used[candidate.PlaceID] || !d.compatible(candidate, picks, active) {
+				continue
+			}
+			picks = append(picks, candidate)
+			used[candidate.PlaceID] = true
+		}
+
+		if len(picks) >= count || level >= len(relaxOrder) {
+			break
+		}
+
+		// 候選不足，放寬下一個限制後重新掃描
+		constraint := relaxOrder[level]
+		if active[constraint] {
+			info.Relaxed = true
+			info.RelaxedConstraints = append(info.RelaxedConstraints, constraint)
+		}
+		active[constraint] = false
+	}
+
+	return picks, info
+}
+
+// 檢查候選餐廳是否與已選餐廳衝突
+func (d *DiversitySelector) compatible(candidate model.Restaurant, picks []model.Restaurant, active map[string]bool) bool {
+	brand := NormalizeBrand(candidate.Name)
+	cuisine := diversityCuisine(candidate)
+
+	for _, pick := range picks {
+		if active[ConstraintBrand] && brand != "" && brand == NormalizeBrand(pick.Name) {
+			return false
+		}
+		if active[ConstraintCuisine] && cuisine != "" {
+			if diversityCuisine(pick) == cuisine {
+				return false
+			}
+		}
+		if active[ConstraintSpacing] && hasLocation(candidate) && hasLocation(pick) &&
+			repository.HaversineMeters(candidate.Lat, candidate.Lng, pick.Lat, pick.Lng) < d.minSpacingMeters {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 用於料理限制的分類
+// 一般搜尋比較推測的料理類型，「拉麵」與「壽司」同屬日式料理；
+// 指定類型搜尋時候選都屬於同一類型，改比較命中的關鍵字（與用餐紀錄的 cuisineOf 一致），拉麵與壽司仍會分散
+func diversityCuisine(r model.Restaurant) string {
+	cuisine, keyword := repository.InferCuisine(r.Name)
+	if r.RestaurantType != "" {
+		return keyword
+	}
+	return cuisine
+}
+
+// NormalizeBrand 移除分店名稱、空白與標點，用於判斷是否為同一品牌
+func NormalizeBrand(name string) string {
+	normalized := bracketPattern.ReplaceAllString(name, "")
+	normalized = branchPattern.ReplaceAllString(strings.TrimSpace(normalized), "")
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(normalized) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func hasLocation(r model.Restaurant) bool {
+	return r.Lat != 0 || r.Lng != 0
+}
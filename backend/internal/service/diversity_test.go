@@ -0,0 +1,180 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"what2eat-backend/internal/model"
+)
+
+func TestNormalizeBrand(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"鼎泰豐 (信義店)", "鼎泰豐"},
+		{"鼎泰豐（南西店）", "鼎泰豐"},
+		{"八方雲集 台北車站店", "八方雲集"},
+		{"摩斯漢堡-民生店", "摩斯漢堡"},
+		{"Pizza Hut 必勝客", "pizzahut必勝客"},
+		{"一蘭拉麵", "一蘭拉麵"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeBrand(tt.name); got != tt.want {
+			t.Errorf("NormalizeBrand(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiversityCompatible(t *testing.T) {
+	selector := NewDiversitySelector(100)
+	all := map[string]bool{ConstraintCuisine: true, ConstraintSpacing: true, ConstraintBrand: true}
+	ramen := model.Restaurant{Name: "一蘭拉麵", Lat: 25.0330, Lng: 121.5654}
+
+	tests := []struct {
+		name      string
+		candidate model.Restaurant
+		active    map[string]bool
+		want      bool
+	}{
+		{"同料理不同關鍵字（壽司與拉麵皆為日式）", model.Restaurant{Name: "爭鮮壽司", Lat: 25.0400, Lng: 121.5700}, all, false},
+		{"不同料理", model.Restaurant{Name: "韓式炸雞", Lat: 25.0400, Lng: 121.5700}, all, true},
+		{"無法推測料理", model.Restaurant{Name: "麥當勞", Lat: 25.0400, Lng: 121.5700}, all, true},
+		{"同品牌不同分店", model.Restaurant{Name: "一蘭拉麵 (信義店)", Lat: 25.0400, Lng: 121.5700}, map[string]bool{ConstraintBrand: true}, false},
+		{"距離太近", model.Restaurant{Name: "麥當勞", Lat: 25.0331, Lng: 121.5655}, all, false},
+		{"距離太近但已放寬", model.Restaurant{Name: "麥當勞", Lat: 25.0331, Lng: 121.5655}, map[string]bool{ConstraintBrand: true}, true},
+		{"沒有座標時不檢查距離", model.Restaurant{Name: "麥當勞"}, all, true},
+		{"料理限制已放寬", model.Restaurant{Name: "爭鮮壽司", Lat: 25.0400, Lng: 121.5700}, map[string]bool{ConstraintSpacing: true, ConstraintBrand: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selector.compatible(tt.candidate, []model.Restaurant{ramen}, tt.active); got != tt.want {
+				t.Errorf("compatible = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func placeIDs(restaurants []model.Restaurant) []string {
+	ids := make([]string, len(restaurants))
+	for i, r := range restaurants {
+		ids[i] = r.PlaceID
+	}
+	return ids
+}
+
+// 候選不足時依 cuisine → spacing → brand 的順序逐一放寬，直到挑滿為止
+func TestDiversityRelaxationOrder(t *testing.T) {
+	selector := NewDiversitySelector(100)
+	tests := []struct {
+		name        string
+		ordered     []model.Restaurant
+		wantPicks   []string
+		wantRelaxed []string
+	}{
+		{
+			name: "不需放寬",
+			ordered: []model.Restaurant{
+				{PlaceID: "a", Name: "一蘭拉麵", Lat: 25.000, Lng: 121.500},
+				{PlaceID: "b", Name: "韓式炸雞", Lat: 25.010, Lng: 121.500},
+				{PlaceID: "c", Name: "麻辣鍋", Lat: 25.020, Lng: 121.500},
+			},
+			wantPicks: []string{"a", "b", "c"},
+		},
+		{
+			name: "只放寬料理",
+			ordered: []model.Restaurant{
+				{PlaceID: "a", Name: "一蘭拉麵", Lat: 25.000, Lng: 121.500},
+				{PlaceID: "b", Name: "爭鮮壽司", Lat: 25.010, Lng: 121.500},
+				{PlaceID: "c", Name: "藏壽司", Lat: 25.020, Lng: 121.500},
+			},
+			wantPicks:   []string{"a", "b", "c"},
+			wantRelaxed: []string{ConstraintCuisine},
+		},
+		{
+			name: "放寬料理後再放寬距離",
+			ordered: []model.Restaurant{
+				{PlaceID: "a", Name: "一蘭拉麵", Lat: 25.0000, Lng: 121.5000},
+				{PlaceID: "b", Name: "麥當勞", Lat: 25.0001, Lng: 121.5000},
+				{PlaceID: "c", Name: "爭鮮壽司", Lat: 25.0100, Lng: 121.5000},
+			},
+			wantPicks:   []string{"a", "c", "b"},
+			wantRelaxed: []string{ConstraintCuisine, ConstraintSpacing},
+		},
+		{
+			name: "最後才放寬品牌",
+			ordered: []model.Restaurant{
+				{PlaceID: "a", Name: "八方雲集 民生店", Lat: 25.000, Lng: 121.500},
+				{PlaceID: "b", Name: "八方雲集 信義店", Lat: 25.010, Lng: 121.500},
+				{PlaceID: "c", Name: "八方雲集 大安店", Lat: 25.0001, Lng: 121.500},
+			},
+			wantPicks:   []string{"a", "b", "c"},
+			wantRelaxed: []string{ConstraintCuisine, ConstraintSpacing, ConstraintBrand},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picks, info := selector.Select(tt.ordered, 3)
+			if got := placeIDs(picks); !slices.Equal(got, tt.wantPicks) {
+				t.Errorf("picks = %v, want %v", got, tt.wantPicks)
+			}
+			if !slices.Equal(info.RelaxedConstraints, tt.wantRelaxed) || info.Relaxed != (len(tt.wantRelaxed) > 0) {
+				t.Errorf("relaxed = %v %v, want %v", info.Relaxed, info.RelaxedConstraints, tt.wantRelaxed)
+			}
+		})
+	}
+}
+
+func TestDiversityExtendKeepsSelected(t *testing.T) {
+	selector := NewDiversitySelector(0)
+	selected := []model.Restaurant{{PlaceID: "fav", Name: "一蘭拉麵"}}
+	ordered := []model.Restaurant{
+		{PlaceID: "fav", Name: "一蘭拉麵"},
+		{PlaceID: "x", Name: "爭鮮壽司"},
+		{PlaceID: "y", Name: "韓式炸雞"},
+	}
+
+	picks, _ := selector.Extend(selected, ordered, 2)
+	if got := placeIDs(picks); !slices.Equal(got, []string{"fav", "y"}) {
+		t.Errorf("picks = %v, want [fav y]", got)
+	}
+}
+
+// 指定類型搜尋時所有候選同屬一類，改以命中的關鍵字分散，拉麵與壽司不會被視為重複
+func TestDiversityTypedSearch(t *testing.T) {
+	selector := NewDiversitySelector(0)
+	typed := func(id, name string) model.Restaurant {
+		return model.Restaurant{PlaceID: id, Name: name, RestaurantType: "日式料理"}
+	}
+	tests := []struct {
+		name        string
+		ordered     []model.Restaurant
+		count       int
+		wantPicks   []string
+		wantRelaxed []string
+	}{
+		{
+			name:      "拉麵與壽司分散",
+			ordered:   []model.Restaurant{typed("a", "一蘭拉麵"), typed("b", "屯京拉麵"), typed("c", "爭鮮壽司")},
+			count:     2,
+			wantPicks: []string{"a", "c"},
+		},
+		{
+			name:        "關鍵字不足時才放寬",
+			ordered:     []model.Restaurant{typed("a", "一蘭拉麵"), typed("b", "屯京拉麵"), typed("c", "爭鮮壽司")},
+			count:       3,
+			wantPicks:   []string{"a", "c", "b"},
+			wantRelaxed: []string{ConstraintCuisine},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picks, info := selector.Select(tt.ordered, tt.count)
+			if got := placeIDs(picks); !slices.Equal(got, tt.wantPicks) {
+				t.Errorf("picks = %v, want %v", got, tt.wantPicks)
+			}
+			if !slices.Equal(info.RelaxedConstraints, tt.wantRelaxed) || info.Relaxed != (len(tt.wantRelaxed) > 0) {
+				t.Errorf("relaxed = %v %v, want %v", info.Relaxed, info.RelaxedConstraints, tt.wantRelaxed)
+			}
+		})
+	}
+}
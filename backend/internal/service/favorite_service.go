@@ -0,0 +1,222 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrFavoriteNotFound = &model.Error{Kind: model.ErrNotFound, Message: "找不到收藏的餐廳"}
+	ErrFavoriteExists   = &model.Error{Kind: model.ErrConflict, Message: "餐廳已在收藏清單中"}
+	ErrInvalidFavorite  = &model.Error{Kind: model.ErrInvalidArgument, Message: "收藏資料不完整，至少需要 place_id 與餐廳名稱"}
+	ErrTooManyTags      = &model.Error{Kind: model.ErrInvalidArgument, Message: fmt.Sprintf("每筆收藏的標籤最多 %d 個", maxFavoriteTags)}
+)
+
+// 每筆收藏的標籤數量上限
+const maxFavoriteTags = 10
+
+// FavoriteUpdate 更新收藏的欄位，nil 表示不修改
+type FavoriteUpdate struct {
+	Category *string
+	Note     *string
+	Tags     *[]string
+}
+
+// FavoriteService 管理使用者收藏、備註與標籤
+type FavoriteService struct {
+	repo *repository.FavoriteRepository
+}
+
+func NewFavoriteService(repo *repository.FavoriteRepository) *FavoriteService {
+	return &FavoriteService{repo: repo}
+}
+
+// AddFavorite 新增收藏，未指定分類時使用餐廳類型或由名稱推測，未指定位置時使用餐廳的座標
+func (s *FavoriteService) AddFavorite(favorite model.Favorite) (*model.Favorite, error) {
+	if favorite.PlaceID == "" {
+		favorite.PlaceID = favorite.Restaurant.PlaceID
+	}
+	if favorite.PlaceID == "" || favorite.Restaurant.Name == "" {
+		return nil, ErrInvalidFavorite
+	}
+	favorite.Restaurant.PlaceID = favorite.PlaceID
+
+	existing, err := s.repo.Get(favorite.UserID, favorite.PlaceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrFavoriteExists
+	}
+
+	if favorite.Category == "" {
+		favorite.Category = favorite.Restaurant.RestaurantType
+	}
+	if favorite.Category == "" {
+		favorite.Category, _ = repository.InferCuisine(favorite.Restaurant.Name)
+	}
+	if favorite.Tags, err = normalizeTags(favorite.Tags); err != nil {
+		return nil, err
+	}
+	// 未另外指定位置時使用快照的座標，從推薦結果直接收藏的餐廳也能依距離排序
+	if favorite.Location == nil && hasLocation(favorite.Restaurant) {
+		favorite.Location = &model.Location{Lat: favorite.Restaurant.Lat, Lng: favorite.Restaurant.Lng}
+	}
+
+	// 快照不保存與當次請求相關的欄位
+	favorite.Restaurant.Reasons = nil
+	favorite.Restaurant.Score = 0
+	favorite.Restaurant.ParticipantDistances = nil
+
+	now := time.Now()
+	favorite.CreatedAt = now
+	favorite.UpdatedAt = now
+
+	if err := s.repo.Save(favorite); err != nil {
+		return nil, fmt.Errorf("無法保存收藏: %w", err)
+	}
+	return &favorite, nil
+}
+
+// GetFavorite 取得單一收藏
+func (s *FavoriteService) GetFavorite(userID, placeID string) (*model.Favorite, error) {
+	favorite, err := s.repo.Get(userID, placeID)
+	if err != nil {
+		return nil, err
+	}
+	if favorite == nil {
+		return nil, ErrFavoriteNotFound
+	}
+	return favorite, nil
+}
+
+// UpdateFavorite 更新收藏的分類、備註或標籤
+func (s *FavoriteService) UpdateFavorite(userID, placeID string, update FavoriteUpdate) (*model.Favorite, error) {
+	favorite, err := s.GetFavorite(userID, placeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Category != nil {
+		favorite.Category = strings.TrimSpace(*update.Category)
+	}
+	if update.Note != nil {
+		favorite.Note = *update.Note
+	}
+	if update.Tags != nil {
+		if favorite.Tags, err = normalizeTags(*update.Tags); err != nil {
+			return nil, err
+		}
+	}
+	favorite.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(*favorite); err != nil {
+		return nil, fmt.Errorf("無法保存收藏: %w", err)
+	}
+	return favorite, nil
+}
+
+// DeleteFavorite 移除收藏
+func (s *FavoriteService) DeleteFavorite(userID, placeID string) error {
+	if _, err := s.GetFavorite(userID, placeID); err != nil {
+		return err
+	}
+	return s.repo.Delete(userID, placeID)
+}
+
+// ListFavorites 依條件列出收藏，提供位置時依距離排序，否則依收藏時間由新到舊
+func (s *FavoriteService) ListFavorites(userID string, filter model.FavoriteFilter) ([]model.Favorite, error) {
+	favorites, err := s.repo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]model.Favorite, 0, len(favorites))
+	for _, favorite := range favorites {
+		if filter.Category != "" && favorite.Category != filter.Category {
+			continue
+		}
+		if filter.Tag != "" && !containsTag(favorite.Tags, filter.Tag) {
+			continue
+		}
+
+		if filter.Origin != nil && favorite.Location != nil {
+			distance := repository.HaversineMeters(filter.Origin.Lat, filter.Origin.Lng, favorite.Location.Lat, favorite.Location.Lng)
+			favorite.DistanceMeters = &distance
+			favorite.Restaurant.DistanceMeters = distance
+			favorite.Restaurant.Distance = repository.FormatDistance(distance)
+		}
+		filtered = append(filtered, favorite)
+	}
+
+	if filter.Origin != nil {
+		// 沒有位置資訊的收藏排在最後
+		sort.SliceStable(filtered, func(i, j int) bool {
+			di, dj := filtered[i].DistanceMeters, filtered[j].DistanceMeters
+			if di == nil || dj == nil {
+				return di != nil
+			}
+			return *di < *dj
+		})
+	} else {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+		})
+	}
+
+	return filtered, nil
+}
+
+// MergeUser 將匿名使用者的收藏合併到帳號，帳號已有的收藏保留帳號版本
+func (s *FavoriteService) MergeUser(fromUserID, toUserID string) error {
+	favorites, err := s.repo.List(fromUserID)
+	if err != nil {
+		return err
+	}
+
+	for _, favorite := range favorites {
+		existing, err := s.repo.Get(toUserID, favorite.PlaceID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			favorite.UserID = toUserID
+			if err := s.repo.Save(favorite); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Delete(fromUserID, favorite.PlaceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 去除空白與重複的標籤
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || containsTag(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxFavoriteTags {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
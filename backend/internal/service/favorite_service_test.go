@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+func newTestFavoriteService() *FavoriteService {
+	return NewFavoriteService(repository.NewFavoriteRepository(repository.NewMemoryStore()))
+}
+
+func TestAddFavoriteLocation(t *testing.T) {
+	tests := []struct {
+		name       string
+		restaurant model.Restaurant
+		location   *model.Location
+		want       *model.Location
+	}{
+		{
+			name:       "使用快照的座標",
+			restaurant: model.Restaurant{PlaceID: "a", Name: "一蘭拉麵", Lat: 25.034, Lng: 121.566},
+			want:       &model.Location{Lat: 25.034, Lng: 121.566},
+		},
+		{
+			name:       "指定的位置優先",
+			restaurant: model.Restaurant{PlaceID: "a", Name: "一蘭拉麵", Lat: 25.034, Lng: 121.566},
+			location:   &model.Location{Lat: 25.040, Lng: 121.550},
+			want:       &model.Location{Lat: 25.040, Lng: 121.550},
+		},
+		{
+			name:       "快照沒有座標",
+			restaurant: model.Restaurant{PlaceID: "a", Name: "一蘭拉麵"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestFavoriteService()
+			if _, err := s.AddFavorite(model.Favorite{UserID: "u1", Restaurant: tt.restaurant, Location: tt.location}); err != nil {
+				t.Fatal(err)
+			}
+
+			favorites, err := s.ListFavorites("u1", model.FavoriteFilter{Origin: &model.Location{Lat: 25.033, Lng: 121.565}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := favorites[0]
+			if tt.want == nil {
+				if got.Location != nil || got.DistanceMeters != nil {
+					t.Errorf("location = %v, distance = %v, want none", got.Location, got.DistanceMeters)
+				}
+				return
+			}
+			if got.Location == nil || *got.Location != *tt.want {
+				t.Errorf("location = %v, want %v", got.Location, tt.want)
+			}
+			if got.DistanceMeters == nil {
+				t.Error("distance is missing")
+			}
+		})
+	}
+}
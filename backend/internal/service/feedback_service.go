@@ -0,0 +1,382 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrFeedbackNotFound = &model.Error{Kind: model.ErrNotFound, Message: "找不到回饋紀錄"}
+	ErrInvalidFeedback  = &model.Error{Kind: model.ErrInvalidArgument, Message: "無效的回饋類型，僅支援 like、dislike 或 never_again"}
+)
+
+// 各回饋類型對料理與價格偏好分數的貢獻
+var feedbackWeights = map[string]float64{
+	model.FeedbackLike:       1,
+	model.FeedbackDislike:    -1,
+	model.FeedbackNeverAgain: -2,
+}
+
+// 對單一餐廳回饋時，該餐廳推薦權重的倍率（never_again 直接排除）
+var placeMultipliers = map[string]float64{
+	model.FeedbackLike:    1.5,
+	model.FeedbackDislike: 0.25,
+}
+
+// 偏好分數達此值（約按讚兩次）才在推薦理由中提及
+const likedCuisineAffinity = 0.75
+
+// FeedbackService 管理使用者回饋，並維護由回饋累積出的偏好檔案
+type FeedbackService struct {
+	repo        *repository.FeedbackRepository
+	favorites   *FavoriteService
+	restaurants *repository.RestaurantRepository
+	weight      float64
+
+	// 偏好檔案為讀取後修改再寫回，需序列化更新
+	mu sync.Mutex
+}
+
+func NewFeedbackService(repo *repository.FeedbackRepository, favorites *FavoriteService, restaurants *repository.RestaurantRepository, cfg config.FeedbackConfig) *FeedbackService {
+	return &FeedbackService{
+		repo:        repo,
+		favorites:   favorites,
+		restaurants: restaurants,
+		weight:      cfg.PreferenceWeight,
+	}
+}
+
+// SubmitFeedback 新增或更新對餐廳的回饋，並增量更新偏好檔案
+func (s *FeedbackService) SubmitFeedback(userID, placeID, kind string, snapshot *model.Restaurant) (*model.Feedback, *model.PreferenceProfile, error) {
+	if _, ok := feedbackWeights[kind]; !ok {
+		return nil, nil, ErrInvalidFeedback
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.repo.Get(userID, placeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var restaurant model.Restaurant
+	if snapshot == nil && existing != nil {
+		restaurant = existing.Restaurant
+	} else if restaurant, err = resolveRestaurant(s.favorites, s.restaurants, userID, placeID, snapshot); err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := s.loadProfile(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	item := model.Feedback{
+		UserID:     userID,
+		PlaceID:    placeID,
+		Kind:       kind,
+		Restaurant: restaurant,
+		Cuisine:    cuisineOf(restaurant),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	// 先扣除舊回饋的影響，再加上新回饋
+	if existing != nil {
+		item.CreatedAt = existing.CreatedAt
+		applyFeedback(profile, *existing, -1)
+	}
+	applyFeedback(profile, item, 1)
+	profile.UpdatedAt = now
+
+	if err := s.repo.Save(item); err != nil {
+		return nil, nil, fmt.Errorf("無法保存回饋: %w", err)
+	}
+	if err := s.repo.SaveProfile(userID, *profile); err != nil {
+		return nil, nil, fmt.Errorf("無法保存偏好檔案: %w", err)
+	}
+
+	fmt.Printf("使用者 %s 對 %s 的回饋: %s (%s)\n", userID, restaurant.Name, kind, item.Cuisine)
+	return &item, profile, nil
+}
+
+// DeleteFeedback 撤回對餐廳的回饋，並從偏好檔案中扣除其影響
+func (s *FeedbackService) DeleteFeedback(userID, placeID string) (*model.PreferenceProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.repo.Get(userID, placeID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrFeedbackNotFound
+	}
+
+	profile, err := s.loadProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	applyFeedback(profile, *existing, -1)
+	profile.UpdatedAt = time.Now()
+
+	if err := s.repo.Delete(userID, placeID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveProfile(userID, *profile); err != nil {
+		return nil, fmt.Errorf("無法保存偏好檔案: %w", err)
+	}
+	return profile, nil
+}
+
+// ListFeedback 列出使用者的回饋，依更新時間由新到舊排序
+func (s *FeedbackService) ListFeedback(userID string) ([]model.Feedback, error) {
+	feedback, err := s.repo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(feedback, func(i, j int) bool {
+		return feedback[i].UpdatedAt.After(feedback[j].UpdatedAt)
+	})
+	return feedback, nil
+}
+
+// GetProfile 取得偏好檔案，沒有任何回饋時返回空的檔案
+func (s *FeedbackService) GetProfile(userID string) (*model.PreferenceProfile, error) {
+	return s.loadProfile(userID)
+}
+
+// ResetProfile 清除回饋與偏好檔案，keepBlocked 為 true 時保留「不再推薦」的餐廳
+func (s *FeedbackService) ResetProfile(userID string, keepBlocked bool) (*model.PreferenceProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feedback, err := s.repo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []model.Feedback
+	for _, item := range feedback {
+		if keepBlocked && item.Kind == model.FeedbackNeverAgain {
+			kept = append(kept, item)
+			continue
+		}
+		if err := s.repo.Delete(userID, item.PlaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	profile := rebuildProfile(kept)
+	if len(kept) == 0 {
+		if err := s.repo.DeleteProfile(userID); err != nil {
+			return nil, err
+		}
+	} else if err := s.repo.SaveProfile(userID, *profile); err != nil {
+		return nil, fmt.Errorf("無法保存偏好檔案: %w", err)
+	}
+
+	fmt.Printf("使用者 %s 重設偏好檔案，保留 %d 筆回饋\n", userID, len(kept))
+	return profile, nil
+}
+
+// MergeUser 將匿名使用者的回饋合併到帳號，同一家餐廳保留帳號的回饋，並重新計算偏好檔案
+func (s *FeedbackService) MergeUser(fromUserID, toUserID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feedback, err := s.repo.List(fromUserID)
+	if err != nil {
+		return err
+	}
+	if len(feedback) == 0 {
+		return nil
+	}
+
+	for _, item := range feedback {
+		existing, err := s.repo.Get(toUserID, item.PlaceID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			item.UserID = toUserID
+			if err := s.repo.Save(item); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Delete(fromUserID, item.PlaceID); err != nil {
+			return err
+		}
+	}
+
+	merged, err := s.repo.List(toUserID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SaveProfile(toUserID, *rebuildProfile(merged)); err != nil {
+		return err
+	}
+	return s.repo.DeleteProfile(fromUserID)
+}
+
+// Personalization 取得使用者的偏好調整，沒有回饋時返回 nil
+func (s *FeedbackService) Personalization(userID string) (*Personalization, error) {
+	if userID == "" {
+		return nil, nil
+	}
+
+	feedback, err := s.repo.List(userID)
+	if err != nil {
+		return nil, fmt.Errorf("無法取得使用者回饋: %w", err)
+	}
+	if len(feedback) == 0 {
+		return nil, nil
+	}
+
+	profile, err := s.loadProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	places := make(map[string]string, len(feedback))
+	for _, item := range feedback {
+		places[item.PlaceID] = item.Kind
+	}
+	return &Personalization{profile: *profile, places: places, weight: s.weight}, nil
+}
+
+func (s *FeedbackService) loadProfile(userID string) (*model.PreferenceProfile, error) {
+	profile, err := s.repo.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &model.PreferenceProfile{}
+	}
+	if profile.Cuisines == nil {
+		profile.Cuisines = make(map[string]float64)
+	}
+	if profile.PriceLevels == nil {
+		profile.PriceLevels = make(map[string]float64)
+	}
+	return profile, nil
+}
+
+// 將一筆回饋的影響加入（sign = 1）或扣除（sign = -1）偏好檔案
+func applyFeedback(profile *model.PreferenceProfile, item model.Feedback, sign float64) {
+	weight := feedbackWeights[item.Kind] * sign
+
+	if item.Cuisine != "" {
+		addScore(profile.Cuisines, item.Cuisine, weight)
+	}
+	if item.Restaurant.PriceLevel > 0 {
+		addScore(profile.PriceLevels, strconv.Itoa(item.Restaurant.PriceLevel), weight)
+	}
+
+	switch item.Kind {
+	case model.FeedbackLike:
+		profile.Likes += int(sign)
+	case model.FeedbackDislike:
+		profile.Dislikes += int(sign)
+	case model.FeedbackNeverAgain:
+		profile.NeverAgain += int(sign)
+	}
+}
+
+// 累加分數，歸零時移除該項目
+func addScore(scores map[string]float64, key string, delta float64) {
+	scores[key] += delta
+	if math.Abs(scores[key]) < 1e-9 {
+		delete(scores, key)
+	}
+}
+
+// 由全部回饋重新計算偏好檔案
+func rebuildProfile(feedback []model.Feedback) *model.PreferenceProfile {
+	profile := &model.PreferenceProfile{
+		Cuisines:    make(map[string]float64),
+		PriceLevels: make(map[string]float64),
+		UpdatedAt:   time.Now(),
+	}
+	for _, item := range feedback {
+		applyFeedback(profile, item, 1)
+	}
+	return profile
+}
+
+// Personalization 依使用者偏好過濾與調整候選餐廳的權重，nil 表示不調整
+type Personalization struct {
+	profile model.PreferenceProfile
+	places  map[string]string // place_id → 回饋類型
+	weight  float64
+}
+
+// Filter 排除使用者標記為「不再推薦」的餐廳
+func (p *Personalization) Filter(candidates []model.Restaurant) []model.Restaurant {
+	if p == nil {
+		return candidates
+	}
+
+	filtered := make([]model.Restaurant, 0, len(candidates))
+	for _, r := range candidates {
+		if p.places[r.PlaceID] == model.FeedbackNeverAgain {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// Multiplier 計算餐廳推薦權重的倍率，1 表示不調整
+func (p *Personalization) Multiplier(r model.Restaurant) float64 {
+	if p == nil {
+		return 1
+	}
+
+	multiplier := 1 + p.weight*p.cuisineAffinity(r)
+	if r.PriceLevel > 0 {
+		multiplier *= 1 + p.weight*affinity(p.profile.PriceLevels[strconv.Itoa(r.PriceLevel)])
+	}
+	if placeMultiplier, ok := placeMultipliers[p.places[r.PlaceID]]; ok {
+		multiplier *= placeMultiplier
+	}
+	return math.Max(multiplier, 0)
+}
+
+// Explain 產生偏好階段的推薦理由（按過讚、喜歡的料理）
+func (p *Personalization) Explain(r model.Restaurant) []model.Reason {
+	if p == nil {
+		return nil
+	}
+
+	if p.places[r.PlaceID] == model.FeedbackLike {
+		return []model.Reason{{Code: "you_liked", Stage: model.StagePreference}}
+	}
+	if p.cuisineAffinity(r) >= likedCuisineAffinity {
+		return []model.Reason{{
+			Code:   "likes_cuisine",
+			Stage:  model.StagePreference,
+			Params: map[string]string{"cuisine": cuisineOf(r)},
+		}}
+	}
+	return nil
+}
+
+func (p *Personalization) cuisineAffinity(r model.Restaurant) float64 {
+	return affinity(p.profile.Cuisines[cuisineOf(r)])
+}
+
+// 將累積分數壓縮到 -1 ~ 1，避免單一料理的大量回饋主導推薦
+func affinity(score float64) float64 {
+	return math.Tanh(score / 2)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"testing"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+func newTestFeedbackService(weight float64) *FeedbackService {
+	repo := repository.NewFeedbackRepository(repository.NewMemoryStore())
+	return NewFeedbackService(repo, nil, nil, config.FeedbackConfig{PreferenceWeight: weight})
+}
+
+var (
+	ramenShop = model.Restaurant{PlaceID: "ramen", Name: "一蘭拉麵", PriceLevel: 2}
+	ramenBar  = model.Restaurant{PlaceID: "ramen-2", Name: "鷹流拉麵", PriceLevel: 3}
+	hotpot    = model.Restaurant{PlaceID: "hotpot", Name: "老四川麻辣鍋", PriceLevel: 2}
+	noPrice   = model.Restaurant{PlaceID: "noodles", Name: "巷口牛肉麵"}
+)
+
+func TestFeedbackProfileUpdates(t *testing.T) {
+	type step struct {
+		restaurant model.Restaurant
+		kind       string // 空字串表示撤回回饋
+	}
+
+	tests := []struct {
+		name         string
+		steps        []step
+		wantCuisines map[string]float64
+		wantPrices   map[string]float64
+		wantCounts   [3]int // likes, dislikes, never_again
+	}{
+		{
+			name:         "按讚累加料理與價格",
+			steps:        []step{{ramenShop, model.FeedbackLike}, {ramenBar, model.FeedbackLike}},
+			wantCuisines: map[string]float64{"拉麵": 2},
+			wantPrices:   map[string]float64{"2": 1, "3": 1},
+			wantCounts:   [3]int{2, 0, 0},
+		},
+		{
+			name:         "不再推薦的權重較重",
+			steps:        []step{{ramenShop, model.FeedbackLike}, {hotpot, model.FeedbackNeverAgain}},
+			wantCuisines: map[string]float64{"拉麵": 1, "麻辣鍋": -2},
+			wantPrices:   map[string]float64{"2": -1},
+			wantCounts:   [3]int{1, 0, 1},
+		},
+		{
+			name:         "更改回饋時扣除舊回饋",
+			steps:        []step{{ramenShop, model.FeedbackLike}, {ramenShop, model.FeedbackDislike}},
+			wantCuisines: map[string]float64{"拉麵": -1},
+			wantPrices:   map[string]float64{"2": -1},
+			wantCounts:   [3]int{0, 1, 0},
+		},
+		{
+			name:         "相同回饋重複送出不重複計算",
+			steps:        []step{{ramenShop, model.FeedbackLike}, {ramenShop, model.FeedbackLike}},
+			wantCuisines: map[string]float64{"拉麵": 1},
+			wantPrices:   map[string]float64{"2": 1},
+			wantCounts:   [3]int{1, 0, 0},
+		},
+		{
+			name:         "撤回後分數歸零即移除",
+			steps:        []step{{ramenShop, model.FeedbackLike}, {hotpot, model.FeedbackDislike}, {ramenShop, ""}},
+			wantCuisines: map[string]float64{"麻辣鍋": -1},
+			wantPrices:   map[string]float64{"2": -1},
+			wantCounts:   [3]int{0, 1, 0},
+		},
+		{
+			name:         "未知價格不影響價格偏好",
+			steps:        []step{{noPrice, model.FeedbackLike}},
+			wantCuisines: map[string]float64{"牛肉": 1},
+			wantPrices:   map[string]float64{},
+			wantCounts:   [3]int{1, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestFeedbackService(0.5)
+			var profile *model.PreferenceProfile
+			var err error
+			for _, st := range tt.steps {
+				if st.kind == "" {
+					profile, err = s.DeleteFeedback("user", st.restaurant.PlaceID)
+				} else {
+					snapshot := st.restaurant
+					_, profile, err = s.SubmitFeedback("user", st.restaurant.PlaceID, st.kind, &snapshot)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !maps.Equal(profile.Cuisines, tt.wantCuisines) {
+				t.Errorf("cuisines = %v, want %v", profile.Cuisines, tt.wantCuisines)
+			}
+			if !maps.Equal(profile.PriceLevels, tt.wantPrices) {
+				t.Errorf("price levels = %v, want %v", profile.PriceLevels, tt.wantPrices)
+			}
+			if counts := [3]int{profile.Likes, profile.Dislikes, profile.NeverAgain}; counts != tt.wantCounts {
+				t.Errorf("counts = %v, want %v", counts, tt.wantCounts)
+			}
+
+			// 增量更新的結果應與由全部回饋重新計算相同
+			feedback, err := s.ListFeedback("user")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rebuilt := rebuildProfile(feedback)
+			if !maps.Equal(rebuilt.Cuisines, profile.Cuisines) || !maps.Equal(rebuilt.PriceLevels, profile.PriceLevels) {
+				t.Errorf("incremental profile %v/%v differs from rebuilt %v/%v",
+					profile.Cuisines, profile.PriceLevels, rebuilt.Cuisines, rebuilt.PriceLevels)
+			}
+
+			stored, err := s.GetProfile("user")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(stored.Cuisines, profile.Cuisines) {
+				t.Errorf("stored cuisines = %v, want %v", stored.Cuisines, profile.Cuisines)
+			}
+		})
+	}
+}
+
+func TestFeedbackErrors(t *testing.T) {
+	s := newTestFeedbackService(0.5)
+	snapshot := ramenShop
+	if _, _, err := s.SubmitFeedback("user", snapshot.PlaceID, "love", &snapshot); !errors.Is(err, ErrInvalidFeedback) {
+		t.Errorf("invalid kind: err = %v, want ErrInvalidFeedback", err)
+	}
+	if _, err := s.DeleteFeedback("user", "missing"); !errors.Is(err, ErrFeedbackNotFound) {
+		t.Errorf("missing feedback: err = %v, want ErrFeedbackNotFound", err)
+	}
+}
+
+func TestFeedbackResetKeepsBlocked(t *testing.T) {
+	s := newTestFeedbackService(0.5)
+	for _, item := range []struct {
+		restaurant model.Restaurant
+		kind       string
+	}{{ramenShop, model.FeedbackLike}, {hotpot, model.FeedbackNeverAgain}} {
+		snapshot := item.restaurant
+		if _, _, err := s.SubmitFeedback("user", snapshot.PlaceID, item.kind, &snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	profile, err := s.ResetProfile("user", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(profile.Cuisines, map[string]float64{"麻辣鍋": -2}) || profile.Likes != 0 || profile.NeverAgain != 1 {
+		t.Errorf("profile = %+v, want only the blocked hot pot", profile)
+	}
+
+	personalization, err := s.Personalization("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := personalization.Filter([]model.Restaurant{ramenShop, hotpot}); len(got) != 1 || got[0].PlaceID != ramenShop.PlaceID {
+		t.Errorf("filtered = %v, want only ramen", placeIDs(got))
+	}
+}
+
+func TestPersonalizationMultiplier(t *testing.T) {
+	s := newTestFeedbackService(0.5)
+	for _, r := range []model.Restaurant{ramenShop, ramenBar} {
+		snapshot := r
+		if _, _, err := s.SubmitFeedback("user", r.PlaceID, model.FeedbackLike, &snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshot := hotpot
+	if _, _, err := s.SubmitFeedback("user", hotpot.PlaceID, model.FeedbackDislike, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := s.Personalization("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 拉麵 +2、麻辣鍋 -1；價格 "2" 為 +1 -1 = 0（已移除）、"3" 為 +1
+	otherRamen := model.Restaurant{PlaceID: "ramen-3", Name: "博多拉麵", PriceLevel: 3}
+	tests := []struct {
+		name       string
+		restaurant model.Restaurant
+		want       float64
+	}{
+		{"按讚過的餐廳", ramenShop, (1 + 0.5*math.Tanh(1)) * 1.5},
+		{"喜歡的料理", otherRamen, (1 + 0.5*math.Tanh(1)) * (1 + 0.5*math.Tanh(0.5))},
+		{"不喜歡的餐廳", hotpot, (1 + 0.5*math.Tanh(-0.5)) * 0.25},
+		{"沒有偏好", model.Restaurant{PlaceID: "x", Name: "早午餐店"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Multiplier(tt.restaurant); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("multiplier = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+
+	if reasons := p.Explain(otherRamen); len(reasons) != 1 || reasons[0].Code != "likes_cuisine" {
+		t.Errorf("reasons = %v, want likes_cuisine", reasons)
+	}
+	if reasons := p.Explain(ramenShop); len(reasons) != 1 || reasons[0].Code != "you_liked" {
+		t.Errorf("reasons = %v, want you_liked", reasons)
+	}
+
+	var nilPersonalization *Personalization
+	if got := nilPersonalization.Multiplier(ramenShop); got != 1 {
+		t.Errorf("nil personalization multiplier = %v, want 1", got)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrLocationNotFound     = &model.Error{Kind: model.ErrNoResults, Message: "找不到符合的地點"}
+	ErrInvalidLocationQuery = &model.Error{Kind: model.ErrInvalidLocation, Message: "地點需為 1-100 字的地址或地標名稱"}
+	ErrGeocodeLimitExceeded = &model.Error{Kind: model.ErrQuotaExceeded, Message: "地點解析的每日額度已用完，請改用經緯度查詢"}
+)
+
+const (
+	maxLocationQueryLength = 100
+	// 查無地點的結果也快取，避免重複查詢浪費額度
+	geocodeNegativeTTL = 24 * time.Hour
+	// 呼叫外部地點解析服務的逾時
+	geocodeTimeout = 5 * time.Second
+)
+
+// Geocoder 將地址或地標文字解析為座標，查無結果時返回 nil
+type Geocoder interface {
+	Name() string
+	Geocode(ctx context.Context, query string) (*model.ResolvedLocation, error)
+}
+
+// GeocodeService 依序以本地地名資料與外部服務解析地點
+// 外部服務的結果長時間快取，並計入獨立的每日額度
+type GeocodeService struct {
+	gazetteer Geocoder
+	remote    Geocoder // nil 表示只使用本地地名資料
+	repo      *repository.GeocodeRepository
+	counter   *CounterService
+	cfg       config.GeocodeConfig
+}
+
+func NewGeocodeService(gazetteer, remote Geocoder, repo *repository.GeocodeRepository, counter *CounterService, cfg config.GeocodeConfig) *GeocodeService {
+	return &GeocodeService{
+		gazetteer: gazetteer,
+		remote:    remote,
+		repo:      repo,
+		counter:   counter,
+		cfg:       cfg,
+	}
+}
+
+// Resolve 解析地址或地標
+func (s *GeocodeService) Resolve(ctx context.Context, query string) (*model.ResolvedLocation, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxLocationQueryLength {
+		return nil, ErrInvalidLocationQuery
+	}
+
+	// 本地地名資料優先，不需要網路也不計入額度
+	location, err := s.gazetteer.Geocode(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if location != nil {
+		return location, nil
+	}
+
+	if s.remote == nil {
+		return nil, ErrLocationNotFound
+	}
+
+	key := repository.NormalizePlaceName(query)
+	cached, found, err := s.repo.Get(key)
+	if err != nil {
+		fmt.Printf("警告: 無法讀取地點快取: %v\n", err)
+	}
+	if found {
+		if cached == nil {
+			return nil, ErrLocationNotFound
+		}
+		result := *cached
+		result.Query = query
+		result.Cached = true
+		return &result, nil
+	}
+
+	if _, _, err := s.counter.IncrementAndGetUsage(); err != nil {
+		return nil, ErrGeocodeLimitExceeded
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, geocodeTimeout)
+	defer cancel()
+	location, err = s.remote.Geocode(ctx, query)
+	if err != nil {
+		s.counter.LogAPIRequest("geocode", 0, 0, "", false, err.Error())
+		return nil, err
+	}
+
+	if location == nil {
+		s.counter.LogAPIRequest("geocode", 0, 0, "", true, "")
+		if err := s.repo.Save(key, nil, geocodeNegativeTTL); err != nil {
+			fmt.Printf("警告: 無法保存地點快取: %v\n", err)
+		}
+		return nil, ErrLocationNotFound
+	}
+
+	s.counter.LogAPIRequest("geocode", location.Lat, location.Lng, "", true, "")
+	if err := s.repo.Save(key, location, s.cfg.CacheTTL); err != nil {
+		fmt.Printf("警告: 無法保存地點快取: %v\n", err)
+	}
+	fmt.Printf("地點解析: %q → [%.4f, %.4f] %s\n", query, location.Lat, location.Lng, location.Address)
+	return location, nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrMealNotFound   = &model.Error{Kind: model.ErrNotFound, Message: "找不到用餐紀錄"}
+	ErrUnknownPlace   = &model.Error{Kind: model.ErrInvalidArgument, Message: "找不到餐廳資料，請一併提供餐廳名稱"}
+	ErrInvalidEatenAt = &model.Error{Kind: model.ErrInvalidArgument, Message: "用餐時間不能晚於現在"}
+)
+
+// HistoryService 管理用餐紀錄，並讓推薦避開最近吃過的餐廳與料理
+type HistoryService struct {
+	repo        *repository.HistoryRepository
+	favorites   *FavoriteService
+	restaurants *repository.RestaurantRepository
+	cfg         config.HistoryConfig
+	location    *time.Location
+}
+
+func NewHistoryService(repo *repository.HistoryRepository, favorites *FavoriteService, restaurants *repository.RestaurantRepository, cfg config.HistoryConfig) *HistoryService {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		// 如果無法加載時區，使用固定的+8小時偏移（台灣時間）
+		fmt.Printf("警告: 無法載入時區 %s: %v，使用固定偏移\n", cfg.Timezone, err)
+		location = time.FixedZone("CST", 8*60*60)
+	}
+
+	return &HistoryService{
+		repo:        repo,
+		favorites:   favorites,
+		restaurants: restaurants,
+		cfg:         cfg,
+		location:    location,
+	}
+}
+
+// RecordMeal 記錄一次用餐，未提供餐廳資料時從收藏或搜尋緩存中查找
+func (s *HistoryService) RecordMeal(userID, placeID string, snapshot *model.Restaurant, eatenAt time.Time) (*model.MealRecord, error) {
+	now := time.Now()
+	if eatenAt.IsZero() {
+		eatenAt = now
+	} else if eatenAt.After(now.Add(time.Minute)) {
+		return nil, ErrInvalidEatenAt
+	}
+
+	restaurant, err := resolveRestaurant(s.favorites, s.restaurants, userID, placeID, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	record := model.MealRecord{
+		ID:         id,
+		UserID:     userID,
+		PlaceID:    placeID,
+		Restaurant: restaurant,
+		Cuisine:    cuisineOf(restaurant),
+		EatenAt:    eatenAt,
+	}
+	if err := s.repo.Save(record); err != nil {
+		return nil, fmt.Errorf("無法保存用餐紀錄: %w", err)
+	}
+
+	fmt.Printf("使用者 %s 記錄用餐: %s (%s)\n", userID, restaurant.Name, record.Cuisine)
+	return &record, nil
+}
+
+// DeleteMeal 刪除用餐紀錄
+func (s *HistoryService) DeleteMeal(userID, recordID string) error {
+	record, err := s.repo.Get(userID, recordID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrMealNotFound
+	}
+	return s.repo.Delete(userID, recordID)
+}
+
+// ListMeals 列出指定時間之後的用餐紀錄，由新到舊排序，since 為零值時列出全部
+func (s *HistoryService) ListMeals(userID string, since time.Time) ([]model.MealRecord, error) {
+	records, err := s.repo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]model.MealRecord, 0, len(records))
+	for _, record := range records {
+		if record.EatenAt.Before(since) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].EatenAt.After(filtered[j].EatenAt)
+	})
+	return filtered, nil
+}
+
+// RecentHistory 取得最近幾週（含本週）的用餐紀錄與每週摘要，皆由新到舊排序
+func (s *HistoryService) RecentHistory(userID string, weeks int) ([]model.MealRecord, []model.WeeklySummary, error) {
+	if weeks <= 0 {
+		weeks = 1
+	}
+
+	currentWeek := s.weekStart(time.Now())
+	records, err := s.ListMeals(userID, currentWeek.AddDate(0, 0, -7*(weeks-1)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, s.summarize(records, currentWeek, weeks), nil
+}
+
+// 統計每週的用餐次數與料理分佈
+func (s *HistoryService) summarize(records []model.MealRecord, currentWeek time.Time, weeks int) []model.WeeklySummary {
+	type bucket struct {
+		meals    int
+		places   map[string]bool
+		cuisines map[string]int
+	}
+	buckets := make([]bucket, weeks)
+	for i := range buckets {
+		buckets[i] = bucket{places: make(map[string]bool), cuisines: make(map[string]int)}
+	}
+
+	for _, record := range records {
+		index := int(math.Round(currentWeek.Sub(s.weekStart(record.EatenAt)).Hours() / (24 * 7)))
+		if index < 0 || index >= weeks {
+			continue
+		}
+		buckets[index].meals++
+		buckets[index].places[record.PlaceID] = true
+		if record.Cuisine != "" {
+			buckets[index].cuisines[record.Cuisine]++
+		}
+	}
+
+	summaries := make([]model.WeeklySummary, weeks)
+	for i, b := range buckets {
+		cuisines := make([]model.CuisineCount, 0, len(b.cuisines))
+		for cuisine, count := range b.cuisines {
+			cuisines = append(cuisines, model.CuisineCount{Cuisine: cuisine, Count: count})
+		}
+		sort.Slice(cuisines, func(a, c int) bool {
+			if cuisines[a].Count != cuisines[c].Count {
+				return cuisines[a].Count > cuisines[c].Count
+			}
+			return cuisines[a].Cuisine < cuisines[c].Cuisine
+		})
+
+		summaries[i] = model.WeeklySummary{
+			WeekStart:    currentWeek.AddDate(0, 0, -7*i).Format("2006-01-02"),
+			Meals:        b.meals,
+			UniquePlaces: len(b.places),
+			Cuisines:     cuisines,
+		}
+	}
+	return summaries
+}
+
+// Rerank 依用餐紀錄調整候選順序：最近吃過的料理與餐廳排到最後，或在 exclude 模式下直接排除
+// ordered 應已依策略排好優先順序，其餘餐廳的相對順序維持不變
+func (s *HistoryService) Rerank(userID string, ordered []model.Restaurant) ([]model.Restaurant, error) {
+	if userID == "" || len(ordered) == 0 {
+		return ordered, nil
+	}
+
+	now := time.Now()
+	records, err := s.ListMeals(userID, now.Add(-max(s.cfg.PlaceWindow, s.cfg.CuisineWindow)))
+	if err != nil {
+		return nil, fmt.Errorf("無法取得用餐紀錄: %w", err)
+	}
+	if len(records) == 0 {
+		return ordered, nil
+	}
+
+	recentPlaces := make(map[string]bool)
+	recentCuisines := make(map[string]bool)
+	for _, record := range records {
+		if now.Sub(record.EatenAt) <= s.cfg.PlaceWindow {
+			recentPlaces[record.PlaceID] = true
+		}
+		if now.Sub(record.EatenAt) <= s.cfg.CuisineWindow && record.Cuisine != "" {
+			recentCuisines[record.Cuisine] = true
+		}
+	}
+
+	placeDays := strconv.Itoa(windowDays(s.cfg.PlaceWindow))
+	cuisineDays := strconv.Itoa(windowDays(s.cfg.CuisineWindow))
+
+	fresh := make([]model.Restaurant, 0, len(ordered))
+	var cuisineRepeats, placeRepeats []model.Restaurant
+	for _, r := range ordered {
+		if recentPlaces[r.PlaceID] {
+			placeRepeats = append(placeRepeats, r.WithReason(model.Reason{
+				Code:   "recently_visited",
+				Stage:  model.StageHistory,
+				Params: map[string]string{"days": placeDays},
+			}))
+			continue
+		}
+		if cuisine := cuisineOf(r); recentCuisines[cuisine] {
+			cuisineRepeats = append(cuisineRepeats, r.WithReason(model.Reason{
+				Code:   "recently_eaten_cuisine",
+				Stage:  model.StageHistory,
+				Params: map[string]string{"cuisine": cuisine, "days": cuisineDays},
+			}))
+			continue
+		}
+		fresh = append(fresh, r)
+	}
+
+	if len(cuisineRepeats)+len(placeRepeats) > 0 {
+		fmt.Printf("依用餐紀錄調整候選 (%s): 最近吃過的料理 %d 家、餐廳 %d 家\n", s.cfg.Mode, len(cuisineRepeats), len(placeRepeats))
+	}
+
+	if s.cfg.Mode == model.HistoryModeExclude {
+		return fresh, nil
+	}
+	return append(append(fresh, cuisineRepeats...), placeRepeats...), nil
+}
+
+// MergeUser 將匿名使用者的用餐紀錄合併到帳號
+func (s *HistoryService) MergeUser(fromUserID, toUserID string) error {
+	records, err := s.repo.List(fromUserID)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		record.UserID = toUserID
+		if err := s.repo.Save(record); err != nil {
+			return err
+		}
+		if err := s.repo.Delete(fromUserID, record.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 依序從請求內容、使用者收藏、搜尋緩存取得餐廳資料，並清除與當次請求相關的欄位
+func resolveRestaurant(favorites *FavoriteService, restaurants *repository.RestaurantRepository, userID, placeID string, snapshot *model.Restaurant) (model.Restaurant, error) {
+	restaurant, err := lookupRestaurant(favorites, restaurants, userID, placeID, snapshot)
+	if err != nil {
+		return model.Restaurant{}, err
+	}
+
+	restaurant.PlaceID = placeID
+	restaurant.Reasons = nil
+	restaurant.Score = 0
+	restaurant.ParticipantDistances = nil
+	return restaurant, nil
+}
+
+func lookupRestaurant(favorites *FavoriteService, restaurants *repository.RestaurantRepository, userID, placeID string, snapshot *model.Restaurant) (model.Restaurant, error) {
+	if snapshot != nil && snapshot.Name != "" {
+		return *snapshot, nil
+	}
+
+	favorite, err := favorites.GetFavorite(userID, placeID)
+	if err != nil && !errors.Is(err, ErrFavoriteNotFound) {
+		return model.Restaurant{}, err
+	}
+	if favorite != nil {
+		restaurant := favorite.Restaurant
+		if restaurant.RestaurantType == "" {
+			restaurant.RestaurantType = favorite.Category
+		}
+		return restaurant, nil
+	}
+
+	if cached, found := restaurants.FindCachedPlace(placeID); found {
+		return *cached, nil
+	}
+	return model.Restaurant{}, ErrUnknownPlace
+}
+
+// 取得該時間所在週的星期一 00:00
+func (s *HistoryService) weekStart(t time.Time) time.Time {
+	local := t.In(s.location)
+	offset := (int(local.Weekday()) + 6) % 7 // 星期一為 0
+	return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, s.location)
+}
+
+// 推測餐廳的料理種類：優先使用名稱中命中的關鍵字（如「拉麵」），其次為餐廳類型
+func cuisineOf(r model.Restaurant) string {
+	restaurantType, keyword := repository.InferCuisine(r.Name)
+	if keyword != "" {
+		return keyword
+	}
+	if r.RestaurantType != "" {
+		return r.RestaurantType
+	}
+	return restaurantType
+}
+
+func windowDays(window time.Duration) int {
+	return int((window + 23*time.Hour) / (24 * time.Hour))
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+func newTestHistoryService(mode, timezone string) *HistoryService {
+	cfg := config.HistoryConfig{
+		PlaceWindow:   7 * 24 * time.Hour,
+		CuisineWindow: 3 * 24 * time.Hour,
+		Mode:          mode,
+		Timezone:      timezone,
+	}
+	return NewHistoryService(repository.NewHistoryRepository(repository.NewMemoryStore()), newTestFavoriteService(), nil, cfg)
+}
+
+func TestRerank(t *testing.T) {
+	type meal struct {
+		placeID, name string
+		ago           time.Duration
+	}
+	const day = 24 * time.Hour
+
+	// 巷口小館推測不出料理，一蘭拉麵為「拉麵」，老四川麻辣鍋為「麻辣鍋」
+	candidates := []model.Restaurant{
+		{PlaceID: "diner", Name: "巷口小館"},
+		{PlaceID: "ramen", Name: "一蘭拉麵"},
+		{PlaceID: "hotpot", Name: "老四川麻辣鍋"},
+	}
+
+	tests := []struct {
+		name        string
+		mode        string
+		meals       []meal
+		want        []string
+		wantReasons map[string]string
+	}{
+		{"沒有用餐紀錄", model.HistoryModeDownrank, nil, []string{"diner", "ramen", "hotpot"}, nil},
+		{
+			name:        "餐廳在窗口內排到最後",
+			mode:        model.HistoryModeDownrank,
+			meals:       []meal{{"diner", "巷口小館", 7*day - time.Hour}},
+			want:        []string{"ramen", "hotpot", "diner"},
+			wantReasons: map[string]string{"diner": "recently_visited"},
+		},
+		{
+			name:  "餐廳在窗口內被排除",
+			mode:  model.HistoryModeExclude,
+			meals: []meal{{"diner", "巷口小館", 7*day - time.Hour}},
+			want:  []string{"ramen", "hotpot"},
+		},
+		{
+			name:  "餐廳剛超出窗口",
+			mode:  model.HistoryModeExclude,
+			meals: []meal{{"diner", "巷口小館", 7*day + time.Hour}},
+			want:  []string{"diner", "ramen", "hotpot"},
+		},
+		{
+			name:        "同料理的其他餐廳在窗口內",
+			mode:        model.HistoryModeDownrank,
+			meals:       []meal{{"other-ramen", "屯京拉麵", 3*day - time.Hour}},
+			want:        []string{"diner", "hotpot", "ramen"},
+			wantReasons: map[string]string{"ramen": "recently_eaten_cuisine"},
+		},
+		{
+			name:  "同料理的其他餐廳在窗口內被排除",
+			mode:  model.HistoryModeExclude,
+			meals: []meal{{"other-ramen", "屯京拉麵", 3*day - time.Hour}},
+			want:  []string{"diner", "hotpot"},
+		},
+		{
+			name:  "料理剛超出窗口",
+			mode:  model.HistoryModeExclude,
+			meals: []meal{{"other-ramen", "屯京拉麵", 3*day + time.Hour}},
+			want:  []string{"diner", "ramen", "hotpot"},
+		},
+		{
+			name:        "料理超出窗口但餐廳仍在窗口內",
+			mode:        model.HistoryModeDownrank,
+			meals:       []meal{{"ramen", "一蘭拉麵", 5 * day}},
+			want:        []string{"diner", "hotpot", "ramen"},
+			wantReasons: map[string]string{"ramen": "recently_visited"},
+		},
+		{
+			name: "最近吃過的料理排在最近去過的餐廳之前",
+			mode: model.HistoryModeDownrank,
+			meals: []meal{
+				{"diner", "巷口小館", 2 * day},
+				{"other-ramen", "屯京拉麵", day},
+			},
+			want:        []string{"hotpot", "ramen", "diner"},
+			wantReasons: map[string]string{"ramen": "recently_eaten_cuisine", "diner": "recently_visited"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestHistoryService(tt.mode, "Asia/Taipei")
+			for _, m := range tt.meals {
+				if _, err := s.RecordMeal("u1", m.placeID, &model.Restaurant{Name: m.name}, time.Now().Add(-m.ago)); err != nil {
+					t.Fatalf("RecordMeal(%s): %v", m.placeID, err)
+				}
+			}
+
+			got, err := s.Rerank("u1", candidates)
+			if err != nil {
+				t.Fatalf("Rerank: %v", err)
+			}
+			if ids := placeIDs(got); !slices.Equal(ids, tt.want) {
+				t.Fatalf("order = %v, want %v", ids, tt.want)
+			}
+			for _, r := range got {
+				want := tt.wantReasons[r.PlaceID]
+				if want == "" {
+					if len(r.Reasons) != 0 {
+						t.Errorf("%s: reasons = %+v, want none", r.PlaceID, r.Reasons)
+					}
+					continue
+				}
+				if len(r.Reasons) != 1 || r.Reasons[0].Code != want || r.Reasons[0].Stage != model.StageHistory {
+					t.Errorf("%s: reasons = %+v, want %s", r.PlaceID, r.Reasons, want)
+				}
+			}
+		})
+	}
+}
+
+// 每週摘要以設定時區的星期一 00:00 分週
+func TestWeeklySummary(t *testing.T) {
+	records := []model.MealRecord{
+		// 台灣時間星期一 00:30，UTC 仍是星期日
+		{PlaceID: "a", Cuisine: "拉麵", EatenAt: time.Date(2026, 10, 18, 16, 30, 0, 0, time.UTC)},
+		// 台灣時間星期日 23:30
+		{PlaceID: "b", Cuisine: "拉麵", EatenAt: time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)},
+		{PlaceID: "b", Cuisine: "麻辣鍋", EatenAt: time.Date(2026, 10, 14, 4, 0, 0, 0, time.UTC)},
+		// 超出統計範圍
+		{PlaceID: "c", Cuisine: "拉麵", EatenAt: time.Date(2026, 10, 5, 4, 0, 0, 0, time.UTC)},
+	}
+	// 台灣時間 2026-10-21 (三) 12:00
+	now := time.Date(2026, 10, 21, 4, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone string
+		want     []model.WeeklySummary
+	}{
+		{
+			timezone: "Asia/Taipei",
+			want: []model.WeeklySummary{
+				{WeekStart: "2026-10-19", Meals: 1, UniquePlaces: 1, Cuisines: []model.CuisineCount{{Cuisine: "拉麵", Count: 1}}},
+				{WeekStart: "2026-10-12", Meals: 2, UniquePlaces: 1, Cuisines: []model.CuisineCount{{Cuisine: "拉麵", Count: 1}, {Cuisine: "麻辣鍋", Count: 1}}},
+			},
+		},
+		{
+			timezone: "UTC",
+			want: []model.WeeklySummary{
+				{WeekStart: "2026-10-19", Meals: 0, UniquePlaces: 0, Cuisines: []model.CuisineCount{}},
+				{WeekStart: "2026-10-12", Meals: 3, UniquePlaces: 2, Cuisines: []model.CuisineCount{{Cuisine: "拉麵", Count: 2}, {Cuisine: "麻辣鍋", Count: 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			s := newTestHistoryService(model.HistoryModeDownrank, tt.timezone)
+			got := s.summarize(records, s.weekStart(now), 2)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(summaries) = %d, want %d", len(got), len(tt.want))
+			}
+			for i := range tt.want {
+				g, w := got[i], tt.want[i]
+				if g.WeekStart != w.WeekStart || g.Meals != w.Meals || g.UniquePlaces != w.UniquePlaces || !slices.Equal(g.Cuisines, w.Cuisines) {
+					t.Errorf("week %d = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
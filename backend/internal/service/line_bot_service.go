@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"what2eat-backend/internal/i18n"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// LineReplier 回覆 LINE 訊息
+type LineReplier interface {
+	Reply(ctx context.Context, replyToken string, messages []map[string]any) error
+}
+
+const (
+	// 每次推薦的餐廳數量（Flex carousel 最多 12 個）
+	lineRecommendCount = 5
+	// 聊天室的位置與類型選擇保留時間
+	lineSessionTTL = 24 * time.Hour
+	// LINE 使用者在推薦流程中的使用者 ID 前綴（用於用餐紀錄與偏好）
+	lineUserPrefix = "line_"
+)
+
+// 聊天指令對應的餐廳類型（也接受完整類型名稱），LINE 與 Slack 共用
+var cuisineCommands = map[string]string{
+	"中式":  "中式料理",
+	"日式":  "日式料理",
+	"義式":  "義式料理",
+	"韓式":  "韓式料理",
+	"美式":  "美式料理",
+	"泰式":  "泰式料理",
+	"早午餐": "早午餐",
+	"海鮮":  "海鮮料理",
+	"牛排":  "牛排",
+	"火鍋":  "火鍋",
+	"甜點":  "甜點",
+	"咖啡":  "咖啡廳",
+	"自助餐": "自助餐廳",
+	"吃到飽": "自助餐廳",
+}
+
+// 快速回覆按鈕上顯示的類型指令
+var lineQuickCuisines = []string{"中式", "日式", "義式", "韓式", "美式", "泰式", "火鍋", "早午餐", "甜點", "不限"}
+
+// 每個聊天室（個人、群組或多人聊天）最後一次的位置與類型選擇
+type lineSession struct {
+	lat            float64
+	lng            float64
+	hasLocation    bool
+	restaurantType string
+	lastPicks      []string // 上一次推薦的餐廳，「再一次」時排除
+	updatedAt      time.Time
+}
+
+// LineBotService 處理 LINE webhook 事件，以既有的推薦流程回覆附近餐廳
+type LineBotService struct {
+	restaurantService *RestaurantService
+	replier           LineReplier
+
+	mu       sync.Mutex
+	sessions map[string]*lineSession
+}
+
+func NewLineBotService(restaurantService *RestaurantService, replier LineReplier) *LineBotService {
+	return &LineBotService{
+		restaurantService: restaurantService,
+		replier:           replier,
+		sessions:          make(map[string]*lineSession),
+	}
+}
+
+// HandleEvent 處理單一 webhook 事件
+func (s *LineBotService) HandleEvent(ctx context.Context, event model.LineEvent) error {
+	if event.ReplyToken == "" {
+		return nil
+	}
+
+	switch event.Type {
+	case "follow", "join":
+		return s.reply(ctx, event.ReplyToken, helpMessage("歡迎使用 What2Eat！"))
+	case "message":
+		if event.Message == nil {
+			return nil
+		}
+		switch event.Message.Type {
+		case "location":
+			session := s.updateSession(event.Source, func(session *lineSession) {
+				session.lat = event.Message.Latitude
+				session.lng = event.Message.Longitude
+				session.hasLocation = true
+			})
+			return s.recommend(ctx, event, session, false)
+		case "text":
+			return s.handleText(ctx, event)
+		}
+	}
+	return nil
+}
+
+// 處理文字指令：選擇類型、再推薦一次或顯示說明
+func (s *LineBotService) handleText(ctx context.Context, event model.LineEvent) error {
+	text := strings.TrimSpace(event.Message.Text)
+
+	restaurantType, isCuisine := parseCuisineCommand(text)
+	switch {
+	case isCuisine:
+		session := s.updateSession(event.Source, func(session *lineSession) {
+			session.restaurantType = restaurantType
+		})
+		if !session.hasLocation {
+			label := restaurantType
+			if label == "" {
+				label = "不限類型"
+			}
+			return s.reply(ctx, event.ReplyToken, locationRequestMessage(fmt.Sprintf("已選擇「%s」，請傳送你的位置資訊 📍", label)))
+		}
+		return s.recommend(ctx, event, session, false)
+
+	case text == "再一次" || text == "換一批" || text == "再來":
+		session := s.updateSession(event.Source, nil)
+		if !session.hasLocation {
+			return s.reply(ctx, event.ReplyToken, locationRequestMessage("請先傳送你的位置資訊 📍"))
+		}
+		return s.recommend(ctx, event, session, true)
+
+	default:
+		return s.reply(ctx, event.ReplyToken, helpMessage(""))
+	}
+}
+
+// 以聊天室的位置與類型執行推薦流程，並回覆 Flex carousel
+// reroll 為 true 時排除上一次推薦過的餐廳
+func (s *LineBotService) recommend(ctx context.Context, event model.LineEvent, session lineSession, reroll bool) error {
+	query := model.RecommendQuery{
+		Lat:            session.lat,
+		Lng:            session.lng,
+		RestaurantType: session.restaurantType,
+		Budget:         -1,
+		Count:          lineRecommendCount,
+		Language:       i18n.LangZhTW,
+	}
+	if event.Source.UserID != "" {
+		query.UserID = lineUserPrefix + event.Source.UserID
+	}
+	if reroll {
+		query.ExcludePlaceIDs = session.lastPicks
+	}
+
+	result, err := s.restaurantService.RecommendRestaurants(ctx, query)
+	if err != nil {
+		fmt.Printf("LINE 推薦失敗: %v\n", err)
+		return s.reply(ctx, event.ReplyToken, textMessage("搜尋餐廳時發生錯誤，請稍後再試 🙏"))
+	}
+
+	if len(result.Restaurants) == 0 {
+		return s.reply(ctx, event.ReplyToken, withCuisineQuickReply(textMessage("附近找不到符合條件的餐廳，換個類型試試看？")))
+	}
+
+	picks := make([]string, len(result.Restaurants))
+	for i, r := range result.Restaurants {
+		picks[i] = r.PlaceID
+	}
+	s.updateSession(event.Source, func(session *lineSession) {
+		session.lastPicks = picks
+	})
+
+	return s.reply(ctx, event.ReplyToken, restaurantCarousel(result.Restaurants), withCuisineQuickReply(textMessage("想換一批請輸入「再一次」，或選擇其他類型 👇")))
+}
+
+func (s *LineBotService) reply(ctx context.Context, replyToken string, messages ...map[string]any) error {
+	return s.replier.Reply(ctx, replyToken, messages)
+}
+
+// 更新並返回聊天室狀態的副本，過期的狀態會先清除
+func (s *LineBotService) updateSession(source model.LineSource, update func(*lineSession)) lineSession {
+	key := sessionKey(source)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, session := range s.sessions {
+		if now.Sub(session.updatedAt) > lineSessionTTL {
+			delete(s.sessions, k)
+		}
+	}
+
+	session, exists := s.sessions[key]
+	if !exists {
+		session = &lineSession{}
+		s.sessions[key] = session
+	}
+	if update != nil {
+		update(session)
+	}
+	session.updatedAt = now
+	return *session
+}
+
+// 群組與多人聊天共用同一個位置，個人聊天以使用者區分
+func sessionKey(source model.LineSource) string {
+	switch {
+	case source.GroupID != "":
+		return "group:" + source.GroupID
+	case source.RoomID != "":
+		return "room:" + source.RoomID
+	default:
+		return "user:" + source.UserID
+	}
+}
+
+// 解析類型指令，「不限」、「全部」表示不限類型
+func parseCuisineCommand(text string) (string, bool) {
+	if text == "不限" || text == "全部" {
+		return "", true
+	}
+	if restaurantType, ok := cuisineCommands[text]; ok {
+		return restaurantType, true
+	}
+	for _, restaurantType := range repository.RestaurantTypes {
+		if text == restaurantType {
+			return restaurantType, true
+		}
+	}
+	return "", false
+}
+
+func textMessage(text string) map[string]any {
+	return map[string]any{"type": "text", "text": text}
+}
+
+func helpMessage(greeting string) map[string]any {
+	text := "傳送位置資訊 📍 就會推薦附近的餐廳！\n輸入類型（如「日式」、「火鍋」）可以指定料理，輸入「再一次」換一批推薦。"
+	if greeting != "" {
+		text = greeting + "\n" + text
+	}
+	return withCuisineQuickReply(locationRequestMessage(text))
+}
+
+// 附上「傳送位置」快速回覆按鈕
+func locationRequestMessage(text string) map[string]any {
+	return map[string]any{
+		"type": "text",
+		"text": text,
+		"quickReply": map[string]any{
+			"items": []map[string]any{locationQuickReplyItem()},
+		},
+	}
+}
+
+// 附上餐廳類型快速回覆按鈕（保留既有的「傳送位置」按鈕）
+func withCuisineQuickReply(message map[string]any) map[string]any {
+	items := []map[string]any{locationQuickReplyItem()}
+	for _, command := range lineQuickCuisines {
+		items = append(items, map[string]any{
+			"type":   "action",
+			"action": map[string]any{"type": "message", "label": command, "text": command},
+		})
+	}
+	message["quickReply"] = map[string]any{"items": items}
+	return message
+}
+
+func locationQuickReplyItem() map[string]any {
+	return map[string]any{
+		"type":   "action",
+		"action": map[string]any{"type": "location", "label": "傳送位置"},
+	}
+}
+
+// 將推薦結果轉為 Flex Message carousel
+func restaurantCarousel(restaurants []model.Restaurant) map[string]any {
+	bubbles := make([]map[string]any, 0, len(restaurants))
+	names := make([]string, 0, len(restaurants))
+	for _, r := range restaurants {
+		bubbles = append(bubbles, restaurantBubble(r))
+		names = append(names, r.Name)
+	}
+
+	return map[string]any{
+		"type":     "flex",
+		"altText":  "推薦餐廳：" + strings.Join(names, "、"),
+		"contents": map[string]any{"type": "carousel", "contents": bubbles},
+	}
+}
+
+func restaurantBubble(r model.Restaurant) map[string]any {
+	body := []map[string]any{
+		{"type": "text", "text": r.Name, "weight": "bold", "size": "lg", "wrap": true},
+		flexInfoRow("評價", fmt.Sprintf("★ %.1f（%s 則評論）", r.Rating, formatCount(r.UserRatingsTotal))),
+		flexInfoRow("距離", r.Distance),
+		flexInfoRow("價格", formatLinePrice(r)),
+	}
+	if len(r.Reasons) > 0 && r.Reasons[len(r.Reasons)-1].Message != "" {
+		body = append(body, map[string]any{
+			"type": "text", "text": r.Reasons[len(r.Reasons)-1].Message,
+			"size": "xs", "color": "#999999", "wrap": true, "margin": "md",
+		})
+	}
+
+	bubble := map[string]any{
+		"type": "bubble",
+		"body": map[string]any{
+			"type": "box", "layout": "vertical", "spacing": "sm",
+			"contents": body,
+		},
+		"footer": map[string]any{
+			"type": "box", "layout": "vertical",
+			"contents": []map[string]any{{
+				"type":   "button",
+				"style":  "primary",
+				"action": map[string]any{"type": "uri", "label": "在 Google Maps 開啟", "uri": repository.GoogleMapsURL(r.Name, r.PlaceID)},
+			}},
+		},
+	}
+
+	if strings.HasPrefix(r.PhotoURL, "https://") {
+		bubble["hero"] = map[string]any{
+			"type": "image", "url": r.PhotoURL,
+			"size": "full", "aspectRatio": "20:13", "aspectMode": "cover",
+		}
+	}
+	return bubble
+}
+
+func flexInfoRow(label, value string) map[string]any {
+	if value == "" {
+		value = "-"
+	}
+	return map[string]any{
+		"type": "box", "layout": "baseline", "spacing": "sm",
+		"contents": []map[string]any{
+			{"type": "text", "text": label, "size": "sm", "color": "#aaaaaa", "flex": 1},
+			{"type": "text", "text": value, "size": "sm", "color": "#666666", "flex": 4, "wrap": true},
+		},
+	}
+}
+
+func formatLinePrice(r model.Restaurant) string {
+	if r.PriceLevel > 0 {
+		return strings.Repeat("$", r.PriceLevel) + " · " + r.AveragePrice
+	}
+	return r.AveragePrice
+}
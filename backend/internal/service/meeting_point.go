@@ -0,0 +1,74 @@
+package service
+
+import (
+	"math"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// minimax 迭代次數，每次向最遠參與者移動的步伐遞減，收斂到近似的最小包圍圓圓心
+const minimaxIterations = 500
+
+// GeographicMidpoint 以三維向量平均計算多個座標的地理中點
+func GeographicMidpoint(points []model.GroupParticipant) model.Location {
+	var x, y, z float64
+	for _, p := range points {
+		lat := p.Lat * math.Pi / 180
+		lng := p.Lng * math.Pi / 180
+		x += math.Cos(lat) * math.Cos(lng)
+		y += math.Cos(lat) * math.Sin(lng)
+		z += math.Sin(lat)
+	}
+
+	n := float64(len(points))
+	x, y, z = x/n, y/n, z/n
+
+	return model.Location{
+		Lat: math.Atan2(z, math.Sqrt(x*x+y*y)) * 180 / math.Pi,
+		Lng: math.Atan2(y, x) * 180 / math.Pi,
+	}
+}
+
+// MinimaxPoint 尋找使最遠參與者距離最小的會面地點 (Bădoiu–Clarkson 近似法)
+// 從地理中點出發，每次朝目前最遠的參與者移動 1/(i+1) 的距離
+func MinimaxPoint(points []model.GroupParticipant) model.Location {
+	center := GeographicMidpoint(points)
+
+	for i := 1; i <= minimaxIterations; i++ {
+		farthest, _ := farthestParticipant(center, points)
+		step := 1 / float64(i+1)
+		center.Lat += (farthest.Lat - center.Lat) * step
+		center.Lng += (farthest.Lng - center.Lng) * step
+	}
+
+	return center
+}
+
+// 返回距離指定地點最遠的參與者與其距離（公尺）
+func farthestParticipant(center model.Location, points []model.GroupParticipant) (model.GroupParticipant, float64) {
+	var farthest model.GroupParticipant
+	maxDistance := -1.0
+	for _, p := range points {
+		d := repository.HaversineMeters(center.Lat, center.Lng, p.Lat, p.Lng)
+		if d > maxDistance {
+			farthest, maxDistance = p, d
+		}
+	}
+	return farthest, maxDistance
+}
+
+// 標註每位參與者到餐廳的距離
+func annotateParticipantDistances(restaurants []model.Restaurant, participants []model.GroupParticipant) {
+	for i := range restaurants {
+		distances := make([]model.ParticipantDistance, 0, len(participants))
+		for _, p := range participants {
+			d := repository.HaversineMeters(p.Lat, p.Lng, restaurants[i].Lat, restaurants[i].Lng)
+			distances = append(distances, model.ParticipantDistance{
+				Name:           p.Name,
+				Distance:       repository.FormatDistance(d),
+				DistanceMeters: math.Round(d),
+			})
+		}
+		restaurants[i].ParticipantDistances = distances
+	}
+}
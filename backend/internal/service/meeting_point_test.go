@@ -0,0 +1,150 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// 以格點搜尋求出最遠參與者距離的最小值，作為 minimax 近似結果的對照
+func bruteForceMinimaxRadius(points []model.GroupParticipant) float64 {
+	minLat, maxLat := math.Inf(1), math.Inf(-1)
+	minLng, maxLng := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		minLat, maxLat = math.Min(minLat, p.Lat), math.Max(maxLat, p.Lat)
+		minLng, maxLng = math.Min(minLng, p.Lng), math.Max(maxLng, p.Lng)
+	}
+
+	const steps = 400
+	best := math.Inf(1)
+	for i := 0; i <= steps; i++ {
+		for j := 0; j <= steps; j++ {
+			center := model.Location{
+				Lat: minLat + (maxLat-minLat)*float64(i)/steps,
+				Lng: minLng + (maxLng-minLng)*float64(j)/steps,
+			}
+			if _, d := farthestParticipant(center, points); d < best {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+func TestMinimaxPoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []model.GroupParticipant
+	}{
+		{
+			name:   "單一參與者",
+			points: []model.GroupParticipant{{Lat: 25.0330, Lng: 121.5654}},
+		},
+		{
+			name: "兩位參與者",
+			points: []model.GroupParticipant{
+				{Lat: 25.0478, Lng: 121.5170},
+				{Lat: 25.0330, Lng: 121.5654},
+			},
+		},
+		{
+			name: "鈍角三角形",
+			points: []model.GroupParticipant{
+				{Lat: 25.0000, Lng: 121.5000},
+				{Lat: 25.0000, Lng: 121.6000},
+				{Lat: 25.0050, Lng: 121.5500},
+			},
+		},
+		{
+			name: "銳角三角形",
+			points: []model.GroupParticipant{
+				{Lat: 25.0000, Lng: 121.5000},
+				{Lat: 25.0000, Lng: 121.6000},
+				{Lat: 25.0800, Lng: 121.5500},
+			},
+		},
+		{
+			name: "多人聚集加上一位遠方參與者",
+			points: []model.GroupParticipant{
+				{Lat: 25.0330, Lng: 121.5654},
+				{Lat: 25.0335, Lng: 121.5660},
+				{Lat: 25.0325, Lng: 121.5650},
+				{Lat: 25.0340, Lng: 121.5645},
+				{Lat: 24.9500, Lng: 121.2200},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			center := MinimaxPoint(tt.points)
+			_, radius := farthestParticipant(center, tt.points)
+			optimal := bruteForceMinimaxRadius(tt.points)
+
+			// 近似解的最遠距離不應比最佳解多出 1% 或 10 公尺
+			if radius > optimal*1.01+10 {
+				t.Errorf("farthest distance = %.0fm, optimal %.0fm", radius, optimal)
+			}
+
+			// 不應比地理中點更差
+			_, midpointRadius := farthestParticipant(GeographicMidpoint(tt.points), tt.points)
+			if radius > midpointRadius+1 {
+				t.Errorf("farthest distance = %.0fm, worse than midpoint %.0fm", radius, midpointRadius)
+			}
+		})
+	}
+}
+
+// 聚集的參與者會把地理中點拉向自己，minimax 則讓遠方參與者與其他人距離相近
+func TestMinimaxPointBalancesOutlier(t *testing.T) {
+	cluster := model.GroupParticipant{Name: "cluster", Lat: 25.0330, Lng: 121.5654}
+	outlier := model.GroupParticipant{Name: "outlier", Lat: 24.9500, Lng: 121.2200}
+	points := []model.GroupParticipant{cluster, cluster, cluster, cluster, outlier}
+
+	center := MinimaxPoint(points)
+	toCluster := repository.HaversineMeters(center.Lat, center.Lng, cluster.Lat, cluster.Lng)
+	toOutlier := repository.HaversineMeters(center.Lat, center.Lng, outlier.Lat, outlier.Lng)
+	if math.Abs(toCluster-toOutlier) > toOutlier*0.02 {
+		t.Errorf("distance to cluster %.0fm, to outlier %.0fm, want roughly equal", toCluster, toOutlier)
+	}
+
+	midpoint := GeographicMidpoint(points)
+	midToOutlier := repository.HaversineMeters(midpoint.Lat, midpoint.Lng, outlier.Lat, outlier.Lng)
+	if toOutlier >= midToOutlier {
+		t.Errorf("outlier distance %.0fm, not shorter than midpoint %.0fm", toOutlier, midToOutlier)
+	}
+}
+
+func TestGeographicMidpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []model.GroupParticipant
+		want   model.Location
+	}{
+		{
+			name:   "單一座標",
+			points: []model.GroupParticipant{{Lat: 25.0330, Lng: 121.5654}},
+			want:   model.Location{Lat: 25.0330, Lng: 121.5654},
+		},
+		{
+			name:   "赤道上對稱",
+			points: []model.GroupParticipant{{Lat: 0, Lng: 10}, {Lat: 0, Lng: 20}},
+			want:   model.Location{Lat: 0, Lng: 15},
+		},
+		{
+			name:   "跨越國際換日線",
+			points: []model.GroupParticipant{{Lat: 0, Lng: 179}, {Lat: 0, Lng: -179}},
+			want:   model.Location{Lat: 0, Lng: 180},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GeographicMidpoint(tt.points)
+			if d := repository.HaversineMeters(got.Lat, got.Lng, tt.want.Lat, tt.want.Lng); d > 1 {
+				t.Errorf("midpoint = %v, want %v (%.1fm away)", got, tt.want, d)
+			}
+		})
+	}
+}
@@ -3,75 +3,394 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
-	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/i18n"
 	"what2eat-backend/internal/model"
 	"what2eat-backend/internal/repository"
 )
 
+var (
+	// ErrUserRequired 從收藏推薦時需要使用者身分
+	ErrUserRequired = &model.Error{Kind: model.ErrUnauthorized, Message: "從收藏推薦需要使用者身分"}
+	// ErrInvalidCoordinates 經緯度超出範圍
+	ErrInvalidCoordinates = &model.Error{Kind: model.ErrInvalidLocation, Message: "經緯度超出範圍"}
+	// ErrNoParticipants 團體推薦沒有任何參與者的位置
+	ErrNoParticipants = &model.Error{Kind: model.ErrInvalidLocation, Message: "至少需要一位參與者的位置"}
+)
+
 type RestaurantService struct {
-	repo           *repository.RestaurantRepository
-	counterService *CounterService
+	repo                *repository.RestaurantRepository
+	counterService      *CounterService
+	favoriteService     *FavoriteService
+	historyService      *HistoryService
+	feedbackService     *FeedbackService
+	travelService       *TravelService
+	areaService         *AreaService
+	scorer              *Scorer
+	diversity           *DiversitySelector
+	defaultStrategy     string
+	favoriteMaxDistance float64
+	mixedRatio          float64
+	areaSearch          config.AreaSearchConfig
 }
 
-func NewRestaurantService(repo *repository.RestaurantRepository, counterService *CounterService) *RestaurantService {
+// 預設推薦數量
+const defaultRecommendCount = 3
+
+func NewRestaurantService(repo *repository.RestaurantRepository, counterService *CounterService, favoriteService *FavoriteService, historyService *HistoryService, feedbackService *FeedbackService, travelService *TravelService, areaService *AreaService, cfg *config.Config) *RestaurantService {
 	return &RestaurantService{
-		repo:           repo,
-		counterService: counterService,
+		repo:                repo,
+		counterService:      counterService,
+		favoriteService:     favoriteService,
+		historyService:      historyService,
+		feedbackService:     feedbackService,
+		travelService:       travelService,
+		areaService:         areaService,
+		scorer:              NewScorer(cfg.Scoring),
+		diversity:           NewDiversitySelector(cfg.Diversity.MinSpacingMeters),
+		defaultStrategy:     cfg.Scoring.DefaultStrategy,
+		favoriteMaxDistance: cfg.Favorites.MaxDistanceMeters,
+		mixedRatio:          cfg.Favorites.MixedRatio,
+		areaSearch:          cfg.AreaSearch,
 	}
 }
 
+// RecommendProgress 接收推薦流程的進度，在呼叫推薦的 goroutine 中依序呼叫
+type RecommendProgress func(event model.RecommendEvent)
+
 // RecommendRestaurants 根據位置和類型推薦餐廳
-func (s *RestaurantService) RecommendRestaurants(ctx context.Context, lat, lng float64, restaurantType string) ([]model.Restaurant, error) {
+// 候選來源可為附近搜尋、使用者收藏或兩者混合，之後皆經過相同的排序與挑選流程
+func (s *RestaurantService) RecommendRestaurants(ctx context.Context, query model.RecommendQuery) (*model.RecommendResult, error) {
+	return s.RecommendRestaurantsStream(ctx, query, nil)
+}
+
+// RecommendRestaurantsStream 與 RecommendRestaurants 相同，並在流程進行時回報進度（progress 可為 nil）
+// 每家推薦餐廳的照片與交通時間完成後立即回報；ctx 取消時停止之後的搜尋與等待
+func (s *RestaurantService) RecommendRestaurantsStream(ctx context.Context, query model.RecommendQuery, progress RecommendProgress) (*model.RecommendResult, error) {
+	notify := func(event model.RecommendEvent) {
+		if progress != nil {
+			progress(event)
+		}
+	}
+
+	count := query.Count
+	if count <= 0 {
+		count = defaultRecommendCount
+	}
+
+	source := query.Source
+	if source == "" {
+		source = model.SourceNearby
+	}
+	if source != model.SourceNearby && query.UserID == "" {
+		return nil, ErrUserRequired
+	}
+	if !finite(query.Lat, query.Lng) || query.Lat < -90 || query.Lat > 90 || query.Lng < -180 || query.Lng > 180 {
+		return nil, ErrInvalidCoordinates
+	}
+
+	var favoriteCandidates, nearbyCandidates []model.Restaurant
+	var cacheStatus string
+	var providers []string
+	var err error
+
+	if source == model.SourceNearby || source == model.SourceMixed {
+		cacheStatus = model.CacheStatusMiss
+		if s.repo.IsCached(query.Lat, query.Lng, query.RestaurantType) {
+			cacheStatus = model.CacheStatusHit
+		}
+	}
+	notify(model.RecommendEvent{Type: model.RecommendEventStarted, Source: source, CacheStatus: cacheStatus})
+
+	// 收藏來源不呼叫 Google API，不計入每日額度
+	if source == model.SourceFavorites || source == model.SourceMixed {
+		favoriteCandidates, err = s.favoriteCandidates(query)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, model.ProviderFavorites)
+	}
+
+	if source == model.SourceNearby || source == model.SourceMixed {
+		nearbyCandidates, err = s.searchCandidates(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, model.ProviderGooglePlaces)
+	}
+
+	// 排除使用者標記為「不再推薦」的餐廳，之後的排序也依偏好調整權重
+	personalization, err := s.feedbackService.Personalization(query.UserID)
+	if err != nil {
+		return nil, err
+	}
+	favoriteCandidates = personalization.Filter(favoriteCandidates)
+	nearbyCandidates = personalization.Filter(nearbyCandidates)
+
+	// 重新推薦時排除上一批已推薦過的餐廳
+	if len(query.ExcludePlaceIDs) > 0 {
+		favoriteCandidates = excludePlaceIDs(favoriteCandidates, query.ExcludePlaceIDs)
+		nearbyCandidates = excludePlaceIDs(nearbyCandidates, query.ExcludePlaceIDs)
+	}
+	notify(model.RecommendEvent{Type: model.RecommendEventCandidates, Candidates: len(favoriteCandidates) + len(nearbyCandidates)})
+
+	// 如果沒有找到符合條件的餐廳
+	if len(favoriteCandidates) == 0 && len(nearbyCandidates) == 0 {
+		fmt.Printf("未找到符合條件的餐廳: 位置 [%.4f, %.4f], 類型: %s, 來源: %s\n", query.Lat, query.Lng, query.RestaurantType, source)
+		return &model.RecommendResult{
+			Restaurants: []model.Restaurant{},
+			Area:        s.areaService.Resolve(ctx, query.Lat, query.Lng),
+			CacheStatus: cacheStatus,
+			Providers:   providers,
+		}, nil
+	}
+
+	// 依策略將所有候選排出優先順序，再交由多樣性限制挑選
+	strategy := s.resolveStrategy(query.Strategy)
+	favoriteOrdered := s.orderCandidates(favoriteCandidates, strategy, query.Budget, personalization)
+	nearbyOrdered := s.orderCandidates(nearbyCandidates, strategy, query.Budget, personalization)
+
+	// 依用餐紀錄避開最近吃過的餐廳與料理，適用於所有候選來源
+	if favoriteOrdered, err = s.historyService.Rerank(query.UserID, favoriteOrdered); err != nil {
+		return nil, err
+	}
+	if nearbyOrdered, err = s.historyService.Rerank(query.UserID, nearbyOrdered); err != nil {
+		return nil, err
+	}
+
+	var restaurants []model.Restaurant
+	var diversityInfo model.DiversityInfo
+	switch source {
+	case model.SourceFavorites:
+		restaurants, diversityInfo = s.diversity.Select(favoriteOrdered, count)
+	case model.SourceMixed:
+		// 先依比例從收藏挑選，剩餘名額由附近餐廳補足，任一方不足時由另一方遞補
+		favoriteCount := int(math.Round(float64(count) * s.mixedRatio))
+		picks, favoriteInfo := s.diversity.Select(favoriteOrdered, favoriteCount)
+		fill := append(excludePlaces(nearbyOrdered, picks), excludePlaces(favoriteOrdered, picks)...)
+		restaurants, diversityInfo = s.diversity.Extend(picks, fill, count)
+		diversityInfo = diversityInfo.Merge(favoriteInfo)
+	default:
+		restaurants, diversityInfo = s.diversity.Select(nearbyOrdered, count)
+	}
+
+	if diversityInfo.Relaxed {
+		fmt.Printf("候選餐廳不足，已放寬多樣性限制: %v\n", diversityInfo.RelaxedConstraints)
+	}
+
+	// 附加挑選階段的推薦理由
+	for i := range restaurants {
+		if strategy == model.StrategyWeighted {
+			for _, reason := range s.scorer.Explain(restaurants[i], query.Budget) {
+				restaurants[i] = restaurants[i].WithReason(reason)
+			}
+		} else {
+			restaurants[i] = restaurants[i].WithReason(model.Reason{Code: "random_pick", Stage: model.StageSelection})
+		}
+		for _, reason := range personalization.Explain(restaurants[i]) {
+			restaurants[i] = restaurants[i].WithReason(reason)
+		}
+	}
+	localizeReasons(restaurants, query.Language)
+
+	// 直線距離無法反映河流、快速道路或捷運路線，需要時以實際路線補上交通時間
+	if query.TravelMode != "" {
+		s.travelService.Enrich(ctx, query.Lat, query.Lng, restaurants, query.TravelMode, query.Language)
+	}
+
+	// 只為最終結果獲取照片URL，每家餐廳的照片完成後即為最終結果
+	err = s.repo.ResolvePhotoURLs(ctx, restaurants, func(i int) {
+		pick := restaurants[i]
+		notify(model.RecommendEvent{Type: model.RecommendEventPick, Index: i, Restaurant: &pick})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("成功推薦 %d 家餐廳 (來源: %s)\n", len(restaurants), source)
+	return &model.RecommendResult{
+		Restaurants: restaurants,
+		Diversity:   diversityInfo,
+		Area:        s.areaService.Resolve(ctx, query.Lat, query.Lng),
+		CacheStatus: cacheStatus,
+		Providers:   providers,
+	}, nil
+}
+
+// 從 Google Places 搜尋附近的候選餐廳（不含照片URL），會計入每日額度
+func (s *RestaurantService) searchCandidates(ctx context.Context, query model.RecommendQuery) ([]model.Restaurant, error) {
 	// 檢查是否已超過API限制
 	if s.counterService.IsLimitExceeded() {
 		current, limit := s.counterService.GetUsage()
-		errMsg := fmt.Sprintf("API 每日請求數已達上限 %d/%d", current, limit)
-		return nil, fmt.Errorf(errMsg)
+		return nil, fmt.Errorf("API 每日請求數已達上限 %d/%d: %w", current, limit, ErrDailyLimitExceeded)
 	}
 
-	// 增加計數
-	current, limit, err := s.counterService.IncrementAndGetUsage()
+	// 增加計數，超過限制時返回 ErrDailyLimitExceeded
+	if _, _, err := s.counterService.IncrementAndGetUsage(); err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.repo.SearchNearby(ctx, query.Lat, query.Lng, query.RestaurantType, false)
 	if err != nil {
-		return nil, fmt.Errorf("API 使用超出限制: %w", err)
+		// 紀錄API請求失敗
+		fmt.Printf("搜尋餐廳失敗: %v\n", err)
+		return nil, fmt.Errorf("搜尋餐廳失敗: %w", err)
 	}
+	return candidates, nil
+}
 
-	// 再次檢查增加後是否超過限制
-	if current > limit {
-		return nil, fmt.Errorf("API 每日請求數已達上限 %d/%d", current, limit)
+// 從使用者收藏中取出距離範圍內且符合類型的候選餐廳
+func (s *RestaurantService) favoriteCandidates(query model.RecommendQuery) ([]model.Restaurant, error) {
+	maxDistance := query.MaxDistanceMeters
+	if maxDistance <= 0 {
+		maxDistance = s.favoriteMaxDistance
 	}
 
-	// 直接從repository獲取隨機的3家餐廳（已處理好照片URL）
-	restaurants, err := s.repo.GetRandomRestaurants(lat, lng, restaurantType, 3)
+	favorites, err := s.favoriteService.ListFavorites(query.UserID, model.FavoriteFilter{
+		Category: query.RestaurantType,
+		Origin:   &model.Location{Lat: query.Lat, Lng: query.Lng},
+	})
 	if err != nil {
-		// 紀錄API請求失敗
-		errMsg := fmt.Sprintf("搜尋餐廳失敗: %v", err)
-		fmt.Printf("%s\n", errMsg)
-		return nil, fmt.Errorf(errMsg)
+		return nil, fmt.Errorf("無法取得收藏清單: %w", err)
 	}
 
-	// 如果沒有找到符合條件的餐廳
-	if len(restaurants) == 0 {
-		fmt.Printf("未找到符合條件的餐廳: 位置 [%.4f, %.4f], 類型: %s\n", lat, lng, restaurantType)
-		return []model.Restaurant{}, nil
+	candidates := make([]model.Restaurant, 0, len(favorites))
+	for _, favorite := range favorites {
+		// 沒有位置資訊或超出距離範圍的收藏不列入候選
+		if favorite.DistanceMeters == nil || *favorite.DistanceMeters > maxDistance {
+			continue
+		}
+
+		restaurant := favorite.Restaurant
+		restaurant.Lat = favorite.Location.Lat
+		restaurant.Lng = favorite.Location.Lng
+		restaurant.RestaurantType = favorite.Category
+		restaurant.Reasons = []model.Reason{{Code: "in_favorites", Stage: model.StageFavorites}}
+		candidates = append(candidates, restaurant)
+	}
+
+	fmt.Printf("收藏中有 %d 家餐廳位於 %s 範圍內\n", len(candidates), repository.FormatDistance(maxDistance))
+	return candidates, nil
+}
+
+// 依策略排出候選餐廳的優先順序
+// 隨機策略在有使用者偏好時，改以偏好倍率為權重抽籤（無偏好時等同均勻隨機）
+func (s *RestaurantService) orderCandidates(candidates []model.Restaurant, strategy string, budget int, personalization *Personalization) []model.Restaurant {
+	if strategy == model.StrategyWeighted {
+		return s.scorer.WeightedDraw(candidates, budget, len(candidates), personalization)
+	}
+	if personalization != nil {
+		pool := make([]model.Restaurant, len(candidates))
+		copy(pool, candidates)
+		return drawByWeight(pool, len(pool), personalization.Multiplier)
+	}
+	return s.selectRandomRestaurants(candidates, len(candidates))
+}
+
+// 排除已選過的餐廳
+func excludePlaces(restaurants, picked []model.Restaurant) []model.Restaurant {
+	pickedIDs := make([]string, len(picked))
+	for i, r := range picked {
+		pickedIDs[i] = r.PlaceID
+	}
+	return excludePlaceIDs(restaurants, pickedIDs)
+}
+
+// 排除指定 place ID 的餐廳
+func excludePlaceIDs(restaurants []model.Restaurant, placeIDs []string) []model.Restaurant {
+	pickedIDs := make(map[string]bool, len(placeIDs))
+	for _, id := range placeIDs {
+		pickedIDs[id] = true
 	}
 
-	fmt.Printf("成功推薦 %d 家餐廳\n", len(restaurants))
-	return restaurants, nil
+	remaining := make([]model.Restaurant, 0, len(restaurants))
+	for _, r := range restaurants {
+		if !pickedIDs[r.PlaceID] {
+			remaining = append(remaining, r)
+		}
+	}
+	return remaining
+}
+
+// RecommendForGroup 計算多位參與者的會面地點，並推薦該地點附近的餐廳
+// 搜尋沿用 RecommendRestaurants，因此共用緩存與API額度限制
+func (s *RestaurantService) RecommendForGroup(ctx context.Context, query model.GroupRecommendQuery) (*model.GroupRecommendResult, error) {
+	if len(query.Participants) == 0 {
+		return nil, ErrNoParticipants
+	}
+
+	var meetingPoint model.Location
+	switch query.Method {
+	case model.MeetingMethodMinimax:
+		meetingPoint = MinimaxPoint(query.Participants)
+	default:
+		query.Method = model.MeetingMethodMidpoint
+		meetingPoint = GeographicMidpoint(query.Participants)
+	}
+	_, maxDistance := farthestParticipant(meetingPoint, query.Participants)
+
+	fmt.Printf("團體推薦: %d 位參與者，會面地點 [%.4f, %.4f] (%s)，最遠 %s\n",
+		len(query.Participants), meetingPoint.Lat, meetingPoint.Lng, query.Method, repository.FormatDistance(maxDistance))
+
+	recommendQuery := query.RecommendQuery
+	recommendQuery.Lat = meetingPoint.Lat
+	recommendQuery.Lng = meetingPoint.Lng
+
+	result, err := s.RecommendRestaurants(ctx, recommendQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	annotateParticipantDistances(result.Restaurants, query.Participants)
+
+	return &model.GroupRecommendResult{
+		MeetingPoint:      meetingPoint,
+		Method:            query.Method,
+		MaxDistanceMeters: math.Round(maxDistance),
+		RecommendResult:   *result,
+	}, nil
+}
+
+// 未指定策略時使用設定檔中的預設策略
+func (s *RestaurantService) resolveStrategy(strategy string) string {
+	if strategy == "" {
+		strategy = s.defaultStrategy
+	}
+	if strategy == model.StrategyWeighted {
+		return model.StrategyWeighted
+	}
+	return model.StrategyRandom
+}
+
+// 依語系產生推薦理由的顯示文字
+func localizeReasons(restaurants []model.Restaurant, lang string) {
+	for i := range restaurants {
+		// 複製後再寫入，避免修改到緩存中共用的推薦理由
+		reasons := make([]model.Reason, len(restaurants[i].Reasons))
+		copy(reasons, restaurants[i].Reasons)
+		for j := range reasons {
+			reasons[j].Message = i18n.Translate(lang, "reason."+reasons[j].Code, reasons[j].Params)
+		}
+		restaurants[i].Reasons = reasons
+	}
 }
 
 // 不需要的GetRecommendations方法（為了向後兼容舊的API格式）移除
 // 因為recommend路由已經移除，所以此方法也不再需要
 
 func (s *RestaurantService) selectRandomRestaurants(restaurants []model.Restaurant, count int) []model.Restaurant {
-	if len(restaurants) <= count {
-		return restaurants
-	}
+	// 複製一份，避免打亂 repository 緩存中的切片
+	shuffled := make([]model.Restaurant, len(restaurants))
+	copy(shuffled, restaurants)
 
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(restaurants), func(i, j int) {
-		restaurants[i], restaurants[j] = restaurants[j], restaurants[i]
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
-	return restaurants[:count]
+	if len(shuffled) <= count {
+		return shuffled
+	}
+	return shuffled[:count]
 }
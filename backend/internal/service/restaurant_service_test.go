@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"what2eat-backend/internal/model"
+)
+
+func TestRecommendRejectsInvalidCoordinates(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name     string
+		lat, lng float64
+	}{
+		{"緯度為 NaN", nan, 121.5},
+		{"經度為 NaN", 25.03, nan},
+		{"緯度為 Inf", inf, 121.5},
+		{"經度為負 Inf", 25.03, -inf},
+		{"緯度超出範圍", 91, 121.5},
+		{"經度超出範圍", 25.03, 181},
+	}
+
+	// 座標檢查在搜尋與呼叫 Google API 之前
+	s := &RestaurantService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.RecommendRestaurants(context.Background(), model.RecommendQuery{Lat: tt.lat, Lng: tt.lng})
+			if !errors.Is(err, ErrInvalidCoordinates) {
+				t.Errorf("err = %v, want ErrInvalidCoordinates", err)
+			}
+		})
+	}
+}
+
+// 各階段產生的推薦理由都能依語系轉成訊息，且不修改緩存中共用的理由
+func TestLocalizeReasons(t *testing.T) {
+	tests := []struct {
+		reason model.Reason
+		zh, en string
+	}{
+		{model.Reason{Code: "nearby_search", Stage: model.StagePrimarySearch}, "附近的餐廳", "nearby restaurant"},
+		{model.Reason{Code: "type_search", Stage: model.StagePrimarySearch, Params: map[string]string{"type": "拉麵"}}, "符合「拉麵」搜尋", "matches \"拉麵\" search"},
+		{model.Reason{Code: "keyword_match", Stage: model.StageNameSearch, Params: map[string]string{"keyword": "豚骨", "type": "日式料理"}}, "透過關鍵字「豚骨」符合日式料理", "matches 日式料理 via keyword 豚骨"},
+		{model.Reason{Code: "highly_rated", Stage: model.StageScoring, Params: map[string]string{"rating": "4.7", "reviews": "1.2k"}}, "高評價（4.7 分，1.2k 則評論）", "highly rated (4.7, 1.2k reviews)"},
+		{model.Reason{Code: "well_rated", Stage: model.StageScoring, Params: map[string]string{"rating": "4.1"}}, "評價不錯（4.1 分）", "well rated (4.1)"},
+		{model.Reason{Code: "within", Stage: model.StageScoring, Params: map[string]string{"distance": "300m"}}, "距離 300m 以內", "within 300m"},
+		{model.Reason{Code: "fits_budget", Stage: model.StageScoring, Params: map[string]string{"price": "$$"}}, "符合預算（$$）", "fits your budget ($$)"},
+		{model.Reason{Code: "random_pick", Stage: model.StageSelection}, "隨機選出", "picked at random"},
+		{model.Reason{Code: "in_favorites", Stage: model.StageFavorites}, "在你的收藏中", "in your favourites"},
+		{model.Reason{Code: "recently_visited", Stage: model.StageHistory, Params: map[string]string{"days": "14"}}, "14 天內來過，其他選擇不足時才推薦", "you ate here in the last 14 days; shown because options were limited"},
+		{model.Reason{Code: "recently_eaten_cuisine", Stage: model.StageHistory, Params: map[string]string{"cuisine": "拉麵", "days": "3"}}, "3 天內吃過拉麵，其他選擇不足時才推薦", "you had 拉麵 in the last 3 days; shown because options were limited"},
+		{model.Reason{Code: "you_liked", Stage: model.StagePreference}, "你曾按讚", "you liked this place"},
+		{model.Reason{Code: "likes_cuisine", Stage: model.StagePreference, Params: map[string]string{"cuisine": "拉麵"}}, "你喜歡拉麵", "you like 拉麵"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason.Code, func(t *testing.T) {
+			for lang, want := range map[string]string{"zh-TW": tt.zh, "en-US,en;q=0.9": tt.en, "": tt.zh} {
+				cached := []model.Restaurant{{Reasons: []model.Reason{tt.reason}}}
+				restaurants := []model.Restaurant{cached[0]}
+				localizeReasons(restaurants, lang)
+				if got := restaurants[0].Reasons[0].Message; got != want {
+					t.Errorf("lang %q: message = %q, want %q", lang, got, want)
+				}
+				if cached[0].Reasons[0].Message != "" {
+					t.Errorf("lang %q: cached reason was modified", lang)
+				}
+			}
+		})
+	}
+}
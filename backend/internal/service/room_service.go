@@ -0,0 +1,581 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrRoomNotFound        = &model.Error{Kind: model.ErrNotFound, Message: "找不到投票房間"}
+	ErrRoomClosed          = &model.Error{Kind: model.ErrConflict, Message: "投票房間已結束"}
+	ErrParticipantNotFound = &model.Error{Kind: model.ErrForbidden, Message: "找不到參與者，請先加入房間"}
+	ErrCandidateNotFound   = &model.Error{Kind: model.ErrNotFound, Message: "候選餐廳不存在"}
+	ErrCandidateVetoed     = &model.Error{Kind: model.ErrConflict, Message: "此餐廳已被否決，無法投票"}
+	ErrNoCandidates        = &model.Error{Kind: model.ErrNotFound, Message: "附近沒有可供投票的餐廳"}
+	ErrInvalidName         = &model.Error{Kind: model.ErrInvalidArgument, Message: "顯示名稱不可為空"}
+	ErrNotRoomCreator      = &model.Error{Kind: model.ErrForbidden, Message: "只有建立房間的人可以結束投票"}
+	ErrVetoLimit           = &model.Error{Kind: model.ErrConflict, Message: fmt.Sprintf("每人最多只能否決 %d 家餐廳", maxVetoesPerPerson)}
+)
+
+const (
+	roomKeyPrefix      = "room:"
+	maxVetoesPerPerson = 1
+	roomEventBuffer    = 8
+)
+
+// CreateRoomRequest 建立房間的參數
+type CreateRoomRequest struct {
+	Query    model.RoomQuery
+	PoolSize int           // 候選餐廳數量
+	Deadline time.Duration // 投票截止時間，0 使用預設值
+	Quorum   int           // 投票人數達此值即結束，0 表示只看截止時間
+	Language string
+	Creator  string // 建立者的使用者 ID，只有建立者可以提前結束投票
+}
+
+// RoomService 管理團體投票房間，狀態保存在記憶體並可選擇性持久化
+type RoomService struct {
+	mu          sync.Mutex
+	rooms       map[string]*model.Room
+	timers      map[string]*time.Timer
+	subscribers map[string]map[chan model.RoomView]struct{}
+
+	restaurantService *RestaurantService
+	store             repository.Store
+	cfg               config.RoomConfig
+}
+
+func NewRoomService(restaurantService *RestaurantService, store repository.Store, cfg config.RoomConfig) *RoomService {
+	s := &RoomService{
+		rooms:             make(map[string]*model.Room),
+		timers:            make(map[string]*time.Timer),
+		subscribers:       make(map[string]map[chan model.RoomView]struct{}),
+		restaurantService: restaurantService,
+		store:             store,
+		cfg:               cfg,
+	}
+
+	s.loadRooms()
+	go s.expireLoop()
+
+	return s
+}
+
+// CreateRoom 依推薦條件取得候選餐廳並建立房間
+func (s *RoomService) CreateRoom(ctx context.Context, req CreateRoomRequest) (*model.RoomView, error) {
+	poolSize := req.PoolSize
+	if poolSize <= 0 {
+		poolSize = s.cfg.DefaultPoolSize
+	}
+	if poolSize > s.cfg.MaxPoolSize {
+		poolSize = s.cfg.MaxPoolSize
+	}
+
+	deadline := req.Deadline
+	if deadline <= 0 {
+		deadline = s.cfg.DefaultDeadline
+	}
+
+	result, err := s.restaurantService.RecommendRestaurants(ctx, model.RecommendQuery{
+		Lat:            req.Query.Lat,
+		Lng:            req.Query.Lng,
+		RestaurantType: req.Query.RestaurantType,
+		Strategy:       req.Query.Strategy,
+		Budget:         req.Query.Budget,
+		Count:          poolSize,
+		Language:       req.Language,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Restaurants) == 0 {
+		return nil, ErrNoCandidates
+	}
+
+	now := time.Now()
+	room := &model.Room{
+		Query:        req.Query,
+		Candidates:   result.Restaurants,
+		Participants: make(map[string]*model.Participant),
+		Quorum:       req.Quorum,
+		Status:       model.RoomStatusOpen,
+		CreatedBy:    req.Creator,
+		CreatedAt:    now,
+		Deadline:     now.Add(deadline),
+		ExpiresAt:    now.Add(deadline + s.cfg.TTL),
+	}
+	view, err := s.addRoom(room)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("建立投票房間 %s: %d 家候選餐廳，截止時間 %s\n", room.ID, len(room.Candidates), room.Deadline.Format("15:04:05"))
+	return &view, nil
+}
+
+// 分配未使用的房間 ID 後加入房間並排程截止時間
+func (s *RoomService) addRoom(room *model.Room) (model.RoomView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 房間 ID 較短，重複時重新產生
+	for {
+		id, err := newRoomID()
+		if err != nil {
+			return model.RoomView{}, err
+		}
+		if _, exists := s.rooms[id]; !exists {
+			room.ID = id
+			break
+		}
+	}
+
+	s.rooms[room.ID] = room
+	s.scheduleDeadline(room)
+	s.persist(room)
+	return buildRoomView(room), nil
+}
+
+// GetRoom 取得房間狀態
+func (s *RoomService) GetRoom(roomID string) (*model.RoomView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, found := s.rooms[roomID]
+	if !found {
+		return nil, ErrRoomNotFound
+	}
+	view := buildRoomView(room)
+	return &view, nil
+}
+
+// Join 以顯示名稱加入房間，返回參與者 ID（後續投票時使用）
+func (s *RoomService) Join(roomID, name string) (string, *model.RoomView, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, ErrInvalidName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, err := s.openRoom(roomID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	participantID, err := newParticipantID()
+	if err != nil {
+		return "", nil, err
+	}
+	room.Participants[participantID] = &model.Participant{Name: name, JoinedAt: time.Now()}
+
+	s.persist(room)
+	view := s.broadcast(room)
+	return participantID, &view, nil
+}
+
+// Vote 投票給候選餐廳，重複投票會覆蓋先前的選擇
+func (s *RoomService) Vote(roomID, participantID, placeID string) (*model.RoomView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, participant, err := s.participant(roomID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasCandidate(room, placeID) {
+		return nil, ErrCandidateNotFound
+	}
+	if vetoCount(room)[placeID] > 0 {
+		return nil, ErrCandidateVetoed
+	}
+
+	participant.Vote = placeID
+
+	// 達到法定人數即結束投票
+	if room.Quorum > 0 && votedCount(room) >= room.Quorum {
+		s.closeRoom(room)
+	}
+
+	s.persist(room)
+	view := s.broadcast(room)
+	return &view, nil
+}
+
+// Veto 否決候選餐廳，被否決的餐廳不會勝出，已投給它的票也會被取消
+func (s *RoomService) Veto(roomID, participantID, placeID string) (*model.RoomView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, participant, err := s.participant(roomID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasCandidate(room, placeID) {
+		return nil, ErrCandidateNotFound
+	}
+	if len(participant.Vetoes) >= maxVetoesPerPerson {
+		return nil, ErrVetoLimit
+	}
+
+	participant.Vetoes = append(participant.Vetoes, placeID)
+	for _, p := range room.Participants {
+		if p.Vote == placeID {
+			p.Vote = ""
+		}
+	}
+
+	s.persist(room)
+	view := s.broadcast(room)
+	return &view, nil
+}
+
+// Close 提前結束投票並決定勝出餐廳，只有建立房間的使用者可以結束
+func (s *RoomService) Close(roomID, userID string) (*model.RoomView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, err := s.openRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.CreatedBy == "" || room.CreatedBy != userID {
+		return nil, ErrNotRoomCreator
+	}
+
+	s.closeRoom(room)
+	s.persist(room)
+	view := s.broadcast(room)
+	return &view, nil
+}
+
+// Subscribe 訂閱房間狀態變更，返回目前狀態、事件通道與取消訂閱函式
+// 房間結束或過期時通道會被關閉
+func (s *RoomService) Subscribe(roomID string) (*model.RoomView, <-chan model.RoomView, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, found := s.rooms[roomID]
+	if !found {
+		return nil, nil, nil, ErrRoomNotFound
+	}
+
+	ch := make(chan model.RoomView, roomEventBuffer)
+	view := buildRoomView(room)
+
+	// 已結束的房間直接關閉通道，訂閱者只會收到最終狀態
+	if room.Status == model.RoomStatusClosed {
+		close(ch)
+		return &view, ch, func() {}, nil
+	}
+
+	if s.subscribers[roomID] == nil {
+		s.subscribers[roomID] = make(map[chan model.RoomView]struct{})
+	}
+	s.subscribers[roomID][ch] = struct{}{}
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if subs, ok := s.subscribers[roomID]; ok {
+			if _, ok := subs[ch]; ok {
+				delete(subs, ch)
+				close(ch)
+			}
+		}
+	}
+
+	return &view, ch, unsubscribe, nil
+}
+
+// 以下方法需持有 s.mu
+
+func (s *RoomService) openRoom(roomID string) (*model.Room, error) {
+	room, found := s.rooms[roomID]
+	if !found {
+		return nil, ErrRoomNotFound
+	}
+	if room.Status != model.RoomStatusOpen {
+		return nil, ErrRoomClosed
+	}
+	return room, nil
+}
+
+func (s *RoomService) participant(roomID, participantID string) (*model.Room, *model.Participant, error) {
+	room, err := s.openRoom(roomID)
+	if err != nil {
+		return nil, nil, err
+	}
+	participant, found := room.Participants[participantID]
+	if !found {
+		return nil, nil, ErrParticipantNotFound
+	}
+	return room, participant, nil
+}
+
+// 結束投票並決定勝出餐廳
+func (s *RoomService) closeRoom(room *model.Room) {
+	if room.Status == model.RoomStatusClosed {
+		return
+	}
+
+	room.Status = model.RoomStatusClosed
+	room.ClosedAt = time.Now()
+	room.Winner = pickWinner(room)
+
+	if timer, ok := s.timers[room.ID]; ok {
+		timer.Stop()
+		delete(s.timers, room.ID)
+	}
+
+	if room.Winner != nil {
+		fmt.Printf("投票房間 %s 結束，勝出餐廳: %s\n", room.ID, room.Winner.Name)
+	} else {
+		fmt.Printf("投票房間 %s 結束，沒有人投票\n", room.ID)
+	}
+}
+
+func (s *RoomService) scheduleDeadline(room *model.Room) {
+	roomID := room.ID
+	s.timers[roomID] = time.AfterFunc(time.Until(room.Deadline), func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		room, found := s.rooms[roomID]
+		if !found || room.Status != model.RoomStatusOpen {
+			return
+		}
+		s.closeRoom(room)
+		s.persist(room)
+		s.broadcast(room)
+	})
+}
+
+// 推送最新狀態給所有訂閱者，房間結束後關閉所有通道
+func (s *RoomService) broadcast(room *model.Room) model.RoomView {
+	view := buildRoomView(room)
+
+	for ch := range s.subscribers[room.ID] {
+		select {
+		case ch <- view:
+		default:
+			// 訂閱者處理過慢時略過，下一次推送的是完整狀態
+		}
+		if room.Status == model.RoomStatusClosed {
+			close(ch)
+		}
+	}
+	if room.Status == model.RoomStatusClosed {
+		delete(s.subscribers, room.ID)
+	}
+
+	return view
+}
+
+func (s *RoomService) persist(room *model.Room) {
+	if err := s.store.Put(roomKeyPrefix+room.ID, room); err != nil {
+		fmt.Printf("警告: 無法保存投票房間 %s: %v\n", room.ID, err)
+	}
+}
+
+func (s *RoomService) removeRoom(roomID string) {
+	if timer, ok := s.timers[roomID]; ok {
+		timer.Stop()
+		delete(s.timers, roomID)
+	}
+	for ch := range s.subscribers[roomID] {
+		close(ch)
+	}
+	delete(s.subscribers, roomID)
+	delete(s.rooms, roomID)
+
+	if err := s.store.Delete(roomKeyPrefix + roomID); err != nil {
+		fmt.Printf("警告: 無法刪除投票房間 %s: %v\n", roomID, err)
+	}
+}
+
+// 啟動時從儲存載入尚未過期的房間
+func (s *RoomService) loadRooms() {
+	keys, err := s.store.Keys(roomKeyPrefix)
+	if err != nil {
+		fmt.Printf("警告: 無法載入投票房間: %v\n", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		var room model.Room
+		if found, err := s.store.Get(key, &room); !found || err != nil {
+			continue
+		}
+		if now.After(room.ExpiresAt) {
+			s.removeRoom(room.ID)
+			continue
+		}
+		if room.Participants == nil {
+			room.Participants = make(map[string]*model.Participant)
+		}
+
+		s.rooms[room.ID] = &room
+		if room.Status == model.RoomStatusOpen {
+			// 截止時間已過的房間會立即結束
+			s.scheduleDeadline(&room)
+		}
+	}
+
+	if len(s.rooms) > 0 {
+		fmt.Printf("已載入 %d 個投票房間\n", len(s.rooms))
+	}
+}
+
+// 定期清除過期的房間
+func (s *RoomService) expireLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.mu.Lock()
+		now := time.Now()
+		for roomID, room := range s.rooms {
+			if now.After(room.ExpiresAt) {
+				fmt.Printf("投票房間 %s 已過期，移除\n", roomID)
+				s.removeRoom(roomID)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// 得票最多且未被否決的餐廳勝出，同票時取推薦順序較前者；沒有人投票時沒有勝出餐廳
+func pickWinner(room *model.Room) *model.Restaurant {
+	votes := make(map[string]int)
+	for _, p := range room.Participants {
+		if p.Vote != "" {
+			votes[p.Vote]++
+		}
+	}
+	vetoes := vetoCount(room)
+
+	var winner *model.Restaurant
+	best := 0
+	for i := range room.Candidates {
+		candidate := &room.Candidates[i]
+		if vetoes[candidate.PlaceID] > 0 {
+			continue
+		}
+		if votes[candidate.PlaceID] > best {
+			best = votes[candidate.PlaceID]
+			winner = candidate
+		}
+	}
+
+	if winner == nil {
+		return nil
+	}
+	result := *winner
+	return &result
+}
+
+func buildRoomView(room *model.Room) model.RoomView {
+	votes := make(map[string]int)
+	for _, p := range room.Participants {
+		if p.Vote != "" {
+			votes[p.Vote]++
+		}
+	}
+	vetoes := vetoCount(room)
+
+	view := model.RoomView{
+		ID:           room.ID,
+		Status:       room.Status,
+		Candidates:   make([]model.CandidateTally, 0, len(room.Candidates)),
+		Participants: make([]model.ParticipantView, 0, len(room.Participants)),
+		Quorum:       room.Quorum,
+		Winner:       room.Winner,
+		Deadline:     room.Deadline,
+		ExpiresAt:    room.ExpiresAt,
+	}
+
+	for _, candidate := range room.Candidates {
+		view.Candidates = append(view.Candidates, model.CandidateTally{
+			Restaurant: candidate,
+			Votes:      votes[candidate.PlaceID],
+			Vetoes:     vetoes[candidate.PlaceID],
+			Vetoed:     vetoes[candidate.PlaceID] > 0,
+		})
+	}
+	participants := make([]*model.Participant, 0, len(room.Participants))
+	for _, p := range room.Participants {
+		participants = append(participants, p)
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].JoinedAt.Before(participants[j].JoinedAt)
+	})
+	for _, p := range participants {
+		view.Participants = append(view.Participants, model.ParticipantView{Name: p.Name, Voted: p.Vote != ""})
+	}
+
+	return view
+}
+
+func vetoCount(room *model.Room) map[string]int {
+	vetoes := make(map[string]int)
+	for _, p := range room.Participants {
+		for _, placeID := range p.Vetoes {
+			vetoes[placeID]++
+		}
+	}
+	return vetoes
+}
+
+func votedCount(room *model.Room) int {
+	count := 0
+	for _, p := range room.Participants {
+		if p.Vote != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func hasCandidate(room *model.Room, placeID string) bool {
+	for _, candidate := range room.Candidates {
+		if candidate.PlaceID == placeID {
+			return true
+		}
+	}
+	return false
+}
+
+// 房間 ID 會出現在分享連結中，使用較短的隨機字串
+func newRoomID() (string, error) {
+	return randomHex(5)
+}
+
+// 參與者 ID 作為投票憑證，使用較長的隨機字串
+func newParticipantID() (string, error) {
+	return randomHex(16)
+}
+
+// 產生 n 位元組的隨機十六進位字串
+// ID 可能作為憑證使用，crypto/rand 失敗時返回錯誤，不退回可預測的值
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("無法產生隨機 ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+func newTestRoomService() *RoomService {
+	return NewRoomService(nil, repository.NewMemoryStore(), config.RoomConfig{
+		DefaultDeadline: time.Minute,
+		TTL:             time.Minute,
+		DefaultPoolSize: 4,
+		MaxPoolSize:     8,
+	})
+}
+
+// 不經過推薦流程，直接以指定的候選餐廳建立房間
+func addTestRoom(t *testing.T, s *RoomService, creator string, quorum int, deadline time.Duration) model.RoomView {
+	t.Helper()
+	now := time.Now()
+	view, err := s.addRoom(&model.Room{
+		Candidates: []model.Restaurant{
+			{PlaceID: "a", Name: "拉麵屋"},
+			{PlaceID: "b", Name: "火鍋店"},
+			{PlaceID: "c", Name: "早午餐"},
+		},
+		Participants: make(map[string]*model.Participant),
+		Quorum:       quorum,
+		Status:       model.RoomStatusOpen,
+		CreatedBy:    creator,
+		CreatedAt:    now,
+		Deadline:     now.Add(deadline),
+		ExpiresAt:    now.Add(deadline + time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return view
+}
+
+func joinRoom(t *testing.T, s *RoomService, roomID, name string) string {
+	t.Helper()
+	participantID, _, err := s.Join(roomID, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return participantID
+}
+
+func TestRoomQuorumClosesVote(t *testing.T) {
+	s := newTestRoomService()
+	room := addTestRoom(t, s, "creator", 2, time.Hour)
+	alice := joinRoom(t, s, room.ID, "Alice")
+	bob := joinRoom(t, s, room.ID, "Bob")
+
+	view, err := s.Vote(room.ID, alice, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Status != model.RoomStatusOpen {
+		t.Fatalf("status = %s after 1/2 votes, want open", view.Status)
+	}
+
+	view, err = s.Vote(room.ID, bob, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Status != model.RoomStatusClosed || view.Winner == nil || view.Winner.PlaceID != "b" {
+		t.Fatalf("room = %s winner %v, want closed with b", view.Status, view.Winner)
+	}
+
+	if _, err := s.Vote(room.ID, alice, "a"); !errors.Is(err, ErrRoomClosed) {
+		t.Errorf("vote after close: err = %v, want ErrRoomClosed", err)
+	}
+}
+
+func TestRoomDeadlineClosesVote(t *testing.T) {
+	s := newTestRoomService()
+	room := addTestRoom(t, s, "creator", 0, 50*time.Millisecond)
+	alice := joinRoom(t, s, room.ID, "Alice")
+	if _, err := s.Vote(room.ID, alice, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, events, unsubscribe, err := s.Subscribe(room.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribe()
+
+	// 截止時間到達後推送最終狀態並關閉通道
+	timeout := time.After(2 * time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-events:
+		case <-timeout:
+			t.Fatal("room was not closed at the deadline")
+		}
+	}
+
+	view, err := s.GetRoom(room.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Status != model.RoomStatusClosed || view.Winner == nil || view.Winner.PlaceID != "c" {
+		t.Errorf("room = %s winner %v, want closed with c", view.Status, view.Winner)
+	}
+}
+
+func TestRoomClose(t *testing.T) {
+	tests := []struct {
+		name    string
+		creator string
+		caller  string
+		wantErr error
+	}{
+		{name: "建立者可以結束", creator: "user-1", caller: "user-1"},
+		{name: "其他使用者不能結束", creator: "user-1", caller: "user-2", wantErr: ErrNotRoomCreator},
+		{name: "未帶身分不能結束", creator: "user-1", caller: "", wantErr: ErrNotRoomCreator},
+		{name: "沒有建立者的舊房間不能提前結束", creator: "", caller: "", wantErr: ErrNotRoomCreator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestRoomService()
+			room := addTestRoom(t, s, tt.creator, 0, time.Hour)
+
+			view, err := s.Close(room.ID, tt.caller)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if current, _ := s.GetRoom(room.ID); current.Status != model.RoomStatusOpen {
+					t.Errorf("status = %s after rejected close, want open", current.Status)
+				}
+				return
+			}
+			if view.Status != model.RoomStatusClosed {
+				t.Errorf("status = %s, want closed", view.Status)
+			}
+			if _, err := s.Close(room.ID, tt.caller); !errors.Is(err, ErrRoomClosed) {
+				t.Errorf("second close: err = %v, want ErrRoomClosed", err)
+			}
+		})
+	}
+
+	if _, err := newTestRoomService().Close("missing", "user-1"); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("missing room: err = %v, want ErrRoomNotFound", err)
+	}
+}
+
+func TestRoomCloseSkipsVetoedCandidate(t *testing.T) {
+	s := newTestRoomService()
+	room := addTestRoom(t, s, "creator", 0, time.Hour)
+	alice := joinRoom(t, s, room.ID, "Alice")
+	bob := joinRoom(t, s, room.ID, "Bob")
+
+	if _, err := s.Vote(room.ID, alice, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Veto(room.ID, bob, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Vote(room.ID, alice, "a"); !errors.Is(err, ErrCandidateVetoed) {
+		t.Fatalf("vote for vetoed: err = %v, want ErrCandidateVetoed", err)
+	}
+	if _, err := s.Vote(room.ID, bob, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := s.Close(room.ID, "creator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Winner == nil || view.Winner.PlaceID != "c" {
+		t.Errorf("winner = %v, want c", view.Winner)
+	}
+}
+
+func TestRoomIDsAreUnique(t *testing.T) {
+	s := newTestRoomService()
+	seen := make(map[string]bool)
+	for range 200 {
+		room := addTestRoom(t, s, "creator", 0, time.Hour)
+		if seen[room.ID] {
+			t.Fatalf("duplicate room ID %s", room.ID)
+		}
+		seen[room.ID] = true
+	}
+}
+
+func TestPickWinner(t *testing.T) {
+	candidates := []model.Restaurant{{PlaceID: "a"}, {PlaceID: "b"}, {PlaceID: "c"}}
+	tests := []struct {
+		name         string
+		participants map[string]*model.Participant
+		want         string // 空字串表示沒有勝出餐廳
+	}{
+		{
+			name:         "沒有參與者",
+			participants: map[string]*model.Participant{},
+		},
+		{
+			name:         "有參與者但沒有人投票",
+			participants: map[string]*model.Participant{"p1": {Name: "Alice"}, "p2": {Name: "Bob", Vetoes: []string{"a"}}},
+		},
+		{
+			name:         "得票最多者勝出",
+			participants: map[string]*model.Participant{"p1": {Vote: "c"}, "p2": {Vote: "c"}, "p3": {Vote: "b"}},
+			want:         "c",
+		},
+		{
+			name:         "同票時取順序較前者",
+			participants: map[string]*model.Participant{"p1": {Vote: "c"}, "p2": {Vote: "b"}},
+			want:         "b",
+		},
+		{
+			name:         "被否決的餐廳不會勝出",
+			participants: map[string]*model.Participant{"p1": {Vote: "a"}, "p2": {Vote: "a", Vetoes: []string{"a"}}, "p3": {Vote: "c"}},
+			want:         "c",
+		},
+		{
+			name:         "只有被否決的餐廳有票",
+			participants: map[string]*model.Participant{"p1": {Vote: "a"}, "p2": {Vetoes: []string{"a"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner := pickWinner(&model.Room{Candidates: candidates, Participants: tt.participants})
+			var got string
+			if winner != nil {
+				got = winner.PlaceID
+			}
+			if got != tt.want {
+				t.Errorf("winner = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,684 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/i18n"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrScheduleNotFound = &model.Error{Kind: model.ErrNotFound, Message: "找不到排程"}
+	ErrInvalidSchedule  = &model.Error{Kind: model.ErrInvalidArgument, Message: "無效的排程設定"}
+	ErrScheduleLimit    = &model.Error{Kind: model.ErrConflict, Message: "排程數量已達上限"}
+	ErrScheduleTooSoon  = &model.Error{Kind: model.ErrRateLimited, Message: "排程執行過於頻繁"}
+)
+
+// LinePusher 主動推送 LINE 訊息
+type LinePusher interface {
+	Push(ctx context.Context, to string, messages []map[string]any) error
+}
+
+// WebhookPoster 將 JSON 內容 POST 到外部 webhook
+type WebhookPoster interface {
+	PostJSON(ctx context.Context, url string, payload any) error
+}
+
+// IdentityResolver 查詢使用者帳號綁定的外部身分
+type IdentityResolver interface {
+	LinkedSubjects(userID, provider string) ([]string, error)
+}
+
+const (
+	// 以 LINE Login 綁定帳號時的身分提供者名稱（OAUTH_NAME），其識別碼即為 LINE 使用者 ID
+	lineIdentityProvider = "line"
+	// 排程器檢查到期排程的間隔
+	schedulerTick = 20 * time.Second
+	// 伺服器停機等原因錯過執行時間超過此值就不補發
+	scheduleMisfireGrace = 10 * time.Minute
+	// 兩次執行的最短間隔，避免過於頻繁的排程耗盡每日額度
+	minScheduleInterval = 15 * time.Minute
+	// 每次推送的最多餐廳數量
+	maxScheduleCount = 10
+	// 單次推薦與發送的時限
+	scheduleRunTimeout = 30 * time.Second
+)
+
+// SchedulerService 管理定期推送的排程，到期時執行推薦流程並發送到指定目標
+type SchedulerService struct {
+	repo              *repository.ScheduleRepository
+	restaurantService *RestaurantService
+	counterService    *CounterService
+	linePusher        LinePusher // 未設定 LINE access token 時為 nil
+	webhooks          WebhookPoster
+	identities        IdentityResolver
+	cfg               config.SchedulerConfig
+	holidays          map[string]bool
+
+	// 排程為讀取後修改再寫回，需序列化更新；running 避免同一排程重疊執行
+	mu      sync.Mutex
+	running map[string]bool
+	stop    chan struct{}
+}
+
+func NewSchedulerService(repo *repository.ScheduleRepository, restaurantService *RestaurantService, counterService *CounterService, linePusher LinePusher, webhooks WebhookPoster, identities IdentityResolver, cfg config.SchedulerConfig) *SchedulerService {
+	holidays := make(map[string]bool)
+	for _, date := range strings.Split(cfg.Holidays, ",") {
+		date = strings.TrimSpace(date)
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			fmt.Printf("警告: 無法解析假日 %q，需為 YYYY-MM-DD\n", date)
+			continue
+		}
+		holidays[date] = true
+	}
+
+	return &SchedulerService{
+		repo:              repo,
+		restaurantService: restaurantService,
+		counterService:    counterService,
+		linePusher:        linePusher,
+		webhooks:          webhooks,
+		identities:        identities,
+		cfg:               cfg,
+		holidays:          holidays,
+		running:           make(map[string]bool),
+		stop:              make(chan struct{}),
+	}
+}
+
+// Start 在背景定期檢查並執行到期的排程
+func (s *SchedulerService) Start() {
+	go func() {
+		ticker := time.NewTicker(schedulerTick)
+		defer ticker.Stop()
+
+		s.runDue()
+		for {
+			select {
+			case <-ticker.C:
+				s.runDue()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	fmt.Printf("排程器已啟動，假日 %d 天\n", len(s.holidays))
+}
+
+// Stop 停止排程器，進行中的重試會放棄
+func (s *SchedulerService) Stop() {
+	close(s.stop)
+}
+
+// CreateSchedule 新增排程，每個使用者的排程數量不超過設定的上限
+func (s *SchedulerService) CreateSchedule(userID string, schedule model.Schedule) (*model.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.repo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.MaxPerUser > 0 && len(existing) >= s.cfg.MaxPerUser {
+		return nil, fmt.Errorf("%w: 每個使用者最多 %d 個排程", ErrScheduleLimit, s.cfg.MaxPerUser)
+	}
+
+	if schedule.ID, err = randomHex(8); err != nil {
+		return nil, err
+	}
+	schedule.UserID = userID
+	schedule.CreatedAt = time.Now()
+	schedule.LastRunAt = nil
+	if err := s.prepare(&schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(schedule); err != nil {
+		return nil, fmt.Errorf("無法保存排程: %w", err)
+	}
+
+	fmt.Printf("使用者 %s 新增排程 %s (%s %s)，下次執行: %s\n", userID, schedule.ID, schedule.Cron, schedule.Timezone, schedule.NextRunAt.Format(time.RFC3339))
+	return &schedule, nil
+}
+
+// UpdateSchedule 以新的設定取代既有排程，保留建立時間與上次執行時間
+func (s *SchedulerService) UpdateSchedule(userID, scheduleID string, schedule model.Schedule) (*model.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.GetSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.ID = existing.ID
+	schedule.UserID = userID
+	schedule.CreatedAt = existing.CreatedAt
+	schedule.LastRunAt = existing.LastRunAt
+	if err := s.prepare(&schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(schedule); err != nil {
+		return nil, fmt.Errorf("無法保存排程: %w", err)
+	}
+	return &schedule, nil
+}
+
+// GetSchedule 取得單一排程
+func (s *SchedulerService) GetSchedule(userID, scheduleID string) (*model.Schedule, error) {
+	schedule, err := s.repo.Get(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// ListSchedules 列出使用者的排程，依建立時間排序
+func (s *SchedulerService) ListSchedules(userID string) ([]model.Schedule, error) {
+	schedules, err := s.repo.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+	return schedules, nil
+}
+
+// DeleteSchedule 刪除排程與其發送紀錄
+func (s *SchedulerService) DeleteSchedule(userID, scheduleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.GetSchedule(userID, scheduleID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(userID, scheduleID); err != nil {
+		return err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(scheduleID)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := s.repo.DeleteDelivery(scheduleID, delivery.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunNow 立即執行一次排程（不檢查假日），發送在背景進行，返回初始的發送紀錄；
+// 與排程執行相同，距離上次執行需間隔至少 minScheduleInterval
+func (s *SchedulerService) RunNow(userID, scheduleID string) (*model.Delivery, error) {
+	schedule, err := s.GetSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.LastRunAt != nil {
+		if wait := minScheduleInterval - time.Since(*schedule.LastRunAt); wait > 0 {
+			return nil, fmt.Errorf("%w: 請在 %d 分鐘後再試", ErrScheduleTooSoon, int(wait.Minutes())+1)
+		}
+	}
+
+	delivery, err := s.begin(*schedule, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	go s.execute(*schedule, delivery, true)
+	return &delivery, nil
+}
+
+// ListDeliveries 列出排程的發送紀錄，由新到舊排序
+func (s *SchedulerService) ListDeliveries(userID, scheduleID string) ([]model.Delivery, error) {
+	if _, err := s.GetSchedule(userID, scheduleID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	sortDeliveries(deliveries)
+	return deliveries, nil
+}
+
+// 檢查並啟動所有到期的排程，錯過太久的執行記為略過
+func (s *SchedulerService) runDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules, err := s.repo.ListAll()
+	if err != nil {
+		fmt.Printf("排程器無法載入排程: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		if !schedule.Enabled || schedule.NextRunAt.IsZero() || schedule.NextRunAt.After(now) {
+			continue
+		}
+
+		scheduledFor := schedule.NextRunAt
+		if err := s.advance(&schedule, now); err != nil {
+			fmt.Printf("排程 %s 無法計算下次執行時間: %v\n", schedule.ID, err)
+			continue
+		}
+
+		if now.Sub(scheduledFor) > scheduleMisfireGrace {
+			delivery, err := newDelivery(schedule.ID, scheduledFor)
+			if err != nil {
+				fmt.Printf("排程 %s 無法建立發送紀錄: %v\n", schedule.ID, err)
+				continue
+			}
+			delivery.Status = model.DeliveryStatusSkipped
+			delivery.Error = "錯過執行時間（伺服器未運行）"
+			delivery.CreatedAt = now
+			s.record(delivery)
+			continue
+		}
+
+		if s.running[schedule.ID] {
+			fmt.Printf("排程 %s 上次執行尚未完成，略過 %s\n", schedule.ID, scheduledFor.Format(time.RFC3339))
+			continue
+		}
+		delivery, err := newDelivery(schedule.ID, scheduledFor)
+		if err != nil {
+			fmt.Printf("排程 %s 無法建立發送紀錄: %v\n", schedule.ID, err)
+			continue
+		}
+		s.running[schedule.ID] = true
+		s.record(delivery)
+		go s.execute(schedule, delivery, false)
+	}
+}
+
+// 排程的下次執行時間往後推並保存（需持有 s.mu）
+func (s *SchedulerService) advance(schedule *model.Schedule, now time.Time) error {
+	next, err := nextRun(*schedule, now)
+	if err != nil {
+		return err
+	}
+	schedule.NextRunAt = next
+	return s.repo.Save(*schedule)
+}
+
+// 標記排程為執行中並建立發送紀錄，排程已在執行時返回錯誤
+func (s *SchedulerService) begin(schedule model.Schedule, scheduledFor time.Time) (model.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[schedule.ID] {
+		return model.Delivery{}, fmt.Errorf("%w: 排程正在執行中", ErrInvalidSchedule)
+	}
+	delivery, err := newDelivery(schedule.ID, scheduledFor)
+	if err != nil {
+		return model.Delivery{}, err
+	}
+	s.running[schedule.ID] = true
+	s.record(delivery)
+	return delivery, nil
+}
+
+// 執行推薦並發送，失敗時以指數退避重試
+func (s *SchedulerService) execute(schedule model.Schedule, delivery model.Delivery, manual bool) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, schedule.ID)
+		s.finish(schedule, delivery)
+		s.mu.Unlock()
+	}()
+
+	location := s.location(schedule.Timezone)
+	if date := delivery.ScheduledFor.In(location).Format("2006-01-02"); !manual && s.holidays[date] {
+		s.skip(&delivery, fmt.Sprintf("%s 為假日，不發送", date))
+		return
+	}
+
+	var restaurants []model.Restaurant
+	delay := s.cfg.RetryDelay
+	for {
+		err := s.attempt(schedule, &delivery, &restaurants)
+		if err == nil || delivery.Status == model.DeliveryStatusSkipped {
+			return
+		}
+
+		delivery.Error = err.Error()
+		// 目標位址被拒絕時重試也不會成功
+		if delivery.Attempts >= s.cfg.MaxAttempts || errors.Is(err, model.ErrBlockedAddress) {
+			delivery.Status = model.DeliveryStatusFailed
+			fmt.Printf("排程 %s 發送失敗（已嘗試 %d 次）: %v\n", schedule.ID, delivery.Attempts, err)
+			return
+		}
+
+		s.mu.Lock()
+		s.record(delivery)
+		s.mu.Unlock()
+		fmt.Printf("排程 %s 第 %d 次發送失敗，%s 後重試: %v\n", schedule.ID, delivery.Attempts, delay, err)
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-s.stop:
+			delivery.Status = model.DeliveryStatusFailed
+			return
+		}
+	}
+}
+
+// 單次嘗試：尚未取得推薦時先執行推薦流程，之後發送到目標
+func (s *SchedulerService) attempt(schedule model.Schedule, delivery *model.Delivery, restaurants *[]model.Restaurant) error {
+	ctx, cancel := context.WithTimeout(context.Background(), scheduleRunTimeout)
+	defer cancel()
+
+	if *restaurants == nil {
+		// 每日額度用盡時不再重試，直接略過本次推送
+		if err := s.counterService.CheckDailyLimit(); err != nil {
+			s.skip(delivery, err.Error())
+			return nil
+		}
+
+		delivery.Attempts++
+		result, err := s.restaurantService.RecommendRestaurants(ctx, model.RecommendQuery{
+			Lat:            schedule.Lat,
+			Lng:            schedule.Lng,
+			RestaurantType: schedule.RestaurantType,
+			Budget:         schedule.Budget,
+			Count:          schedule.Count,
+			Language:       i18n.LangZhTW,
+		})
+		s.counterService.LogAPIRequest("scheduler", schedule.Lat, schedule.Lng, schedule.RestaurantType, err == nil, errorText(err))
+		if err != nil {
+			return fmt.Errorf("推薦失敗: %w", err)
+		}
+		if len(result.Restaurants) == 0 {
+			s.skip(delivery, "附近找不到符合條件的餐廳")
+			return nil
+		}
+
+		*restaurants = result.Restaurants
+		for _, r := range result.Restaurants {
+			delivery.Restaurants = append(delivery.Restaurants, r.Name)
+		}
+	} else {
+		delivery.Attempts++
+	}
+
+	if err := s.send(ctx, schedule, delivery.ScheduledFor, *restaurants); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	delivery.Status = model.DeliveryStatusDelivered
+	delivery.Error = ""
+	delivery.DeliveredAt = &now
+	fmt.Printf("排程 %s 已推送 %d 家餐廳到 %s\n", schedule.ID, len(*restaurants), schedule.Target.Type)
+	return nil
+}
+
+// 依目標類型組成訊息並發送
+func (s *SchedulerService) send(ctx context.Context, schedule model.Schedule, scheduledFor time.Time, restaurants []model.Restaurant) error {
+	title := scheduleTitle(schedule)
+
+	switch schedule.Target.Type {
+	case model.ScheduleTargetLine:
+		if s.linePusher == nil {
+			return errors.New("未設定 LINE_CHANNEL_ACCESS_TOKEN")
+		}
+		return s.linePusher.Push(ctx, schedule.Target.To, []map[string]any{
+			textMessage(title),
+			restaurantCarousel(restaurants),
+		})
+
+	case model.ScheduleTargetSlack:
+		return s.webhooks.PostJSON(ctx, schedule.Target.URL, map[string]any{
+			"text":   title,
+			"blocks": scheduleSlackBlocks(title, restaurants),
+		})
+
+	default:
+		return s.webhooks.PostJSON(ctx, schedule.Target.URL, map[string]any{
+			"schedule_id":   schedule.ID,
+			"name":          schedule.Name,
+			"scheduled_for": scheduledFor,
+			"text":          title,
+			"restaurants":   restaurants,
+		})
+	}
+}
+
+// 保存最終的發送紀錄、更新排程的上次執行時間，並清除過舊的紀錄（需持有 s.mu）
+func (s *SchedulerService) finish(schedule model.Schedule, delivery model.Delivery) {
+	s.record(delivery)
+
+	// 執行期間排程可能已被修改或刪除，重新讀取後只更新上次執行時間
+	current, err := s.repo.Get(schedule.UserID, schedule.ID)
+	if err != nil || current == nil {
+		return
+	}
+	now := time.Now()
+	current.LastRunAt = &now
+	if err := s.repo.Save(*current); err != nil {
+		fmt.Printf("警告: 無法更新排程 %s: %v\n", schedule.ID, err)
+	}
+
+	if s.cfg.DeliveryHistory <= 0 {
+		return
+	}
+	deliveries, err := s.repo.ListDeliveries(schedule.ID)
+	if err != nil || len(deliveries) <= s.cfg.DeliveryHistory {
+		return
+	}
+	sortDeliveries(deliveries)
+	for _, old := range deliveries[s.cfg.DeliveryHistory:] {
+		if err := s.repo.DeleteDelivery(schedule.ID, old.ID); err != nil {
+			fmt.Printf("警告: 無法刪除發送紀錄 %s: %v\n", old.ID, err)
+		}
+	}
+}
+
+func (s *SchedulerService) record(delivery model.Delivery) {
+	if err := s.repo.SaveDelivery(delivery); err != nil {
+		fmt.Printf("警告: 無法保存發送紀錄 %s: %v\n", delivery.ID, err)
+	}
+}
+
+func (s *SchedulerService) skip(delivery *model.Delivery, reason string) {
+	delivery.Status = model.DeliveryStatusSkipped
+	delivery.Error = reason
+	fmt.Printf("排程 %s 略過 %s: %s\n", delivery.ScheduleID, delivery.ScheduledFor.Format(time.RFC3339), reason)
+}
+
+// 驗證排程設定、補上預設值並計算下次執行時間
+func (s *SchedulerService) prepare(schedule *model.Schedule) error {
+	if schedule.Timezone == "" {
+		schedule.Timezone = s.cfg.Timezone
+	}
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		return fmt.Errorf("%w: 無效的時區 %q", ErrInvalidSchedule, schedule.Timezone)
+	}
+
+	cron, err := parseCron(schedule.Cron)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	if err := checkInterval(cron, s.location(schedule.Timezone)); err != nil {
+		return err
+	}
+
+	if !finite(schedule.Lat, schedule.Lng) || schedule.Lat < -90 || schedule.Lat > 90 || schedule.Lng < -180 || schedule.Lng > 180 || (schedule.Lat == 0 && schedule.Lng == 0) {
+		return fmt.Errorf("%w: 無效的位置", ErrInvalidSchedule)
+	}
+	if schedule.Budget < -1 || schedule.Budget > 4 {
+		return fmt.Errorf("%w: 預算需為 0-4 的價格等級，或 -1 表示不限", ErrInvalidSchedule)
+	}
+	if schedule.Count <= 0 {
+		schedule.Count = defaultRecommendCount
+	}
+	if schedule.Count > maxScheduleCount {
+		return fmt.Errorf("%w: 每次最多推送 %d 家餐廳", ErrInvalidSchedule, maxScheduleCount)
+	}
+
+	if err := s.validateTarget(schedule.UserID, schedule.Target); err != nil {
+		return err
+	}
+
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if schedule.Name == "" {
+		schedule.Name = "午餐推薦"
+	}
+
+	schedule.NextRunAt = time.Time{}
+	if schedule.Enabled {
+		next, err := nextRun(*schedule, time.Now())
+		if err != nil {
+			return err
+		}
+		schedule.NextRunAt = next
+	}
+	return nil
+}
+
+// 檢查發送目標；LINE 只能推送給使用者自己以 LINE Login 綁定的帳號，
+// webhook 連到內部網路位址的情況由 WebhookPoster 在連線時拒絕
+func (s *SchedulerService) validateTarget(userID string, target model.ScheduleTarget) error {
+	switch target.Type {
+	case model.ScheduleTargetWebhook, model.ScheduleTargetSlack:
+		parsed, err := url.Parse(target.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("%w: 目標需提供有效的 http(s) 網址", ErrInvalidSchedule)
+		}
+	case model.ScheduleTargetLine:
+		if target.To == "" {
+			return fmt.Errorf("%w: LINE 目標需提供 to（自己的 LINE 使用者 ID）", ErrInvalidSchedule)
+		}
+		if s.linePusher == nil {
+			return fmt.Errorf("%w: 伺服器未設定 LINE_CHANNEL_ACCESS_TOKEN", ErrInvalidSchedule)
+		}
+		if s.identities == nil {
+			return fmt.Errorf("%w: LINE 目標需先以 LINE 帳號登入", ErrInvalidSchedule)
+		}
+		subjects, err := s.identities.LinkedSubjects(userID, lineIdentityProvider)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(subjects, target.To) {
+			return fmt.Errorf("%w: LINE 目標只能是自己綁定的 LINE 帳號", ErrInvalidSchedule)
+		}
+	default:
+		return fmt.Errorf("%w: 目標類型僅支援 webhook、line 或 slack", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+func (s *SchedulerService) location(timezone string) *time.Location {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.FixedZone("CST", 8*60*60)
+	}
+	return location
+}
+
+// 計算 after 之後的下次執行時間
+func nextRun(schedule model.Schedule, after time.Time) (time.Time, error) {
+	cron, err := parseCron(schedule.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: 無效的時區 %q", ErrInvalidSchedule, schedule.Timezone)
+	}
+
+	next, ok := cron.Next(after, location)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: cron 表示式 %q 不會觸發", ErrInvalidSchedule, schedule.Cron)
+	}
+	return next, nil
+}
+
+// 檢查接下來一天內的執行間隔，避免每分鐘執行這類耗盡額度的排程
+func checkInterval(cron *cronSchedule, location *time.Location) error {
+	previous, ok := cron.Next(time.Now(), location)
+	if !ok {
+		return fmt.Errorf("%w: cron 表示式不會觸發", ErrInvalidSchedule)
+	}
+
+	limit := previous.Add(24 * time.Hour)
+	for {
+		next, ok := cron.Next(previous, location)
+		if !ok || next.After(limit) {
+			return nil
+		}
+		if next.Sub(previous) < minScheduleInterval {
+			return fmt.Errorf("%w: 兩次執行需間隔至少 %d 分鐘", ErrInvalidSchedule, int(minScheduleInterval.Minutes()))
+		}
+		previous = next
+	}
+}
+
+func newDelivery(scheduleID string, scheduledFor time.Time) (model.Delivery, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return model.Delivery{}, err
+	}
+	return model.Delivery{
+		ID:           id,
+		ScheduleID:   scheduleID,
+		ScheduledFor: scheduledFor,
+		Status:       model.DeliveryStatusPending,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+func sortDeliveries(deliveries []model.Delivery) {
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+}
+
+func scheduleTitle(schedule model.Schedule) string {
+	if schedule.RestaurantType != "" {
+		return fmt.Sprintf("🍱 %s：今天吃%s？", schedule.Name, schedule.RestaurantType)
+	}
+	return fmt.Sprintf("🍱 %s：今天吃什麼？", schedule.Name)
+}
+
+// Slack incoming webhook 的 Block Kit 內容
+func scheduleSlackBlocks(title string, restaurants []model.Restaurant) []map[string]any {
+	blocks := []map[string]any{slackSection("*" + title + "*"), {"type": "divider"}}
+	for _, r := range restaurants {
+		text := fmt.Sprintf("*<%s|%s>*\n★ %.1f・%s", repository.GoogleMapsURL(r.Name, r.PlaceID), r.Name, r.Rating, r.Distance)
+		if price := formatLinePrice(r); price != "" {
+			text += "・" + price
+		}
+		blocks = append(blocks, slackSection(text))
+	}
+	return blocks
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
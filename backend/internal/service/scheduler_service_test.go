@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+type nopLinePusher struct{}
+
+func (nopLinePusher) Push(context.Context, string, []map[string]any) error { return nil }
+
+type nopWebhookPoster struct{}
+
+func (nopWebhookPoster) PostJSON(context.Context, string, any) error { return nil }
+
+// 以使用者 ID 對應綁定的 LINE 使用者 ID
+type staticIdentities map[string][]string
+
+func (s staticIdentities) LinkedSubjects(userID, provider string) ([]string, error) {
+	if provider != lineIdentityProvider {
+		return nil, nil
+	}
+	return s[userID], nil
+}
+
+func newTestSchedulerService(maxPerUser int) *SchedulerService {
+	identities := staticIdentities{"acct_line": {"Uline-owner"}}
+	return NewSchedulerService(repository.NewScheduleRepository(repository.NewMemoryStore()), nil, nil, nopLinePusher{}, nopWebhookPoster{}, identities, config.SchedulerConfig{
+		Timezone:   "Asia/Taipei",
+		MaxPerUser: maxPerUser,
+	})
+}
+
+func testSchedule(target model.ScheduleTarget) model.Schedule {
+	return model.Schedule{Cron: "30 11 * * 1-5", Lat: 25.033, Lng: 121.565, Target: target, Enabled: true}
+}
+
+var testWebhookTarget = model.ScheduleTarget{Type: model.ScheduleTargetWebhook, URL: "https://hooks.example.com/lunch"}
+
+func TestScheduleLineTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		to      string
+		wantErr error
+	}{
+		{name: "推送給自己綁定的 LINE", userID: "acct_line", to: "Uline-owner"},
+		{name: "推送給其他 LINE 使用者", userID: "acct_line", to: "Uother", wantErr: ErrInvalidSchedule},
+		{name: "推送給群組", userID: "acct_line", to: "Cgroup", wantErr: ErrInvalidSchedule},
+		{name: "未綁定 LINE 的帳號", userID: "acct_other", to: "Uline-owner", wantErr: ErrInvalidSchedule},
+		{name: "匿名使用者", userID: "device-1", to: "Uline-owner", wantErr: ErrInvalidSchedule},
+		{name: "未提供 to", userID: "acct_line", to: "", wantErr: ErrInvalidSchedule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSchedulerService(10)
+			_, err := s.CreateSchedule(tt.userID, testSchedule(model.ScheduleTarget{Type: model.ScheduleTargetLine, To: tt.to}))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleLimitPerUser(t *testing.T) {
+	s := newTestSchedulerService(2)
+	var created []*model.Schedule
+	for range 2 {
+		schedule, err := s.CreateSchedule("user-1", testSchedule(testWebhookTarget))
+		if err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, schedule)
+	}
+
+	if _, err := s.CreateSchedule("user-1", testSchedule(testWebhookTarget)); !errors.Is(err, ErrScheduleLimit) {
+		t.Fatalf("third schedule: err = %v, want ErrScheduleLimit", err)
+	}
+	// 上限以使用者為單位
+	if _, err := s.CreateSchedule("user-2", testSchedule(testWebhookTarget)); err != nil {
+		t.Errorf("other user: %v", err)
+	}
+	// 刪除後可以再新增
+	if err := s.DeleteSchedule("user-1", created[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateSchedule("user-1", testSchedule(testWebhookTarget)); err != nil {
+		t.Errorf("after delete: %v", err)
+	}
+}
+
+func TestRunNowRespectsMinimumInterval(t *testing.T) {
+	s := newTestSchedulerService(10)
+	schedule, err := s.CreateSchedule("user-1", testSchedule(testWebhookTarget))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastRun := time.Now().Add(-time.Minute)
+	schedule.LastRunAt = &lastRun
+	if err := s.repo.Save(*schedule); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.RunNow("user-1", schedule.ID); !errors.Is(err, ErrScheduleTooSoon) {
+		t.Errorf("err = %v, want ErrScheduleTooSoon", err)
+	}
+	if deliveries, _ := s.ListDeliveries("user-1", schedule.ID); len(deliveries) != 0 {
+		t.Errorf("rejected run recorded %d deliveries, want 0", len(deliveries))
+	}
+	if _, err := s.RunNow("user-2", schedule.ID); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("other user: err = %v, want ErrScheduleNotFound", err)
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+const (
+	// 評分下限，避免分數為 0 的餐廳完全沒有機會被抽中
+	minDrawWeight = 0.01
+
+	// 貝氏調整後評分分數達此值視為高評價 (約 4.4 分)
+	highlyRatedScore = 0.6
+
+	// 此距離內視為「距離近」（公尺）
+	closeByMeters = 500
+)
+
+// Scorer 依評價、距離、價格符合度與隨機擾動計算餐廳分數
+type Scorer struct {
+	cfg config.ScoringConfig
+}
+
+func NewScorer(cfg config.ScoringConfig) *Scorer {
+	return &Scorer{cfg: cfg}
+}
+
+// Score 計算單一餐廳的加權分數 (0-1 之間的各項分數乘上權重後相加)
+func (s *Scorer) Score(r model.Restaurant, budget int) float64 {
+	return s.cfg.RatingWeight*s.ratingScore(r) +
+		s.cfg.DistanceWeight*s.distanceScore(r) +
+		s.cfg.PriceWeight*priceFitScore(r.PriceLevel, budget) +
+		s.cfg.JitterWeight*rand.Float64()
+}
+
+// 以貝氏平均調整評分：評論數少的餐廳會被拉向先驗評分
+func (s *Scorer) ratingScore(r model.Restaurant) float64 {
+	v := float64(r.UserRatingsTotal)
+	m := s.cfg.PriorReviews
+	if v+m <= 0 {
+		return 0
+	}
+	adjusted := (v/(v+m))*float64(r.Rating) + (m/(v+m))*s.cfg.PriorRating
+
+	// 搜尋結果已過濾 3.5 分以下的餐廳，將 3.5-5 映射到 0-1
+	return clamp01((adjusted - 3.5) / 1.5)
+}
+
+// 距離以指數衰減計分，越近分數越高
+func (s *Scorer) distanceScore(r model.Restaurant) float64 {
+	if s.cfg.DistanceDecayMeters <= 0 {
+		return 1
+	}
+	return math.Exp(-r.DistanceMeters / s.cfg.DistanceDecayMeters)
+}
+
+// 價格符合度：低於預算小幅扣分，高於預算大幅扣分
+func priceFitScore(priceLevel, budget int) float64 {
+	// 未指定預算時不影響排序
+	if budget < 0 {
+		return 1
+	}
+	// Google 未提供價格等級時解碼為 0，與其他功能一致視為未知，給予中間值
+	if priceLevel <= 0 {
+		return 0.5
+	}
+
+	diff := priceLevel - budget
+	if diff <= 0 {
+		return clamp01(1 - 0.15*float64(-diff))
+	}
+	return clamp01(1 - 0.5*float64(diff))
+}
+
+// WeightedDraw 依分數比例不放回地抽出 count 家餐廳
+// personalization 不為 nil 時，分數再乘上使用者偏好的倍率
+func (s *Scorer) WeightedDraw(restaurants []model.Restaurant, budget, count int, personalization *Personalization) []model.Restaurant {
+	pool := make([]model.Restaurant, len(restaurants))
+	copy(pool, restaurants)
+
+	for i := range pool {
+		score := s.Score(pool[i], budget) * personalization.Multiplier(pool[i])
+		pool[i].Score = math.Round(score*1000) / 1000
+	}
+
+	return drawByWeight(pool, count, func(r model.Restaurant) float64 {
+		return r.Score
+	})
+}
+
+// 依權重比例不放回地抽出 count 家餐廳（會修改 pool）
+func drawByWeight(pool []model.Restaurant, count int, weight func(model.Restaurant) float64) []model.Restaurant {
+	if count > len(pool) {
+		count = len(pool)
+	}
+
+	picks := make([]model.Restaurant, 0, count)
+	for len(picks) < count {
+		total := 0.0
+		for _, r := range pool {
+			total += math.Max(weight(r), minDrawWeight)
+		}
+
+		target := rand.Float64() * total
+		index := len(pool) - 1
+		for i, r := range pool {
+			target -= math.Max(weight(r), minDrawWeight)
+			if target <= 0 {
+				index = i
+				break
+			}
+		}
+
+		picks = append(picks, pool[index])
+		pool = append(pool[:index], pool[index+1:]...)
+	}
+
+	return picks
+}
+
+// Explain 產生計分階段的推薦理由（高評價、距離近、符合預算）
+func (s *Scorer) Explain(r model.Restaurant, budget int) []model.Reason {
+	var reasons []model.Reason
+
+	switch {
+	case s.ratingScore(r) >= highlyRatedScore:
+		reasons = append(reasons, model.Reason{
+			Code:  "highly_rated",
+			Stage: model.StageScoring,
+			Params: map[string]string{
+				"rating":  fmt.Sprintf("%.1f", r.Rating),
+				"reviews": formatCount(r.UserRatingsTotal),
+			},
+		})
+	case r.Rating >= 4.0:
+		reasons = append(reasons, model.Reason{
+			Code:   "well_rated",
+			Stage:  model.StageScoring,
+			Params: map[string]string{"rating": fmt.Sprintf("%.1f", r.Rating)},
+		})
+	}
+
+	if r.DistanceMeters > 0 && r.DistanceMeters <= closeByMeters {
+		// 以 100 公尺為單位無條件進位，如 "within 300 m"
+		rounded := math.Ceil(r.DistanceMeters/100) * 100
+		reasons = append(reasons, model.Reason{
+			Code:   "within",
+			Stage:  model.StageScoring,
+			Params: map[string]string{"distance": repository.FormatDistance(rounded)},
+		})
+	}
+
+	if budget >= 0 && r.PriceLevel > 0 && r.PriceLevel <= budget {
+		reasons = append(reasons, model.Reason{
+			Code:   "fits_budget",
+			Stage:  model.StageScoring,
+			Params: map[string]string{"price": strings.Repeat("$", r.PriceLevel)},
+		})
+	}
+
+	return reasons
+}
+
+// 將評論數格式化為精簡字串 (如: 1234 -> "1.2k")
+func formatCount(n int) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d", n)
+	}
+	return fmt.Sprintf("%.1fk", float64(n)/1000)
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"maps"
+	"math"
+	"testing"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+)
+
+func testScoringConfig() config.ScoringConfig {
+	return config.ScoringConfig{
+		RatingWeight:        0.4,
+		DistanceWeight:      0.3,
+		PriceWeight:         0.2,
+		PriorRating:         4.0,
+		PriorReviews:        50,
+		DistanceDecayMeters: 800,
+	}
+}
+
+func TestPriceFitScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		priceLevel int
+		budget     int
+		want       float64
+	}{
+		{"未指定預算", 3, -1, 1},
+		{"價格未知", 0, 2, 0.5},
+		{"價格未知且預算 0", 0, 0, 0.5},
+		{"預算 0 時等級 1 超出", 1, 0, 0.5},
+		{"剛好符合預算", 2, 2, 1},
+		{"低於預算一級", 1, 2, 0.85},
+		{"高於預算一級", 3, 2, 0.5},
+		{"高於預算兩級", 4, 2, 0},
+		{"高於預算三級仍不低於 0", 4, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := priceFitScore(tt.priceLevel, tt.budget); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("priceFitScore(%d, %d) = %v, want %v", tt.priceLevel, tt.budget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRatingScore(t *testing.T) {
+	scorer := NewScorer(testScoringConfig())
+	tests := []struct {
+		name    string
+		rating  float32
+		reviews int
+		want    float64
+	}{
+		{"沒有評論時等於先驗評分", 5, 0, 1.0 / 3},
+		{"評論數等於先驗評論數", 5, 50, 2.0 / 3},
+		{"評論數多時接近實際評分", 4.7, 100000, 0.7993},
+		{"低於 3.5 分為 0", 3.0, 100000, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scorer.ratingScore(model.Restaurant{Rating: tt.rating, UserRatingsTotal: tt.reviews})
+			if math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("ratingScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceScore(t *testing.T) {
+	scorer := NewScorer(testScoringConfig())
+	tests := []struct {
+		meters float64
+		want   float64
+	}{
+		{0, 1},
+		{800, math.Exp(-1)},
+		{1600, math.Exp(-2)},
+	}
+	for _, tt := range tests {
+		if got := scorer.distanceScore(model.Restaurant{DistanceMeters: tt.meters}); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("distanceScore(%v) = %v, want %v", tt.meters, got, tt.want)
+		}
+	}
+
+	noDecay := NewScorer(config.ScoringConfig{})
+	if got := noDecay.distanceScore(model.Restaurant{DistanceMeters: 5000}); got != 1 {
+		t.Errorf("distanceScore without decay = %v, want 1", got)
+	}
+}
+
+func TestWeightedDrawPicksDistinctRestaurants(t *testing.T) {
+	scorer := NewScorer(testScoringConfig())
+	candidates := []model.Restaurant{
+		{PlaceID: "a", Rating: 4.8, UserRatingsTotal: 500, DistanceMeters: 100, PriceLevel: 2},
+		{PlaceID: "b", Rating: 4.0, UserRatingsTotal: 20, DistanceMeters: 900, PriceLevel: 4},
+		{PlaceID: "c", Rating: 3.6, UserRatingsTotal: 5, DistanceMeters: 2000, PriceLevel: 0},
+		{PlaceID: "d", Rating: 4.4, UserRatingsTotal: 80, DistanceMeters: 300, PriceLevel: 1},
+	}
+
+	for range 50 {
+		picks := scorer.WeightedDraw(candidates, 2, 3, nil)
+		if len(picks) != 3 {
+			t.Fatalf("got %d picks, want 3", len(picks))
+		}
+		seen := make(map[string]bool)
+		for _, pick := range picks {
+			if seen[pick.PlaceID] {
+				t.Fatalf("duplicate pick %s", pick.PlaceID)
+			}
+			seen[pick.PlaceID] = true
+			if pick.Score <= 0 {
+				t.Errorf("pick %s has no score", pick.PlaceID)
+			}
+		}
+	}
+
+	if picks := scorer.WeightedDraw(candidates, -1, 10, nil); len(picks) != len(candidates) {
+		t.Errorf("drawing more than available: got %d picks, want %d", len(picks), len(candidates))
+	}
+	if candidates[0].Score != 0 {
+		t.Error("WeightedDraw modified the input slice")
+	}
+}
+
+func TestExplainFitsBudget(t *testing.T) {
+	scorer := NewScorer(testScoringConfig())
+	tests := []struct {
+		name       string
+		priceLevel int
+		budget     int
+		wantPrice  string
+	}{
+		{"價格未知", 0, 1, ""},
+		{"等級 1", 1, 1, "$"},
+		{"等級 2", 2, 2, "$$"},
+		{"超過預算", 3, 2, ""},
+		{"未指定預算", 1, -1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			for _, reason := range scorer.Explain(model.Restaurant{PriceLevel: tt.priceLevel}, tt.budget) {
+				if reason.Code == "fits_budget" {
+					got = reason.Params["price"]
+				}
+			}
+			if got != tt.wantPrice {
+				t.Errorf("fits_budget price = %q, want %q", got, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestExplainReasons(t *testing.T) {
+	scorer := NewScorer(testScoringConfig())
+	tests := []struct {
+		name       string
+		restaurant model.Restaurant
+		budget     int
+		want       map[string]map[string]string // 理由代碼 -> 參數
+	}{
+		{
+			name:       "高評價、距離近且符合預算",
+			restaurant: model.Restaurant{Rating: 4.7, UserRatingsTotal: 1234, DistanceMeters: 230, PriceLevel: 2},
+			budget:     2,
+			want: map[string]map[string]string{
+				"highly_rated": {"rating": "4.7", "reviews": "1.2k"},
+				"within":       {"distance": "300m"},
+				"fits_budget":  {"price": "$$"},
+			},
+		},
+		{
+			name:       "評價不錯",
+			restaurant: model.Restaurant{Rating: 4.1, UserRatingsTotal: 30, DistanceMeters: 800},
+			budget:     -1,
+			want:       map[string]map[string]string{"well_rated": {"rating": "4.1"}},
+		},
+		{
+			name:       "沒有理由",
+			restaurant: model.Restaurant{Rating: 3.8, UserRatingsTotal: 30, DistanceMeters: 501, PriceLevel: 3},
+			budget:     2,
+			want:       map[string]map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := scorer.Explain(tt.restaurant, tt.budget)
+			got := make(map[string]map[string]string, len(reasons))
+			for _, reason := range reasons {
+				if reason.Stage != model.StageScoring {
+					t.Errorf("%s stage = %s, want %s", reason.Code, reason.Stage, model.StageScoring)
+				}
+				got[reason.Code] = reason.Params
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("reasons = %v, want %v", got, tt.want)
+			}
+			for code, params := range tt.want {
+				if !maps.Equal(got[code], params) {
+					t.Errorf("%s params = %v, want %v", code, got[code], params)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/ogcard"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ShareCard 繪製好的預覽卡片
+type ShareCard struct {
+	PNG       []byte
+	ETag      string
+	ExpiresAt time.Time
+}
+
+// CardRenderer 將分享繪製成預覽卡片 PNG
+type CardRenderer interface {
+	Render(share model.Share) ([]byte, error)
+}
+
+// ShareCardService 產生分享連結的預覽頁面與卡片圖片，卡片繪製成本較高因此快取
+type ShareCardService struct {
+	shareService *ShareService
+	renderer     CardRenderer
+	cfg          config.ShareConfig
+
+	mu    sync.Mutex
+	cache map[string]*ShareCard
+	order []string // 依加入順序記錄快取鍵，超過上限時淘汰最舊的
+
+	// 同一張卡片同時被多個預覽爬蟲請求時只繪製一次
+	renders singleflight.Group
+}
+
+func NewShareCardService(shareService *ShareService, renderer CardRenderer, cfg config.ShareConfig) *ShareCardService {
+	return &ShareCardService{
+		shareService: shareService,
+		renderer:     renderer,
+		cfg:          cfg,
+		cache:        make(map[string]*ShareCard, cfg.CardCacheSize),
+	}
+}
+
+// RenderPage 產生含 Open Graph 標籤的分享頁面
+func (s *ShareCardService) RenderPage(id string) ([]byte, error) {
+	share, err := s.shareService.GetShare(id)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimRight(s.cfg.PublicBaseURL, "/")
+	pageURL := base + "/s/" + url.PathEscape(share.ID)
+	return ogcard.RenderPage(ogcard.Page{
+		Share:    *share,
+		PageURL:  pageURL,
+		ImageURL: pageURL + "/card.png",
+		AppURL:   s.appURL(share.ID),
+	})
+}
+
+// GetCard 取得分享的預覽卡片，快取中沒有時才重新繪製，同一分享的並行請求共用同一次繪製
+func (s *ShareCardService) GetCard(id string) (*ShareCard, error) {
+	if card := s.cached(id); card != nil {
+		return card, nil
+	}
+
+	card, err, _ := s.renders.Do(id, func() (any, error) {
+		// 等待期間前一次繪製可能已完成並寫入快取
+		if card := s.cached(id); card != nil {
+			return card, nil
+		}
+		return s.render(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return card.(*ShareCard), nil
+}
+
+// 繪製卡片並寫入快取
+func (s *ShareCardService) render(id string) (*ShareCard, error) {
+	share, err := s.shareService.GetShare(id)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	data, err := s.renderer.Render(*share)
+	if err != nil {
+		return nil, fmt.Errorf("無法繪製預覽卡片: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	card := &ShareCard{
+		PNG:       data,
+		ETag:      `"` + hex.EncodeToString(sum[:8]) + `"`,
+		ExpiresAt: share.ExpiresAt,
+	}
+	fmt.Printf("繪製分享 %s 的預覽卡片: %d bytes，耗時 %v\n", id, len(data), time.Since(start))
+
+	s.store(id, card)
+	return card, nil
+}
+
+func (s *ShareCardService) cached(id string) *ShareCard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	card, found := s.cache[id]
+	if !found {
+		return nil
+	}
+	// 分享已過期時交由 GetShare 處理（刪除並返回 ErrShareExpired）
+	if time.Now().After(card.ExpiresAt) {
+		delete(s.cache, id)
+		return nil
+	}
+	return card
+}
+
+func (s *ShareCardService) store(id string, card *ShareCard) {
+	if s.cfg.CardCacheSize <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.cache[id]; !found {
+		s.order = append(s.order, id)
+	}
+	s.cache[id] = card
+
+	// 淘汰最舊的卡片；order 中可能有已因過期刪除的鍵，一併略過
+	for len(s.cache) > s.cfg.CardCacheSize && len(s.order) > 0 {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		delete(s.cache, oldest)
+	}
+	if len(s.order) > 2*s.cfg.CardCacheSize {
+		s.compactOrder()
+	}
+}
+
+// 移除 order 中已不在快取的鍵，避免過期刪除造成切片無限增長
+func (s *ShareCardService) compactOrder() {
+	order := make([]string, 0, len(s.cache))
+	seen := make(map[string]bool, len(s.cache))
+	for _, id := range s.order {
+		if _, found := s.cache[id]; found && !seen[id] {
+			seen[id] = true
+			order = append(order, id)
+		}
+	}
+	s.order = order
+}
+
+// 前端開啟分享的網址，保留 AppURL 原有的查詢參數
+func (s *ShareCardService) appURL(id string) string {
+	u, err := url.Parse(s.cfg.AppURL)
+	if err != nil {
+		return s.cfg.AppURL
+	}
+	query := u.Query()
+	query.Set("share", id)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// 計算繪製次數的卡片繪製器，每次繪製耗時 delay
+type countingRenderer struct {
+	renders atomic.Int32
+	delay   time.Duration
+}
+
+func (r *countingRenderer) Render(share model.Share) ([]byte, error) {
+	r.renders.Add(1)
+	time.Sleep(r.delay)
+	return []byte("png:" + share.ID), nil
+}
+
+func TestShareCardRendersOncePerShare(t *testing.T) {
+	cfg := config.ShareConfig{TTL: time.Hour, CoordinateDecimals: 2, CardCacheSize: 10}
+	shares := NewShareService(repository.NewShareRepository(repository.NewMemoryStore()), cfg)
+	share, err := shares.CreateShare([]model.Restaurant{{PlaceID: "a", Name: "拉麵屋"}}, model.ShareQuery{Lat: 25.03, Lng: 121.56})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renderer := &countingRenderer{delay: 100 * time.Millisecond}
+	s := NewShareCardService(shares, renderer, cfg)
+
+	const callers = 16
+	start := make(chan struct{})
+	cards := make([]*ShareCard, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			card, err := s.GetCard(share.ID)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			cards[i] = card
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if n := renderer.renders.Load(); n != 1 {
+		t.Errorf("rendered %d times for %d concurrent requests, want 1", n, callers)
+	}
+	for i, card := range cards {
+		if card == nil || string(card.PNG) != "png:"+share.ID {
+			t.Fatalf("caller %d got card %v", i, card)
+		}
+	}
+
+	// 快取命中時不再繪製；不存在的分享不會繪製
+	if _, err := s.GetCard(share.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetCard("missing"); err == nil {
+		t.Error("missing share: err = nil")
+	}
+	if n := renderer.renders.Load(); n != 1 {
+		t.Errorf("rendered %d times after cache hit, want 1", n)
+	}
+}
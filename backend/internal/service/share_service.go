@@ -0,0 +1,197 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	ErrShareNotFound = &model.Error{Kind: model.ErrNotFound, Message: "找不到分享的推薦"}
+	ErrShareExpired  = &model.Error{Kind: model.ErrGone, Message: "分享連結已過期"}
+	ErrInvalidShare  = &model.Error{Kind: model.ErrInvalidArgument, Message: fmt.Sprintf("分享內容需包含 1-%d 家有名稱與 place_id 的餐廳", maxSharedRestaurants)}
+)
+
+const (
+	// 分享 ID 的字元集與長度，62^8 約 2.2 × 10^14 種組合
+	shareIDAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	shareIDLength   = 8
+	// 產生 ID 碰撞時的重試次數
+	shareIDAttempts = 5
+	// 每次分享最多的餐廳數量
+	maxSharedRestaurants = 20
+	// 清除過期分享的間隔
+	sharePurgeInterval = time.Hour
+)
+
+// 分享時移除的推薦理由：距離可反推分享者位置，收藏、用餐紀錄與偏好屬於個人資料
+var privateReasonStages = map[string]bool{
+	model.StageFavorites:  true,
+	model.StageHistory:    true,
+	model.StagePreference: true,
+}
+
+var privateReasonCodes = map[string]bool{
+	"within": true,
+}
+
+// ShareService 保存推薦結果的快照並以短 ID 分享
+type ShareService struct {
+	repo *repository.ShareRepository
+	cfg  config.ShareConfig
+}
+
+func NewShareService(repo *repository.ShareRepository, cfg config.ShareConfig) *ShareService {
+	s := &ShareService{repo: repo, cfg: cfg}
+	go s.purgeLoop()
+	return s
+}
+
+// CreateShare 保存推薦結果快照，座標降低精度並移除可反推位置的欄位
+func (s *ShareService) CreateShare(restaurants []model.Restaurant, query model.ShareQuery) (*model.Share, error) {
+	if len(restaurants) == 0 || len(restaurants) > maxSharedRestaurants {
+		return nil, ErrInvalidShare
+	}
+	if !finite(query.Lat, query.Lng) || query.Lat < -90 || query.Lat > 90 || query.Lng < -180 || query.Lng > 180 {
+		return nil, fmt.Errorf("%w: 無效的位置", ErrInvalidShare)
+	}
+
+	sanitized := make([]model.Restaurant, 0, len(restaurants))
+	for _, r := range restaurants {
+		if r.Name == "" || r.PlaceID == "" {
+			return nil, ErrInvalidShare
+		}
+		sanitized = append(sanitized, sanitizeSharedRestaurant(r))
+	}
+
+	query.Lat = roundCoordinate(query.Lat, s.cfg.CoordinateDecimals)
+	query.Lng = roundCoordinate(query.Lng, s.cfg.CoordinateDecimals)
+
+	id, err := s.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	share := model.Share{
+		ID:          id,
+		Restaurants: sanitized,
+		Query:       query,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.cfg.TTL),
+	}
+	if err := s.repo.Save(share); err != nil {
+		return nil, fmt.Errorf("無法保存分享: %w", err)
+	}
+
+	fmt.Printf("建立分享 %s: %d 家餐廳，有效至 %s\n", share.ID, len(share.Restaurants), share.ExpiresAt.Format(time.RFC3339))
+	return &share, nil
+}
+
+// GetShare 取得分享，過期的分享會一併刪除
+func (s *ShareService) GetShare(id string) (*model.Share, error) {
+	share, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if share == nil {
+		return nil, ErrShareNotFound
+	}
+
+	if time.Now().After(share.ExpiresAt) {
+		if err := s.repo.Delete(id); err != nil {
+			fmt.Printf("警告: 無法刪除過期分享 %s: %v\n", id, err)
+		}
+		return nil, ErrShareExpired
+	}
+	return share, nil
+}
+
+// 產生未被使用的短 ID
+func (s *ShareService) newID() (string, error) {
+	for attempt := 0; attempt < shareIDAttempts; attempt++ {
+		id, err := randomShareID()
+		if err != nil {
+			return "", err
+		}
+		exists, err := s.repo.Exists(id)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return id, nil
+		}
+	}
+	return "", errors.New("無法產生分享 ID，請稍後再試")
+}
+
+// 定期清除過期的分享
+func (s *ShareService) purgeLoop() {
+	ticker := time.NewTicker(sharePurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		shares, err := s.repo.List()
+		if err != nil {
+			fmt.Printf("警告: 無法載入分享: %v\n", err)
+			continue
+		}
+
+		now := time.Now()
+		purged := 0
+		for _, share := range shares {
+			if now.After(share.ExpiresAt) {
+				if err := s.repo.Delete(share.ID); err == nil {
+					purged++
+				}
+			}
+		}
+		if purged > 0 {
+			fmt.Printf("已清除 %d 個過期分享\n", purged)
+		}
+	}
+}
+
+// 以 crypto/rand 均勻選取字元，避免取餘數造成的偏差
+func randomShareID() (string, error) {
+	id := make([]byte, shareIDLength)
+	limit := big.NewInt(int64(len(shareIDAlphabet)))
+	for i := range id {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("無法產生分享 ID: %w", err)
+		}
+		id[i] = shareIDAlphabet[n.Int64()]
+	}
+	return string(id), nil
+}
+
+// 移除距離、交通時間、參與者與個人化的推薦理由，保留餐廳本身的公開資訊
+func sanitizeSharedRestaurant(r model.Restaurant) model.Restaurant {
+	r.Distance = ""
+	r.DistanceMeters = 0
+	r.ParticipantDistances = nil
+	r.Travel = nil
+	r.Score = 0
+
+	reasons := make([]model.Reason, 0, len(r.Reasons))
+	for _, reason := range r.Reasons {
+		if privateReasonStages[reason.Stage] || privateReasonCodes[reason.Code] {
+			continue
+		}
+		reasons = append(reasons, reason)
+	}
+	r.Reasons = reasons
+	return r
+}
+
+func roundCoordinate(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
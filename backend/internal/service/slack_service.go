@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/i18n"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+var (
+	errSlackPollNotFound   = &model.Error{Kind: model.ErrNotFound, Message: "找不到投票"}
+	errSlackNotPollCreator = &model.Error{Kind: model.ErrForbidden, Message: "只有發起投票的人可以結束投票"}
+)
+
+// SlackPoster 發送、更新與回覆 Slack 訊息
+type SlackPoster interface {
+	PostMessage(ctx context.Context, channel, text string, blocks []map[string]any) (string, error)
+	UpdateMessage(ctx context.Context, channel, ts, text string, blocks []map[string]any) error
+	Respond(ctx context.Context, responseURL string, message map[string]any) error
+}
+
+const (
+	slackPollCandidates   = 3
+	slackChannelKeyPrefix = "slack_channel:"
+	slackPollKeyPrefix    = "slack_poll:"
+
+	// 按鈕的 action_id，同一則訊息中需唯一，投票按鈕加上序號
+	slackActionVotePrefix = "vote_"
+	slackActionClose      = "close_poll"
+
+	// 背景作業（建立投票、更新訊息）的時限
+	slackRequestTimeout = 30 * time.Second
+)
+
+// SlackService 處理 /what2eat 指令與午餐投票，投票邏輯沿用投票房間
+type SlackService struct {
+	roomService      *RoomService
+	poster           SlackPoster
+	store            repository.Store
+	cfg              config.SlackConfig
+	channelLocations map[string]model.Location
+	defaultLocation  *model.Location
+
+	// 投票對應（Slack 使用者 → 房間參與者）為讀取後修改再寫回，需序列化
+	mu sync.Mutex
+}
+
+func NewSlackService(roomService *RoomService, poster SlackPoster, store repository.Store, cfg config.SlackConfig) *SlackService {
+	s := &SlackService{
+		roomService:      roomService,
+		poster:           poster,
+		store:            store,
+		cfg:              cfg,
+		channelLocations: make(map[string]model.Location),
+	}
+
+	for _, entry := range strings.Split(cfg.ChannelLocations, ";") {
+		channel, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			continue
+		}
+		location, err := parseLatLng(value)
+		if err != nil {
+			fmt.Printf("警告: 無法解析頻道 %s 的位置 %q: %v\n", channel, value, err)
+			continue
+		}
+		s.channelLocations[strings.TrimSpace(channel)] = location
+	}
+	if cfg.DefaultLocation != "" {
+		if location, err := parseLatLng(cfg.DefaultLocation); err == nil {
+			s.defaultLocation = &location
+		} else {
+			fmt.Printf("警告: 無法解析 Slack 預設位置 %q: %v\n", cfg.DefaultLocation, err)
+		}
+	}
+
+	s.resumePolls()
+	return s
+}
+
+// HandleCommand 處理 /what2eat 指令，返回立即回覆的訊息
+// 搜尋餐廳可能超過 Slack 的 3 秒時限，因此投票在背景建立後再發送到頻道
+func (s *SlackService) HandleCommand(cmd model.SlackCommand) map[string]any {
+	text := strings.TrimSpace(cmd.Text)
+	fields := strings.Fields(text)
+
+	if len(fields) > 0 && (fields[0] == "location" || fields[0] == "位置") {
+		return s.handleLocationCommand(cmd.ChannelID, strings.Join(fields[1:], ""))
+	}
+	if text == "help" || text == "說明" {
+		return slackEphemeral(slackHelpText)
+	}
+
+	restaurantType, ok := parseCuisineCommand(text)
+	if text != "" && !ok {
+		return slackEphemeral(fmt.Sprintf("不認識的料理類型「%s」\n%s", text, slackHelpText))
+	}
+
+	location, found := s.channelLocation(cmd.ChannelID)
+	if !found {
+		return slackEphemeral("此頻道尚未設定辦公室位置，請先輸入 `/what2eat location 25.0330,121.5654`")
+	}
+
+	go s.startPoll(cmd, location, restaurantType)
+	return slackEphemeral("正在幫大家找餐廳，請稍候… 🍱")
+}
+
+// HandleInteraction 處理投票按鈕的回呼，訊息內容由投票監看程序更新
+func (s *SlackService) HandleInteraction(ctx context.Context, interaction model.SlackInteraction) error {
+	if interaction.Type != "block_actions" {
+		return nil
+	}
+
+	for _, action := range interaction.Actions {
+		var err error
+		switch {
+		case strings.HasPrefix(action.ActionID, slackActionVotePrefix):
+			err = s.vote(interaction, action.Value)
+		case action.ActionID == slackActionClose:
+			err = s.closePoll(interaction)
+		default:
+			continue
+		}
+
+		if err != nil {
+			s.respondError(ctx, interaction.ResponseURL, err)
+		}
+	}
+	return nil
+}
+
+func (s *SlackService) handleLocationCommand(channelID, value string) map[string]any {
+	if value == "" {
+		if location, found := s.channelLocation(channelID); found {
+			return slackEphemeral(fmt.Sprintf("此頻道的辦公室位置：%.4f, %.4f", location.Lat, location.Lng))
+		}
+		return slackEphemeral("此頻道尚未設定辦公室位置，請輸入 `/what2eat location 緯度,經度`")
+	}
+
+	location, err := parseLatLng(value)
+	if err != nil {
+		return slackEphemeral("無效的位置格式，請輸入 `/what2eat location 25.0330,121.5654`")
+	}
+	if err := s.store.Put(slackChannelKeyPrefix+channelID, location); err != nil {
+		return slackEphemeral("無法保存頻道位置，請稍後再試")
+	}
+
+	fmt.Printf("Slack 頻道 %s 的辦公室位置設為 [%.4f, %.4f]\n", channelID, location.Lat, location.Lng)
+	return slackInChannel(fmt.Sprintf("已將此頻道的辦公室位置設為 %.4f, %.4f 📍", location.Lat, location.Lng))
+}
+
+// 依序使用頻道中設定的位置、環境變數中的頻道位置、預設位置
+func (s *SlackService) channelLocation(channelID string) (model.Location, bool) {
+	var location model.Location
+	if found, err := s.store.Get(slackChannelKeyPrefix+channelID, &location); err == nil && found {
+		return location, true
+	}
+	if location, found := s.channelLocations[channelID]; found {
+		return location, true
+	}
+	if s.defaultLocation != nil {
+		return *s.defaultLocation, true
+	}
+	return model.Location{}, false
+}
+
+// 建立投票房間並將投票訊息發送到頻道
+func (s *SlackService) startPoll(cmd model.SlackCommand, location model.Location, restaurantType string) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackRequestTimeout)
+	defer cancel()
+
+	view, err := s.roomService.CreateRoom(ctx, CreateRoomRequest{
+		Query: model.RoomQuery{
+			Lat:            location.Lat,
+			Lng:            location.Lng,
+			RestaurantType: restaurantType,
+			Budget:         -1,
+		},
+		PoolSize: slackPollCandidates,
+		Deadline: s.cfg.PollDuration,
+		Language: i18n.LangZhTW,
+		Creator:  slackRoomCreator(cmd.UserID),
+	})
+	if err != nil {
+		fmt.Printf("Slack 建立投票失敗: %v\n", err)
+		message := "搜尋餐廳時發生錯誤，請稍後再試 🙏"
+		if errors.Is(err, ErrNoCandidates) {
+			message = "附近找不到符合條件的餐廳，換個類型試試看？"
+		}
+		if cmd.ResponseURL != "" {
+			if err := s.poster.Respond(ctx, cmd.ResponseURL, slackEphemeral(message)); err != nil {
+				fmt.Printf("Slack 回覆失敗: %v\n", err)
+			}
+		}
+		return
+	}
+
+	poll := model.SlackPoll{
+		RoomID:         view.ID,
+		ChannelID:      cmd.ChannelID,
+		CreatedBy:      cmd.UserID,
+		RestaurantType: restaurantType,
+		Participants:   make(map[string]string),
+		CreatedAt:      time.Now(),
+	}
+
+	poll.MessageTS, err = s.poster.PostMessage(ctx, poll.ChannelID, "午餐投票開始！", pollBlocks(poll, *view))
+	if err != nil {
+		fmt.Printf("Slack 發送投票訊息失敗: %v\n", err)
+		return
+	}
+
+	s.mu.Lock()
+	err = s.savePoll(poll)
+	s.mu.Unlock()
+	if err != nil {
+		fmt.Printf("警告: 無法保存 Slack 投票: %v\n", err)
+	}
+
+	fmt.Printf("Slack 頻道 %s 開始午餐投票 (房間 %s)\n", poll.ChannelID, poll.RoomID)
+	go s.watchPoll(poll)
+}
+
+func (s *SlackService) vote(interaction model.SlackInteraction, placeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	poll, err := s.loadPoll(interaction.Channel.ID, interaction.Message.TS)
+	if err != nil {
+		return err
+	}
+
+	// 第一次投票時以 Slack 名稱加入房間
+	participantID, joined := poll.Participants[interaction.User.ID]
+	if !joined {
+		name := interaction.User.Name
+		if name == "" {
+			name = interaction.User.Username
+		}
+		if name == "" {
+			name = interaction.User.ID
+		}
+
+		if participantID, _, err = s.roomService.Join(poll.RoomID, name); err != nil {
+			return err
+		}
+		poll.Participants[interaction.User.ID] = participantID
+		if err := s.savePoll(*poll); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.roomService.Vote(poll.RoomID, participantID, placeID)
+	return err
+}
+
+func (s *SlackService) closePoll(interaction model.SlackInteraction) error {
+	s.mu.Lock()
+	poll, err := s.loadPoll(interaction.Channel.ID, interaction.Message.TS)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if interaction.User.ID != poll.CreatedBy {
+		return errSlackNotPollCreator
+	}
+	_, err = s.roomService.Close(poll.RoomID, slackRoomCreator(poll.CreatedBy))
+	return err
+}
+
+// Slack 使用者在投票房間中的建立者 ID，與 API 使用者的 ID 區隔
+func slackRoomCreator(slackUserID string) string {
+	return "slack:" + slackUserID
+}
+
+// 監看投票房間：每次狀態變更時更新訊息，結束時公布結果
+func (s *SlackService) watchPoll(poll model.SlackPoll) {
+	_, events, unsubscribe, err := s.roomService.Subscribe(poll.RoomID)
+	if err != nil {
+		s.mu.Lock()
+		s.deletePoll(poll)
+		s.mu.Unlock()
+		return
+	}
+	defer unsubscribe()
+
+	for view := range events {
+		if view.Status == model.RoomStatusClosed {
+			break
+		}
+		s.updatePollMessage(poll, view)
+	}
+
+	// 事件可能因訂閱者處理過慢而略過，以房間的最終狀態公布結果
+	view, err := s.roomService.GetRoom(poll.RoomID)
+	if err != nil {
+		return
+	}
+	s.updatePollMessage(poll, *view)
+	s.announceWinner(poll, *view)
+
+	s.mu.Lock()
+	s.deletePoll(poll)
+	s.mu.Unlock()
+}
+
+func (s *SlackService) updatePollMessage(poll model.SlackPoll, view model.RoomView) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackRequestTimeout)
+	defer cancel()
+
+	if err := s.poster.UpdateMessage(ctx, poll.ChannelID, poll.MessageTS, "午餐投票", pollBlocks(poll, view)); err != nil {
+		fmt.Printf("Slack 更新投票訊息失敗: %v\n", err)
+	}
+}
+
+func (s *SlackService) announceWinner(poll model.SlackPoll, view model.RoomView) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackRequestTimeout)
+	defer cancel()
+
+	text := "午餐投票結束，沒有人投票 😢"
+	if view.Winner != nil {
+		votes := 0
+		for _, candidate := range view.Candidates {
+			if candidate.Restaurant.PlaceID == view.Winner.PlaceID {
+				votes = candidate.Votes
+			}
+		}
+		text = fmt.Sprintf("🎉 午餐投票結果：*<%s|%s>*（%d 票），出發吧！",
+			repository.GoogleMapsURL(view.Winner.Name, view.Winner.PlaceID), view.Winner.Name, votes)
+	}
+
+	if _, err := s.poster.PostMessage(ctx, poll.ChannelID, text, nil); err != nil {
+		fmt.Printf("Slack 公布投票結果失敗: %v\n", err)
+	}
+}
+
+func (s *SlackService) respondError(ctx context.Context, responseURL string, err error) {
+	message := err.Error()
+	if errors.Is(err, ErrRoomClosed) || errors.Is(err, ErrRoomNotFound) || errors.Is(err, errSlackPollNotFound) {
+		message = "此投票已結束"
+	}
+	if responseURL == "" {
+		return
+	}
+	if err := s.poster.Respond(ctx, responseURL, slackEphemeral(message)); err != nil {
+		fmt.Printf("Slack 回覆失敗: %v\n", err)
+	}
+}
+
+// 伺服器重啟後重新監看仍在進行中的投票
+func (s *SlackService) resumePolls() {
+	keys, err := s.store.Keys(slackPollKeyPrefix)
+	if err != nil {
+		fmt.Printf("警告: 無法載入 Slack 投票: %v\n", err)
+		return
+	}
+
+	for _, key := range keys {
+		var poll model.SlackPoll
+		if found, err := s.store.Get(key, &poll); err == nil && found {
+			go s.watchPoll(poll)
+		}
+	}
+}
+
+// 以下方法需持有 s.mu
+
+func (s *SlackService) loadPoll(channelID, messageTS string) (*model.SlackPoll, error) {
+	var poll model.SlackPoll
+	found, err := s.store.Get(slackPollKey(channelID, messageTS), &poll)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errSlackPollNotFound
+	}
+	if poll.Participants == nil {
+		poll.Participants = make(map[string]string)
+	}
+	return &poll, nil
+}
+
+func (s *SlackService) savePoll(poll model.SlackPoll) error {
+	return s.store.Put(slackPollKey(poll.ChannelID, poll.MessageTS), poll)
+}
+
+func (s *SlackService) deletePoll(poll model.SlackPoll) {
+	if err := s.store.Delete(slackPollKey(poll.ChannelID, poll.MessageTS)); err != nil {
+		fmt.Printf("警告: 無法刪除 Slack 投票: %v\n", err)
+	}
+}
+
+func slackPollKey(channelID, messageTS string) string {
+	return slackPollKeyPrefix + channelID + ":" + messageTS
+}
+
+const slackHelpText = "使用方式：\n" +
+	"• `/what2eat` 或 `/what2eat 日式` 發起午餐投票\n" +
+	"• `/what2eat location 25.0330,121.5654` 設定此頻道的辦公室位置"
+
+func slackEphemeral(text string) map[string]any {
+	return map[string]any{"response_type": "ephemeral", "text": text}
+}
+
+func slackInChannel(text string) map[string]any {
+	return map[string]any{"response_type": "in_channel", "text": text}
+}
+
+// 產生投票訊息的 Block Kit 內容
+func pollBlocks(poll model.SlackPoll, view model.RoomView) []map[string]any {
+	open := view.Status == model.RoomStatusOpen
+
+	label := poll.RestaurantType
+	if label == "" {
+		label = "不限類型"
+	}
+	header := fmt.Sprintf("*🍱 午餐投票*（%s）\n由 <@%s> 發起", label, poll.CreatedBy)
+	if open {
+		header += fmt.Sprintf("，<!date^%d^{time}|%s> 截止", view.Deadline.Unix(), view.Deadline.Format("15:04"))
+	} else {
+		header += "，投票已結束"
+	}
+
+	blocks := []map[string]any{slackSection(header), {"type": "divider"}}
+
+	for i, candidate := range view.Candidates {
+		r := candidate.Restaurant
+		text := fmt.Sprintf("*<%s|%s>*\n★ %.1f・%s", repository.GoogleMapsURL(r.Name, r.PlaceID), r.Name, r.Rating, r.Distance)
+		if r.PriceLevel > 0 {
+			text += "・" + strings.Repeat("$", r.PriceLevel)
+		}
+		text += fmt.Sprintf("\n🗳️ %d 票", candidate.Votes)
+		if view.Winner != nil && view.Winner.PlaceID == r.PlaceID {
+			text = "🏆 " + text
+		}
+
+		block := slackSection(text)
+		if open {
+			block["accessory"] = map[string]any{
+				"type":      "button",
+				"text":      map[string]any{"type": "plain_text", "text": "投這家"},
+				"action_id": slackActionVotePrefix + strconv.Itoa(i),
+				"value":     r.PlaceID,
+			}
+		}
+		blocks = append(blocks, block)
+	}
+
+	var voters []string
+	for _, participant := range view.Participants {
+		if participant.Voted {
+			voters = append(voters, participant.Name)
+		}
+	}
+	summary := "還沒有人投票"
+	if len(voters) > 0 {
+		summary = fmt.Sprintf("已投票（%d 人）：%s", len(voters), strings.Join(voters, "、"))
+	}
+	blocks = append(blocks, map[string]any{
+		"type":     "context",
+		"elements": []map[string]any{{"type": "mrkdwn", "text": summary}},
+	})
+
+	if open {
+		blocks = append(blocks, map[string]any{
+			"type": "actions",
+			"elements": []map[string]any{{
+				"type":      "button",
+				"text":      map[string]any{"type": "plain_text", "text": "結束投票"},
+				"style":     "danger",
+				"action_id": slackActionClose,
+				"value":     poll.RoomID,
+			}},
+		})
+	}
+
+	return blocks
+}
+
+func slackSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": text},
+	}
+}
+
+// 解析 "緯度,經度" 格式的位置
+func parseLatLng(value string) (model.Location, error) {
+	latText, lngText, found := strings.Cut(value, ",")
+	if !found {
+		return model.Location{}, fmt.Errorf("位置格式需為 緯度,經度")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	if err != nil || !finite(lat) || lat < -90 || lat > 90 {
+		return model.Location{}, fmt.Errorf("無效的緯度 %q", latText)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngText), 64)
+	if err != nil || !finite(lng) || lng < -180 || lng > 180 {
+		return model.Location{}, fmt.Errorf("無效的經度 %q", lngText)
+	}
+	return model.Location{Lat: lat, Lng: lng}, nil
+}
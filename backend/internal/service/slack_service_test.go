@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+	"what2eat-backend/internal/model"
+)
+
+func TestParseLatLng(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    model.Location
+		wantErr bool
+	}{
+		{name: "一般位置", value: "25.0330,121.5654", want: model.Location{Lat: 25.0330, Lng: 121.5654}},
+		{name: "含空白", value: " 25.0330 , 121.5654 ", want: model.Location{Lat: 25.0330, Lng: 121.5654}},
+		{name: "赤道與本初子午線", value: "0,0", want: model.Location{}},
+		{name: "缺少逗號", value: "25.0330 121.5654", wantErr: true},
+		{name: "緯度超出範圍", value: "91,121.5654", wantErr: true},
+		{name: "經度超出範圍", value: "25.0330,-181", wantErr: true},
+		{name: "NaN", value: "NaN,NaN", wantErr: true},
+		{name: "經度為 NaN", value: "25.0330,nan", wantErr: true},
+		{name: "Inf", value: "Inf,121.5654", wantErr: true},
+		{name: "負 Inf", value: "25.0330,-Infinity", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLatLng(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseLatLng(%q) = %v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("parseLatLng(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
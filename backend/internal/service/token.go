@@ -0,0 +1,73 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"what2eat-backend/internal/model"
+)
+
+var (
+	ErrInvalidToken = &model.Error{Kind: model.ErrUnauthorized, Message: "無效的身分權杖"}
+	ErrTokenExpired = &model.Error{Kind: model.ErrUnauthorized, Message: "身分權杖已過期"}
+)
+
+// TokenSigner 以 HMAC-SHA256 簽署與驗證權杖
+// 格式為 base64url(JSON 內容) + "." + base64url(簽章)
+type TokenSigner struct {
+	secret []byte
+}
+
+func NewTokenSigner(secret string) *TokenSigner {
+	return &TokenSigner{secret: []byte(secret)}
+}
+
+// Sign 簽署權杖
+func (t *TokenSigner) Sign(claims model.TokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("無法序列化權杖: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + t.signature(encoded), nil
+}
+
+// Verify 驗證簽章與有效期限，並確認權杖類型符合預期
+func (t *TokenSigner) Verify(token string, expectedTypes ...string) (*model.TokenClaims, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(t.signature(encoded))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims model.TokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.ExpiresAt > 0 && time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	for _, expected := range expectedTypes {
+		if claims.Type == expected {
+			return &claims, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+func (t *TokenSigner) signature(encoded string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
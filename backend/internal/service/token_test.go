@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"what2eat-backend/internal/model"
+)
+
+func tokenClaims(subject, tokenType string, ttl time.Duration, nonce string) model.TokenClaims {
+	now := time.Now()
+	claims := model.TokenClaims{Subject: subject, Type: tokenType, Nonce: nonce, IssuedAt: now.Unix()}
+	if ttl != 0 {
+		claims.ExpiresAt = now.Add(ttl).Unix()
+	}
+	return claims
+}
+
+func TestTokenSignerVerify(t *testing.T) {
+	signer := NewTokenSigner("secret")
+
+	sign := func(claims model.TokenClaims) string {
+		token, err := signer.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := sign(tokenClaims("anon_1", model.TokenTypeDevice, 0, ""))
+	payload, signature, _ := strings.Cut(valid, ".")
+	otherPayload, _, _ := strings.Cut(sign(tokenClaims("anon_2", model.TokenTypeDevice, 0, "")), ".")
+	foreign, _ := NewTokenSigner("other-secret").Sign(tokenClaims("anon_1", model.TokenTypeDevice, 0, ""))
+
+	tests := []struct {
+		name    string
+		token   string
+		types   []string
+		wantErr error
+	}{
+		{name: "有效且不會過期", token: valid, types: []string{model.TokenTypeDevice}},
+		{name: "符合其中一種類型", token: valid, types: []string{model.TokenTypeAccount, model.TokenTypeDevice}},
+		{name: "尚未過期", token: sign(tokenClaims("anon_1", model.TokenTypeMagicLink, time.Minute, "n")), types: []string{model.TokenTypeMagicLink}},
+		{name: "已過期", token: sign(tokenClaims("anon_1", model.TokenTypeMagicLink, -time.Minute, "n")), types: []string{model.TokenTypeMagicLink}, wantErr: ErrTokenExpired},
+		{name: "類型不符", token: valid, types: []string{model.TokenTypeAccount}, wantErr: ErrInvalidToken},
+		{name: "替換內容", token: otherPayload + "." + signature, types: []string{model.TokenTypeDevice}, wantErr: ErrInvalidToken},
+		{name: "竄改簽章", token: payload + "." + strings.Repeat("A", len(signature)), types: []string{model.TokenTypeDevice}, wantErr: ErrInvalidToken},
+		{name: "其他金鑰簽署", token: foreign, types: []string{model.TokenTypeDevice}, wantErr: ErrInvalidToken},
+		{name: "缺少簽章", token: payload, types: []string{model.TokenTypeDevice}, wantErr: ErrInvalidToken},
+		{name: "空字串", token: "", types: []string{model.TokenTypeDevice}, wantErr: ErrInvalidToken},
+		{name: "缺少使用者", token: sign(tokenClaims("", model.TokenTypeDevice, 0, "")), types: []string{model.TokenTypeDevice}, wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := signer.Verify(tt.token, tt.types...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && claims.Subject != "anon_1" {
+				t.Errorf("subject = %s, want anon_1", claims.Subject)
+			}
+		})
+	}
+}
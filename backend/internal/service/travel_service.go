@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/i18n"
+	"what2eat-backend/internal/model"
+	"what2eat-backend/internal/repository"
+)
+
+// TravelTimeProvider 計算起點到多個目的地的交通時間（Google Distance Matrix 或離線估算）
+// 返回的切片與 destinations 一一對應，找不到路線的目的地為 nil
+type TravelTimeProvider interface {
+	Name() string
+	TravelTimes(ctx context.Context, origin model.Location, destinations []model.Location, mode string) ([]*model.TravelTime, error)
+}
+
+// IsValidTravelMode 檢查交通方式是否支援
+func IsValidTravelMode(mode string) bool {
+	_, ok := travelEstimates[mode]
+	return ok
+}
+
+const (
+	// 呼叫交通時間服務的逾時，逾時後改用估算，不拖慢推薦回應
+	travelTimeout = 5 * time.Second
+	// 快取超過此數量時清除過期項目
+	travelCacheCleanupSize = 5000
+)
+
+// 離線估算的參數：直線距離乘上繞路係數，再以平均速度換算並加上固定的等候時間
+var travelEstimates = map[string]struct {
+	detourFactor float64
+	speedKmh     float64
+	overhead     time.Duration
+}{
+	model.TravelModeWalking: {detourFactor: 1.3, speedKmh: 4.8},
+	model.TravelModeDriving: {detourFactor: 1.4, speedKmh: 25, overhead: 3 * time.Minute},  // 市區車速，含找停車位
+	model.TravelModeTransit: {detourFactor: 1.3, speedKmh: 18, overhead: 10 * time.Minute}, // 含步行到站與候車
+}
+
+// HaversineEstimator 以直線距離估算交通時間，不需要網路，作為 Google 服務的備援
+type HaversineEstimator struct{}
+
+func NewHaversineEstimator() *HaversineEstimator {
+	return &HaversineEstimator{}
+}
+
+func (e *HaversineEstimator) Name() string {
+	return "estimate"
+}
+
+func (e *HaversineEstimator) TravelTimes(ctx context.Context, origin model.Location, destinations []model.Location, mode string) ([]*model.TravelTime, error) {
+	params, ok := travelEstimates[mode]
+	if !ok {
+		return nil, fmt.Errorf("不支援的交通方式: %s", mode)
+	}
+
+	results := make([]*model.TravelTime, len(destinations))
+	for i, destination := range destinations {
+		meters := repository.HaversineMeters(origin.Lat, origin.Lng, destination.Lat, destination.Lng) * params.detourFactor
+		seconds := meters/1000/params.speedKmh*3600 + params.overhead.Seconds()
+		results[i] = &model.TravelTime{
+			Mode:            mode,
+			DurationSeconds: int(math.Round(seconds)),
+			DistanceMeters:  int(math.Round(meters)),
+			Provider:        e.Name(),
+			Estimated:       true,
+		}
+	}
+	return results, nil
+}
+
+type travelCacheEntry struct {
+	travel    model.TravelTime
+	expiresAt time.Time
+}
+
+// TravelService 為最終推薦結果補上交通時間
+// 結果以「起點所在的格子 + 目的地」快取，同一區域的使用者共用；呼叫外部服務計入獨立的每日額度
+type TravelService struct {
+	provider TravelTimeProvider // nil 表示只使用離線估算
+	fallback TravelTimeProvider
+	counter  *CounterService
+	cfg      config.TravelConfig
+
+	mu    sync.Mutex
+	cache map[string]travelCacheEntry
+}
+
+func NewTravelService(provider, fallback TravelTimeProvider, counter *CounterService, cfg config.TravelConfig) *TravelService {
+	return &TravelService{
+		provider: provider,
+		fallback: fallback,
+		counter:  counter,
+		cfg:      cfg,
+		cache:    make(map[string]travelCacheEntry),
+	}
+}
+
+// Enrich 為餐廳補上交通時間，外部服務失敗或額度用盡時改用離線估算，不會讓推薦失敗
+func (s *TravelService) Enrich(ctx context.Context, lat, lng float64, restaurants []model.Restaurant, mode, lang string) {
+	if len(restaurants) == 0 {
+		return
+	}
+
+	// 以格子中心作為起點，同一格子內的請求結果一致，快取才有意義
+	origin := model.Location{
+		Lat: roundCoordinate(lat, s.cfg.CellDecimals),
+		Lng: roundCoordinate(lng, s.cfg.CellDecimals),
+	}
+
+	travels := make([]*model.TravelTime, len(restaurants))
+	keys := make([]string, len(restaurants))
+	var missing []int
+	for i, r := range restaurants {
+		// 沒有座標的餐廳無法計算
+		if r.Lat == 0 && r.Lng == 0 {
+			continue
+		}
+		keys[i] = s.cacheKey(origin, r.PlaceID, mode)
+		if cached, found := s.cached(keys[i]); found {
+			travels[i] = &cached
+			continue
+		}
+		missing = append(missing, i)
+	}
+
+	if len(missing) > 0 {
+		destinations := make([]model.Location, len(missing))
+		missingKeys := make([]string, len(missing))
+		for j, i := range missing {
+			destinations[j] = model.Location{Lat: restaurants[i].Lat, Lng: restaurants[i].Lng}
+			missingKeys[j] = keys[i]
+		}
+
+		results := s.lookup(ctx, origin, destinations, missingKeys, mode)
+		for j, i := range missing {
+			travels[i] = results[j]
+		}
+	}
+
+	for i := range restaurants {
+		if travels[i] == nil {
+			continue
+		}
+		travel := *travels[i]
+		travel.Message = formatTravelMessage(travel, lang)
+		restaurants[i].Travel = &travel
+	}
+}
+
+// 向外部服務查詢，失敗的部分以估算補上，成功的結果寫入快取
+func (s *TravelService) lookup(ctx context.Context, origin model.Location, destinations []model.Location, keys []string, mode string) []*model.TravelTime {
+	var results []*model.TravelTime
+	if s.provider != nil {
+		if _, _, err := s.counter.IncrementBy(len(destinations)); err != nil {
+			current, limit := s.counter.GetUsage()
+			fmt.Printf("交通時間額度已用盡 (%d/%d)，改用估算\n", current, limit)
+		} else {
+			ctx, cancel := context.WithTimeout(ctx, travelTimeout)
+			var err error
+			results, err = s.provider.TravelTimes(ctx, origin, destinations, mode)
+			cancel()
+
+			if err != nil {
+				fmt.Printf("交通時間查詢失敗，改用估算: %v\n", err)
+				s.counter.LogAPIRequest("distance_matrix", origin.Lat, origin.Lng, mode, false, err.Error())
+				results = nil
+			} else {
+				s.counter.LogAPIRequest("distance_matrix", origin.Lat, origin.Lng, mode, true, "")
+			}
+		}
+	}
+
+	// 找不到路線的目的地（如大眾運輸無班次）也以估算補上
+	var estimates []*model.TravelTime
+	if fallbackResults, err := s.fallback.TravelTimes(ctx, origin, destinations, mode); err == nil {
+		estimates = fallbackResults
+	}
+
+	merged := make([]*model.TravelTime, len(destinations))
+	for i := range destinations {
+		if i < len(results) && results[i] != nil {
+			merged[i] = results[i]
+			s.store(keys[i], *results[i])
+			continue
+		}
+		if i < len(estimates) {
+			merged[i] = estimates[i]
+		}
+	}
+	return merged
+}
+
+// 快取鍵：交通方式 + 起點格子 + 目的地餐廳
+func (s *TravelService) cacheKey(origin model.Location, placeID, mode string) string {
+	return fmt.Sprintf("%s:%.*f:%.*f:%s", mode, s.cfg.CellDecimals, origin.Lat, s.cfg.CellDecimals, origin.Lng, placeID)
+}
+
+func (s *TravelService) cached(key string) (model.TravelTime, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, found := s.cache[key]
+	if !found || time.Now().After(entry.expiresAt) {
+		return model.TravelTime{}, false
+	}
+	return entry.travel, true
+}
+
+func (s *TravelService) store(key string, travel model.TravelTime) {
+	if s.cfg.CacheTTL <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.cache) >= travelCacheCleanupSize {
+		for k, entry := range s.cache {
+			if now.After(entry.expiresAt) {
+				delete(s.cache, k)
+			}
+		}
+	}
+	s.cache[key] = travelCacheEntry{travel: travel, expiresAt: now.Add(s.cfg.CacheTTL)}
+}
+
+func formatTravelMessage(travel model.TravelTime, lang string) string {
+	minutes := max(int(math.Ceil(float64(travel.DurationSeconds)/60)), 1)
+	key := "travel." + travel.Mode
+	if travel.Estimated {
+		key += "_estimated"
+	}
+	return i18n.Translate(lang, key, map[string]string{"minutes": strconv.Itoa(minutes)})
+}
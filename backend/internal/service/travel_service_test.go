@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"what2eat-backend/internal/config"
+	"what2eat-backend/internal/model"
+)
+
+// 模擬 Distance Matrix：固定回傳 10 分鐘，noRoute 中的目的地找不到路線
+type fakeTravelProvider struct {
+	err     error
+	noRoute map[model.Location]bool
+	calls   int
+}
+
+func (p *fakeTravelProvider) Name() string {
+	return "google"
+}
+
+func (p *fakeTravelProvider) TravelTimes(ctx context.Context, origin model.Location, destinations []model.Location, mode string) ([]*model.TravelTime, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	results := make([]*model.TravelTime, len(destinations))
+	for i, destination := range destinations {
+		if p.noRoute[destination] {
+			continue
+		}
+		results[i] = &model.TravelTime{Mode: mode, DurationSeconds: 600, DistanceMeters: 800, Provider: p.Name()}
+	}
+	return results, nil
+}
+
+func TestTravelEnrichFallback(t *testing.T) {
+	b := model.Location{Lat: 25.0360, Lng: 121.5680}
+	tests := []struct {
+		name         string
+		provider     *fakeTravelProvider
+		dailyLimit   int
+		wantProvider map[string]string
+		wantCalls    int // 兩次 Enrich 後呼叫外部服務的次數，成功的結果會被快取
+	}{
+		{
+			name:         "外部服務成功",
+			provider:     &fakeTravelProvider{},
+			dailyLimit:   100,
+			wantProvider: map[string]string{"a": "google", "b": "google"},
+			wantCalls:    1,
+		},
+		{
+			name:         "外部服務失敗改用估算",
+			provider:     &fakeTravelProvider{err: errors.New("REQUEST_DENIED")},
+			dailyLimit:   100,
+			wantProvider: map[string]string{"a": "estimate", "b": "estimate"},
+			wantCalls:    2,
+		},
+		{
+			name:         "找不到路線的目的地改用估算",
+			provider:     &fakeTravelProvider{noRoute: map[model.Location]bool{b: true}},
+			dailyLimit:   100,
+			wantProvider: map[string]string{"a": "google", "b": "estimate"},
+			wantCalls:    2,
+		},
+		{
+			name:         "額度不足改用估算",
+			provider:     &fakeTravelProvider{},
+			dailyLimit:   1,
+			wantProvider: map[string]string{"a": "estimate", "b": "estimate"},
+			wantCalls:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 計數器會寫入 data 目錄
+			t.Chdir(t.TempDir())
+			cfg := config.TravelConfig{CacheTTL: time.Minute, CellDecimals: 3}
+			s := NewTravelService(tt.provider, NewHaversineEstimator(), NewNamedCounterService("travel", tt.dailyLimit), cfg)
+
+			for range 2 {
+				restaurants := []model.Restaurant{
+					{PlaceID: "a", Lat: 25.0340, Lng: 121.5660},
+					{PlaceID: "b", Lat: b.Lat, Lng: b.Lng},
+					{PlaceID: "no-location"},
+				}
+				s.Enrich(context.Background(), 25.0330, 121.5654, restaurants, model.TravelModeWalking, "en")
+
+				for _, r := range restaurants {
+					want := tt.wantProvider[r.PlaceID]
+					if want == "" {
+						if r.Travel != nil {
+							t.Errorf("%s: travel = %+v, want none", r.PlaceID, r.Travel)
+						}
+						continue
+					}
+					if r.Travel == nil {
+						t.Fatalf("%s: travel is nil", r.PlaceID)
+					}
+					if r.Travel.Provider != want || r.Travel.Estimated != (want == "estimate") {
+						t.Errorf("%s: travel = %+v, want provider %s", r.PlaceID, r.Travel, want)
+					}
+					if estimated := strings.HasPrefix(r.Travel.Message, "about "); estimated != r.Travel.Estimated {
+						t.Errorf("%s: message = %q, estimated = %v", r.PlaceID, r.Travel.Message, r.Travel.Estimated)
+					}
+				}
+			}
+
+			if tt.provider.calls != tt.wantCalls {
+				t.Errorf("provider calls = %d, want %d", tt.provider.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTravelEnrichWithoutProvider(t *testing.T) {
+	s := NewTravelService(nil, NewHaversineEstimator(), nil, config.TravelConfig{CellDecimals: 3})
+	restaurants := []model.Restaurant{{PlaceID: "a", Lat: 25.0420, Lng: 121.5654}}
+	s.Enrich(context.Background(), 25.0330, 121.5654, restaurants, model.TravelModeWalking, "zh-TW")
+
+	// 約 1 公里，乘上繞路係數 1.3，以時速 4.8 公里約 16.3 分鐘
+	travel := restaurants[0].Travel
+	if travel == nil || travel.Provider != "estimate" || !travel.Estimated || travel.Message != "步行約 17 分鐘" {
+		t.Errorf("travel = %+v", travel)
+	}
+}